@@ -3,9 +3,15 @@ package adapters
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
 
 	"telegram-leetcode-bot/internal/bot"
 	"telegram-leetcode-bot/internal/leetcode"
+	"telegram-leetcode-bot/internal/sandbox"
 	"telegram-leetcode-bot/internal/storage"
 )
 
@@ -25,12 +31,7 @@ func (p *leetCodeProvider) RandomQuestion(ctx context.Context, seen map[string]s
 		}
 		return bot.Question{}, err
 	}
-	return bot.Question{
-		Slug:       q.Slug,
-		Title:      q.Title,
-		Difficulty: q.Difficulty,
-		URL:        q.URL,
-	}, nil
+	return mapLeetCodeQuestion(q), nil
 }
 
 func (p *leetCodeProvider) AllQuestions(ctx context.Context) ([]bot.Question, error) {
@@ -41,58 +42,284 @@ func (p *leetCodeProvider) AllQuestions(ctx context.Context) ([]bot.Question, er
 
 	out := make([]bot.Question, 0, len(all))
 	for _, q := range all {
-		out = append(out, bot.Question{
+		out = append(out, mapLeetCodeQuestion(q))
+	}
+	return out, nil
+}
+
+func (p *leetCodeProvider) QuestionDetail(ctx context.Context, slug string) (bot.QuestionDetail, error) {
+	detail, err := p.client.QuestionDetail(ctx, slug)
+	if err != nil {
+		return bot.QuestionDetail{}, err
+	}
+
+	similar := make([]bot.Question, 0, len(detail.SimilarQuestions))
+	for _, q := range detail.SimilarQuestions {
+		similar = append(similar, bot.Question{
 			Slug:       q.Slug,
 			Title:      q.Title,
 			Difficulty: q.Difficulty,
-			URL:        q.URL,
+			URL:        "https://leetcode.com/problems/" + q.Slug + "/",
 		})
 	}
+	return bot.QuestionDetail{
+		Slug:             detail.Slug,
+		Content:          detail.Content,
+		Constraints:      detail.Constraints,
+		Snippets:         detail.Snippets,
+		ExampleTestcases: detail.ExampleTestcases,
+		Hints:            detail.Hints,
+		SimilarQuestions: similar,
+		TopicTags:        detail.TopicTags,
+		AcceptanceRate:   detail.AcceptanceRate,
+		Likes:            detail.Likes,
+		Dislikes:         detail.Dislikes,
+	}, nil
+}
+
+func mapLeetCodeQuestion(q leetcode.Question) bot.Question {
+	return bot.Question{
+		Slug:       q.Slug,
+		Title:      q.Title,
+		Difficulty: q.Difficulty,
+		URL:        q.URL,
+		Tags:       q.Tags,
+	}
+}
+
+// NewCatalogProvider serves questions from an offline snapshot. Question
+// details carry only the snapshot's statement.
+func NewCatalogProvider(catalog *leetcode.Catalog) bot.QuestionProvider {
+	return &catalogProvider{catalog: catalog}
+}
+
+type catalogProvider struct {
+	catalog *leetcode.Catalog
+}
+
+func (p *catalogProvider) RandomQuestion(_ context.Context, seen map[string]struct{}) (bot.Question, error) {
+	q, err := p.catalog.RandomQuestion(seen)
+	if err != nil {
+		if errors.Is(err, leetcode.ErrNoUnseenQuestions) {
+			return bot.Question{}, bot.ErrNoUnseenQuestions
+		}
+		return bot.Question{}, err
+	}
+	return mapLeetCodeQuestion(q), nil
+}
+
+func (p *catalogProvider) AllQuestions(_ context.Context) ([]bot.Question, error) {
+	all := p.catalog.AllQuestions()
+	out := make([]bot.Question, 0, len(all))
+	for _, q := range all {
+		out = append(out, mapLeetCodeQuestion(q))
+	}
 	return out, nil
 }
 
-func (p *leetCodeProvider) QuestionPrompt(ctx context.Context, slug string) (string, error) {
-	return p.client.QuestionPrompt(ctx, slug)
+func (p *catalogProvider) QuestionDetail(_ context.Context, slug string) (bot.QuestionDetail, error) {
+	entry, ok := p.catalog.Entry(slug)
+	if !ok {
+		return bot.QuestionDetail{}, fmt.Errorf("question %q is not in the catalog", slug)
+	}
+	if entry.Statement == "" {
+		return bot.QuestionDetail{}, fmt.Errorf("catalog has no statement for %q", slug)
+	}
+	return bot.QuestionDetail{Slug: entry.Slug, Content: entry.Statement}, nil
+}
+
+// fallbackRetryAfter is how long a failing provider is skipped before the
+// chain tries it again, so an outage does not cost a timeout per message.
+const fallbackRetryAfter = 5 * time.Minute
+
+// NewFallbackProvider asks providers in order and moves to the next one when
+// a provider fails. Running out of unseen questions is an answer, not a
+// failure, and is returned as is.
+func NewFallbackProvider(logger *log.Logger, providers ...bot.QuestionProvider) bot.QuestionProvider {
+	return &fallbackProvider{
+		logger:    logger,
+		providers: providers,
+		failedAt:  make([]time.Time, len(providers)),
+		nowFn:     time.Now,
+	}
+}
+
+type fallbackProvider struct {
+	logger    *log.Logger
+	providers []bot.QuestionProvider
+	nowFn     func() time.Time
+
+	mu       sync.Mutex
+	failedAt []time.Time
+}
+
+func (p *fallbackProvider) RandomQuestion(ctx context.Context, seen map[string]struct{}) (bot.Question, error) {
+	var q bot.Question
+	err := p.try("random question", true, func(provider bot.QuestionProvider) error {
+		var err error
+		q, err = provider.RandomQuestion(ctx, seen)
+		return err
+	})
+	return q, err
+}
+
+func (p *fallbackProvider) AllQuestions(ctx context.Context) ([]bot.Question, error) {
+	var all []bot.Question
+	err := p.try("question list", true, func(provider bot.QuestionProvider) error {
+		var err error
+		all, err = provider.AllQuestions(ctx)
+		return err
+	})
+	return all, err
+}
+
+func (p *fallbackProvider) QuestionDetail(ctx context.Context, slug string) (bot.QuestionDetail, error) {
+	var detail bot.QuestionDetail
+	err := p.try("question detail for "+slug, false, func(provider bot.QuestionProvider) error {
+		var err error
+		detail, err = provider.QuestionDetail(ctx, slug)
+		return err
+	})
+	return detail, err
+}
+
+// try runs call against each provider in turn. A provider whose question list
+// failed within fallbackRetryAfter is skipped unless it is the last one left;
+// a missing detail for one slug (markOnFailure false) does not count as an
+// outage.
+func (p *fallbackProvider) try(what string, markOnFailure bool, call func(bot.QuestionProvider) error) error {
+	var lastErr error
+	for i, provider := range p.providers {
+		last := i == len(p.providers)-1
+		if !last && p.coolingDown(i) {
+			continue
+		}
+		err := call(provider)
+		if err == nil || errors.Is(err, bot.ErrNoUnseenQuestions) {
+			return err
+		}
+		lastErr = err
+		if !last {
+			if markOnFailure {
+				p.markFailed(i)
+			}
+			p.logger.Printf("question provider %d failed for %s, trying the next one: %v", i+1, what, err)
+		}
+	}
+	return lastErr
+}
+
+func (p *fallbackProvider) coolingDown(i int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return !p.failedAt[i].IsZero() && p.nowFn().Sub(p.failedAt[i]) < fallbackRetryAfter
+}
+
+func (p *fallbackProvider) markFailed(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failedAt[i] = p.nowFn()
 }
 
 func NewFirestoreStateStore(store *storage.Store) bot.StateStore {
-	return &firestoreStateStore{store: store}
+	return NewStateStore(store)
 }
 
-type firestoreStateStore struct {
-	store *storage.Store
+func NewMemoryStateStore(store *storage.MemoryStore) bot.StateStore {
+	return NewStateStore(store)
 }
 
-func (s *firestoreStateStore) GetChatSettings(ctx context.Context, chatID int64) (bot.ChatSettings, error) {
-	item, err := s.store.GetChatSettings(ctx, chatID)
+func NewBoltStateStore(store *storage.BoltStore) bot.StateStore {
+	return NewStateStore(store)
+}
+
+func NewStateStore(backend storage.Backend) bot.StateStore {
+	return &stateStore{store: backend}
+}
+
+type stateStore struct {
+	store storage.Backend
+}
+
+func (s *stateStore) GetChatSettings(ctx context.Context, key bot.StateKey) (bot.ChatSettings, error) {
+	item, err := s.store.GetChatSettings(ctx, storage.StateKey(key))
 	if err != nil {
 		return bot.ChatSettings{}, err
 	}
 	return mapChatSettings(item), nil
 }
 
-func (s *firestoreStateStore) UpsertDailySettings(ctx context.Context, chatID int64, enabled bool, hhmm, tz string) error {
-	return s.store.UpsertDailySettings(ctx, chatID, enabled, hhmm, tz)
+func (s *stateStore) UpsertDailySettings(ctx context.Context, key bot.StateKey, enabled bool, hhmm, tz string) error {
+	return s.store.UpsertDailySettings(ctx, storage.StateKey(key), enabled, hhmm, tz)
+}
+
+func (s *stateStore) SetCurrentQuestion(ctx context.Context, key bot.StateKey, q bot.Question) error {
+	return s.store.SetCurrentQuestion(ctx, storage.StateKey(key), mapQuestionOut(q))
+}
+
+func (s *stateStore) ClearCurrentQuestion(ctx context.Context, key bot.StateKey) error {
+	return s.store.ClearCurrentQuestion(ctx, storage.StateKey(key))
+}
+
+func (s *stateStore) SetHintLevel(ctx context.Context, key bot.StateKey, slug string, level int) error {
+	return s.store.SetHintLevel(ctx, storage.StateKey(key), slug, level)
+}
+
+func (s *stateStore) SetTutorThread(ctx context.Context, key bot.StateKey, thread bot.TutorThread) error {
+	return s.store.SetTutorThread(ctx, storage.StateKey(key), mapTutorThreadOut(thread))
+}
+
+func (s *stateStore) SetMockSession(ctx context.Context, key bot.StateKey, session bot.MockSession) error {
+	return s.store.SetMockSession(ctx, storage.StateKey(key), storage.MockSession(session))
+}
+
+func (s *stateStore) ClearMockSession(ctx context.Context, key bot.StateKey) error {
+	return s.store.ClearMockSession(ctx, storage.StateKey(key))
 }
 
-func (s *firestoreStateStore) SetCurrentQuestion(ctx context.Context, chatID int64, q bot.Question) error {
-	return s.store.SetCurrentQuestion(ctx, chatID, mapQuestionOut(q))
+func (s *stateStore) ListMockSessions(ctx context.Context) ([]bot.ChatSettings, error) {
+	items, err := s.store.ListMockSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]bot.ChatSettings, 0, len(items))
+	for _, item := range items {
+		out = append(out, mapChatSettings(item))
+	}
+	return out, nil
+}
+
+func (s *stateStore) MarkDailySent(ctx context.Context, key bot.StateKey, day string) error {
+	return s.store.MarkDailySent(ctx, storage.StateKey(key), day)
+}
+
+func (s *stateStore) MarkWeeklySummarySent(ctx context.Context, key bot.StateKey, week string) error {
+	return s.store.MarkWeeklySummarySent(ctx, storage.StateKey(key), week)
+}
+
+func (s *stateStore) SetDifficultyPreference(ctx context.Context, key bot.StateKey, difficulty string) error {
+	return s.store.SetDifficultyPreference(ctx, storage.StateKey(key), difficulty)
 }
 
-func (s *firestoreStateStore) ClearCurrentQuestion(ctx context.Context, chatID int64) error {
-	return s.store.ClearCurrentQuestion(ctx, chatID)
+func (s *stateStore) SetDailyMode(ctx context.Context, key bot.StateKey, mode string) error {
+	return s.store.SetDailyMode(ctx, storage.StateKey(key), mode)
 }
 
-func (s *firestoreStateStore) MarkDailySent(ctx context.Context, chatID int64, day string) error {
-	return s.store.MarkDailySent(ctx, chatID, day)
+func (s *stateStore) SetStudyPlan(ctx context.Context, key bot.StateKey, name string) error {
+	return s.store.SetStudyPlan(ctx, storage.StateKey(key), name)
 }
 
-func (s *firestoreStateStore) MarkQuestionAnswered(ctx context.Context, chatID int64, q bot.Question) error {
-	return s.store.MarkQuestionAnswered(ctx, chatID, mapQuestionOut(q))
+func (s *stateStore) DismissQuestion(ctx context.Context, key bot.StateKey, slug string) error {
+	return s.store.DismissQuestion(ctx, storage.StateKey(key), slug)
 }
 
-func (s *firestoreStateStore) DeleteAnsweredQuestion(ctx context.Context, chatID int64, slug string) error {
-	if err := s.store.DeleteAnsweredQuestion(ctx, chatID, slug); err != nil {
+func (s *stateStore) MarkQuestionAnswered(ctx context.Context, key bot.StateKey, q bot.Question) error {
+	return s.store.MarkQuestionAnswered(ctx, storage.StateKey(key), mapQuestionOut(q))
+}
+
+func (s *stateStore) DeleteAnsweredQuestion(ctx context.Context, key bot.StateKey, slug string) error {
+	if err := s.store.DeleteAnsweredQuestion(ctx, storage.StateKey(key), slug); err != nil {
 		if errors.Is(err, storage.ErrAnsweredQuestionNotFound) {
 			return bot.ErrAnsweredQuestionNotFound
 		}
@@ -101,23 +328,70 @@ func (s *firestoreStateStore) DeleteAnsweredQuestion(ctx context.Context, chatID
 	return nil
 }
 
-func (s *firestoreStateStore) AddServedQuestion(ctx context.Context, chatID int64, q bot.Question) error {
-	return s.store.AddServedQuestion(ctx, chatID, mapQuestionOut(q))
+func (s *stateStore) RecordAnswerAttempt(ctx context.Context, key bot.StateKey, attempt bot.AnswerAttempt) error {
+	return s.store.RecordAnswerAttempt(ctx, storage.StateKey(key), storage.AnswerAttempt(attempt))
 }
 
-func (s *firestoreStateStore) RemoveServedQuestion(ctx context.Context, chatID int64, slug string) error {
-	return s.store.RemoveServedQuestion(ctx, chatID, slug)
+func (s *stateStore) ListAnswerAttempts(ctx context.Context, key bot.StateKey, slug string, limit int) ([]bot.AnswerAttempt, error) {
+	items, err := s.store.ListAnswerAttempts(ctx, storage.StateKey(key), slug, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]bot.AnswerAttempt, 0, len(items))
+	for _, item := range items {
+		out = append(out, bot.AnswerAttempt(item))
+	}
+	return out, nil
 }
 
-func (s *firestoreStateStore) SeenQuestionSet(ctx context.Context, chatID int64) (map[string]struct{}, error) {
-	return s.store.SeenQuestionSet(ctx, chatID)
+func (s *stateStore) RecordGroupSolve(ctx context.Context, chatID int64, solve bot.GroupSolve) error {
+	return s.store.RecordGroupSolve(ctx, chatID, storage.GroupSolve(solve))
 }
 
-func (s *firestoreStateStore) ResetServedQuestions(ctx context.Context, chatID int64) error {
-	return s.store.ResetServedQuestions(ctx, chatID)
+func (s *stateStore) ListGroupSolves(ctx context.Context, chatID int64, since time.Time) ([]bot.GroupSolve, error) {
+	items, err := s.store.ListGroupSolves(ctx, chatID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]bot.GroupSolve, 0, len(items))
+	for _, item := range items {
+		out = append(out, bot.GroupSolve(item))
+	}
+	return out, nil
 }
 
-func (s *firestoreStateStore) ListDailyEnabledChats(ctx context.Context) ([]bot.ChatSettings, error) {
+func (s *stateStore) ListActiveGroups(ctx context.Context, since time.Time) ([]bot.ChatSettings, error) {
+	items, err := s.store.ListActiveGroups(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]bot.ChatSettings, 0, len(items))
+	for _, item := range items {
+		out = append(out, mapChatSettings(item))
+	}
+	return out, nil
+}
+
+func (s *stateStore) AddServedQuestion(ctx context.Context, key bot.StateKey, q bot.Question) error {
+	return s.store.AddServedQuestion(ctx, storage.StateKey(key), mapQuestionOut(q))
+}
+
+func (s *stateStore) RemoveServedQuestion(ctx context.Context, key bot.StateKey, slug string) error {
+	return s.store.RemoveServedQuestion(ctx, storage.StateKey(key), slug)
+}
+
+func (s *stateStore) SeenQuestionSet(ctx context.Context, key bot.StateKey) (map[string]struct{}, error) {
+	return s.store.SeenQuestionSet(ctx, storage.StateKey(key))
+}
+
+func (s *stateStore) ResetServedQuestions(ctx context.Context, key bot.StateKey) error {
+	return s.store.ResetServedQuestions(ctx, storage.StateKey(key))
+}
+
+func (s *stateStore) ListDailyEnabledChats(ctx context.Context) ([]bot.ChatSettings, error) {
 	items, err := s.store.ListDailyEnabledChats(ctx)
 	if err != nil {
 		return nil, err
@@ -130,12 +404,186 @@ func (s *firestoreStateStore) ListDailyEnabledChats(ctx context.Context) ([]bot.
 	return out, nil
 }
 
-func (s *firestoreStateStore) ListAnsweredQuestions(ctx context.Context, chatID int64, limit int) ([]bot.AnsweredQuestion, error) {
-	items, err := s.store.ListAnsweredQuestions(ctx, chatID, limit)
+func (s *stateStore) ListAnsweredQuestions(ctx context.Context, key bot.StateKey, limit int) ([]bot.AnsweredQuestion, error) {
+	items, err := s.store.ListAnsweredQuestions(ctx, storage.StateKey(key), limit)
 	if err != nil {
 		return nil, err
 	}
 
+	return mapAnsweredQuestions(items), nil
+}
+
+func (s *stateStore) ListAllAnsweredQuestions(ctx context.Context, key bot.StateKey) ([]bot.AnsweredQuestion, error) {
+	items, err := s.store.ListAllAnsweredQuestions(ctx, storage.StateKey(key))
+	if err != nil {
+		return nil, err
+	}
+	return mapAnsweredQuestions(items), nil
+}
+
+func (s *stateStore) ListReviewQueue(ctx context.Context, key bot.StateKey, limit int) ([]bot.AnsweredQuestion, error) {
+	items, err := s.store.ListReviewQueue(ctx, storage.StateKey(key), limit)
+	if err != nil {
+		return nil, err
+	}
+	return mapAnsweredQuestions(items), nil
+}
+
+func (s *stateStore) GetReviewState(ctx context.Context, key bot.StateKey, slug string) (bot.ReviewState, error) {
+	state, err := s.store.GetReviewState(ctx, storage.StateKey(key), slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrAnsweredQuestionNotFound) {
+			return bot.ReviewState{}, bot.ErrAnsweredQuestionNotFound
+		}
+		return bot.ReviewState{}, err
+	}
+	return bot.ReviewState(state), nil
+}
+
+func (s *stateStore) UpdateReviewState(ctx context.Context, key bot.StateKey, slug string, state bot.ReviewState) error {
+	if err := s.store.UpdateReviewState(ctx, storage.StateKey(key), slug, storage.ReviewState(state)); err != nil {
+		if errors.Is(err, storage.ErrAnsweredQuestionNotFound) {
+			return bot.ErrAnsweredQuestionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *stateStore) GetAnsweredQuestion(ctx context.Context, key bot.StateKey, slug string) (bot.Question, error) {
+	item, err := s.store.GetAnsweredQuestion(ctx, storage.StateKey(key), slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrAnsweredQuestionNotFound) {
+			return bot.Question{}, bot.ErrAnsweredQuestionNotFound
+		}
+		return bot.Question{}, err
+	}
+
+	return mapQuestionIn(item), nil
+}
+
+func (s *stateStore) SaveInterviewSession(ctx context.Context, key bot.StateKey, session bot.InterviewSession) error {
+	return s.store.SaveInterviewSession(ctx, storage.StateKey(key), mapInterviewSessionOut(session))
+}
+
+func (s *stateStore) LatestInterviewSession(ctx context.Context, key bot.StateKey) (bot.InterviewSession, error) {
+	item, err := s.store.LatestInterviewSession(ctx, storage.StateKey(key))
+	if err != nil {
+		if errors.Is(err, storage.ErrInterviewSessionNotFound) {
+			return bot.InterviewSession{}, bot.ErrInterviewSessionNotFound
+		}
+		return bot.InterviewSession{}, err
+	}
+	return mapInterviewSessionIn(item), nil
+}
+
+func (s *stateStore) SaveQuestionList(ctx context.Context, chatID int64, list bot.QuestionList) error {
+	return s.store.SaveQuestionList(ctx, chatID, mapQuestionListOut(list))
+}
+
+func (s *stateStore) GetQuestionList(ctx context.Context, chatID int64, name string) (bot.QuestionList, error) {
+	item, err := s.store.GetQuestionList(ctx, chatID, name)
+	if err != nil {
+		if errors.Is(err, storage.ErrQuestionListNotFound) {
+			return bot.QuestionList{}, bot.ErrQuestionListNotFound
+		}
+		return bot.QuestionList{}, err
+	}
+	return mapQuestionListIn(item), nil
+}
+
+func (s *stateStore) ListQuestionLists(ctx context.Context, chatID int64) ([]bot.QuestionList, error) {
+	items, err := s.store.ListQuestionLists(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bot.QuestionList, 0, len(items))
+	for _, item := range items {
+		out = append(out, mapQuestionListIn(item))
+	}
+	return out, nil
+}
+
+func (s *stateStore) SaveFormattedPrompt(ctx context.Context, prompt bot.FormattedPrompt) error {
+	return s.store.SaveFormattedPrompt(ctx, storage.FormattedPrompt(prompt))
+}
+
+func (s *stateStore) GetFormattedPrompt(ctx context.Context, key string) (bot.FormattedPrompt, error) {
+	item, err := s.store.GetFormattedPrompt(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrFormattedPromptNotFound) {
+			return bot.FormattedPrompt{}, bot.ErrFormattedPromptNotFound
+		}
+		return bot.FormattedPrompt{}, err
+	}
+	return bot.FormattedPrompt(item), nil
+}
+
+func mapQuestionListIn(in storage.QuestionList) bot.QuestionList {
+	out := bot.QuestionList{
+		Name:      in.Name,
+		CreatedAt: in.CreatedAt,
+		UpdatedAt: in.UpdatedAt,
+	}
+	for _, q := range in.Questions {
+		out.Questions = append(out.Questions, mapQuestionIn(q))
+	}
+	return out
+}
+
+func mapQuestionListOut(in bot.QuestionList) storage.QuestionList {
+	out := storage.QuestionList{
+		Name:      in.Name,
+		CreatedAt: in.CreatedAt,
+		UpdatedAt: in.UpdatedAt,
+	}
+	for _, q := range in.Questions {
+		out.Questions = append(out.Questions, mapQuestionOut(q))
+	}
+	return out
+}
+
+func mapInterviewSessionIn(in storage.InterviewSession) bot.InterviewSession {
+	out := bot.InterviewSession{
+		ID:         in.ID,
+		Difficulty: in.Difficulty,
+		Questions:  make([]bot.SessionQuestion, 0, len(in.Questions)),
+		Current:    in.Current,
+		StartedAt:  in.StartedAt,
+		FinishedAt: in.FinishedAt,
+	}
+	for _, q := range in.Questions {
+		out.Questions = append(out.Questions, bot.SessionQuestion{
+			Question:  mapQuestionIn(q.QuestionRef),
+			Outcome:   q.Outcome,
+			BestScore: q.BestScore,
+			Attempts:  q.Attempts,
+		})
+	}
+	return out
+}
+
+func mapInterviewSessionOut(in bot.InterviewSession) storage.InterviewSession {
+	out := storage.InterviewSession{
+		ID:         in.ID,
+		Difficulty: in.Difficulty,
+		Questions:  make([]storage.SessionQuestion, 0, len(in.Questions)),
+		Current:    in.Current,
+		StartedAt:  in.StartedAt,
+		FinishedAt: in.FinishedAt,
+	}
+	for _, q := range in.Questions {
+		out.Questions = append(out.Questions, storage.SessionQuestion{
+			QuestionRef: mapQuestionOut(q.Question),
+			Outcome:     q.Outcome,
+			BestScore:   q.BestScore,
+			Attempts:    q.Attempts,
+		})
+	}
+	return out
+}
+
+func mapAnsweredQuestions(items []storage.AnsweredQuestion) []bot.AnsweredQuestion {
 	out := make([]bot.AnsweredQuestion, 0, len(items))
 	for _, item := range items {
 		out = append(out, bot.AnsweredQuestion{
@@ -148,39 +596,65 @@ func (s *firestoreStateStore) ListAnsweredQuestions(ctx context.Context, chatID
 			FirstAnsweredAt: item.FirstAnsweredAt,
 			LastAnsweredAt:  item.LastAnsweredAt,
 			Attempts:        item.Attempts,
+			Review: bot.ReviewState{
+				EaseFactor:   item.EaseFactor,
+				IntervalDays: item.IntervalDays,
+				Repetitions:  item.Repetitions,
+				DueAt:        item.DueAt,
+			},
 		})
 	}
-
-	return out, nil
-}
-
-func (s *firestoreStateStore) GetAnsweredQuestion(ctx context.Context, chatID int64, slug string) (bot.Question, error) {
-	item, err := s.store.GetAnsweredQuestion(ctx, chatID, slug)
-	if err != nil {
-		if errors.Is(err, storage.ErrAnsweredQuestionNotFound) {
-			return bot.Question{}, bot.ErrAnsweredQuestionNotFound
-		}
-		return bot.Question{}, err
-	}
-
-	return mapQuestionIn(item), nil
+	return out
 }
 
 func mapChatSettings(item storage.ChatSettings) bot.ChatSettings {
 	mapped := bot.ChatSettings{
 		ChatID:          item.ChatID,
+		UserID:          item.UserID,
 		DailyEnabled:    item.DailyEnabled,
 		DailyTime:       item.DailyTime,
 		Timezone:        item.Timezone,
 		LastDailySentOn: item.LastDailySentOn,
+		Difficulty:      item.Difficulty,
+		DailyMode:       item.DailyMode,
+
+		LastWeeklySummaryOn: item.LastWeeklySummaryOn,
+		HintLevel:           item.HintLevel,
+		HintSlug:            item.HintSlug,
+		StudyPlan:           item.StudyPlan,
+		DismissedSlug:       item.DismissedSlug,
 	}
 	if item.CurrentQuestion != nil {
 		q := mapQuestionIn(*item.CurrentQuestion)
 		mapped.CurrentQuestion = &q
 	}
+	if item.Mock != nil {
+		mock := bot.MockSession(*item.Mock)
+		mapped.Mock = &mock
+	}
+	if item.Tutor != nil {
+		thread := mapTutorThreadIn(*item.Tutor)
+		mapped.Tutor = &thread
+	}
 	return mapped
 }
 
+func mapTutorThreadIn(in storage.TutorThread) bot.TutorThread {
+	out := bot.TutorThread{Slug: in.Slug, Turns: make([]bot.TutorTurn, 0, len(in.Turns))}
+	for _, turn := range in.Turns {
+		out.Turns = append(out.Turns, bot.TutorTurn(turn))
+	}
+	return out
+}
+
+func mapTutorThreadOut(in bot.TutorThread) storage.TutorThread {
+	out := storage.TutorThread{Slug: in.Slug, Turns: make([]storage.TutorTurn, 0, len(in.Turns))}
+	for _, turn := range in.Turns {
+		out.Turns = append(out.Turns, storage.TutorTurn(turn))
+	}
+	return out
+}
+
 func mapQuestionIn(in storage.QuestionRef) bot.Question {
 	return bot.Question{
 		Slug:       in.Slug,
@@ -198,3 +672,62 @@ func mapQuestionOut(in bot.Question) storage.QuestionRef {
 		URL:        in.URL,
 	}
 }
+
+// NewCodeRunner runs answer code in runner against the examples client
+// fetches for the question.
+func NewCodeRunner(client *leetcode.Client, runner *sandbox.Runner) bot.CodeRunner {
+	return &codeRunner{client: client, runner: runner}
+}
+
+type codeRunner struct {
+	client *leetcode.Client
+	runner *sandbox.Runner
+}
+
+func (r *codeRunner) RunExamples(ctx context.Context, q bot.Question, language, code string) ([]bot.ExampleResult, error) {
+	examples, err := r.client.QuestionExamples(ctx, q.Slug)
+	if errors.Is(err, leetcode.ErrExamplesUnavailable) {
+		return nil, bot.ErrCodeRunUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fn := sandbox.Function{
+		Name:   examples.Function,
+		Params: make([]string, 0, len(examples.Params)),
+		Return: examples.Return,
+		GoStub: examples.Snippets["golang"],
+	}
+	for _, p := range examples.Params {
+		fn.Params = append(fn.Params, p.Type)
+	}
+	cases := make([]sandbox.Case, 0, len(examples.Cases))
+	for _, c := range examples.Cases {
+		cases = append(cases, sandbox.Case{Args: c.Args, Expected: c.Expected})
+	}
+
+	outcomes, err := r.runner.Run(ctx, sandbox.Language(language), code, fn, cases)
+	if errors.Is(err, sandbox.ErrUnsupported) {
+		return nil, bot.ErrCodeRunUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]bot.ExampleResult, 0, len(outcomes))
+	for _, o := range outcomes {
+		inputs := make([]string, 0, len(o.Args))
+		for i, arg := range o.Args {
+			inputs = append(inputs, examples.Params[i].Name+" = "+arg)
+		}
+		out = append(out, bot.ExampleResult{
+			Input:    strings.Join(inputs, ", "),
+			Expected: o.Expected,
+			Got:      o.Got,
+			Passed:   o.Passed,
+			Err:      o.Err,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package adapters_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"path/filepath"
+	"testing"
+
+	"telegram-leetcode-bot/internal/adapters"
+	"telegram-leetcode-bot/internal/bot"
+	"telegram-leetcode-bot/internal/leetcode"
+)
+
+type stubProvider struct {
+	question  bot.Question
+	detail    bot.QuestionDetail
+	err       error
+	detailErr error
+	calls     int
+}
+
+func (p *stubProvider) RandomQuestion(context.Context, map[string]struct{}) (bot.Question, error) {
+	p.calls++
+	return p.question, p.err
+}
+
+func (p *stubProvider) AllQuestions(context.Context) ([]bot.Question, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []bot.Question{p.question}, nil
+}
+
+func (p *stubProvider) QuestionDetail(context.Context, string) (bot.QuestionDetail, error) {
+	p.calls++
+	return p.detail, p.detailErr
+}
+
+func TestFallbackProviderUsesCatalogWhenLiveFails(t *testing.T) {
+	ctx := context.Background()
+	live := &stubProvider{err: errors.New("leetcode status 503")}
+	catalog := &leetcode.Catalog{Questions: []leetcode.CatalogEntry{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", Statement: "Find two numbers."},
+	}}
+	offline, err := writeAndLoad(t, catalog)
+	if err != nil {
+		t.Fatalf("load catalog: %v", err)
+	}
+	provider := adapters.NewFallbackProvider(log.New(bytes.NewBuffer(nil), "", 0), live, adapters.NewCatalogProvider(offline))
+
+	q, err := provider.RandomQuestion(ctx, nil)
+	if err != nil || q.Slug != "two-sum" {
+		t.Fatalf("RandomQuestion() = %+v, %v; want two-sum from the catalog", q, err)
+	}
+	if _, err := provider.AllQuestions(ctx); err != nil {
+		t.Fatalf("AllQuestions() error = %v", err)
+	}
+	if live.calls != 1 {
+		t.Fatalf("expected the failing live provider to be skipped after one failure, got %d calls", live.calls)
+	}
+
+	detail, err := provider.QuestionDetail(ctx, "two-sum")
+	if err != nil || detail.Content != "Find two numbers." {
+		t.Fatalf("QuestionDetail() = %+v, %v; want the catalog statement", detail, err)
+	}
+
+	if _, err := provider.RandomQuestion(ctx, map[string]struct{}{"two-sum": {}}); !errors.Is(err, bot.ErrNoUnseenQuestions) {
+		t.Fatalf("RandomQuestion() error = %v, want ErrNoUnseenQuestions", err)
+	}
+}
+
+func TestFallbackProviderKeepsLiveAfterMissingDetail(t *testing.T) {
+	ctx := context.Background()
+	live := &stubProvider{question: bot.Question{Slug: "two-sum"}, detailErr: errors.New(`question "gone" not found`)}
+	backup := &stubProvider{question: bot.Question{Slug: "3sum"}, detailErr: errors.New("no statement")}
+	provider := adapters.NewFallbackProvider(log.New(bytes.NewBuffer(nil), "", 0), live, backup)
+
+	if _, err := provider.QuestionDetail(ctx, "gone"); err == nil {
+		t.Fatalf("expected a detail error when every provider fails")
+	}
+	q, err := provider.RandomQuestion(ctx, nil)
+	if err != nil || q.Slug != "two-sum" {
+		t.Fatalf("RandomQuestion() = %+v, %v; want the live question", q, err)
+	}
+	if backup.calls != 1 {
+		t.Fatalf("expected the backup to be asked only for the missing detail, got %d calls", backup.calls)
+	}
+}
+
+func writeAndLoad(t *testing.T, catalog *leetcode.Catalog) (*leetcode.Catalog, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "questions.json")
+	if err := leetcode.WriteCatalog(path, catalog); err != nil {
+		return nil, err
+	}
+	return leetcode.LoadCatalog(path)
+}
@@ -0,0 +1,74 @@
+package adapters_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+
+	"telegram-leetcode-bot/internal/adapters"
+	"telegram-leetcode-bot/internal/bot"
+	"telegram-leetcode-bot/internal/storage"
+	"telegram-leetcode-bot/internal/storage/storagetest"
+)
+
+const emulatorProjectID = "storagetest"
+
+func TestMemoryStateStoreConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) bot.StateStore {
+		return adapters.NewMemoryStateStore(storage.NewMemoryStore(storagetest.DefaultDailyTime, storagetest.DefaultTimezone))
+	})
+}
+
+func TestBoltStateStoreConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) bot.StateStore {
+		store, err := storage.OpenBoltStore(filepath.Join(t.TempDir(), "bot.db"), storagetest.DefaultDailyTime, storagetest.DefaultTimezone)
+		if err != nil {
+			t.Fatalf("open bolt store: %v", err)
+		}
+		t.Cleanup(func() { _ = store.Close() })
+		return adapters.NewBoltStateStore(store)
+	})
+}
+
+// TestFirestoreStateStoreConformance runs against the Firestore emulator when
+// FIRESTORE_EMULATOR_HOST is set, e.g. via `gcloud emulators firestore start`.
+func TestFirestoreStateStoreConformance(t *testing.T) {
+	host := os.Getenv("FIRESTORE_EMULATOR_HOST")
+	if host == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST not set")
+	}
+
+	storagetest.Run(t, func(t *testing.T) bot.StateStore {
+		clearFirestoreEmulator(t, host)
+
+		client, err := firestore.NewClient(context.Background(), emulatorProjectID)
+		if err != nil {
+			t.Fatalf("create firestore client: %v", err)
+		}
+		t.Cleanup(func() { _ = client.Close() })
+		return adapters.NewFirestoreStateStore(storage.NewStore(client, storagetest.DefaultDailyTime, storagetest.DefaultTimezone))
+	})
+}
+
+func clearFirestoreEmulator(t *testing.T, host string) {
+	t.Helper()
+
+	url := fmt.Sprintf("http://%s/emulator/v1/projects/%s/databases/(default)/documents", host, emulatorProjectID)
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		t.Fatalf("build emulator reset request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("reset firestore emulator: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("reset firestore emulator: status %d", resp.StatusCode)
+	}
+}
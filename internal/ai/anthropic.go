@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com/v1"
+	anthropicVersion        = "2023-06-01"
+	anthropicMaxTokens      = 1024
+)
+
+// AnthropicProvider speaks the Anthropic Messages API.
+type AnthropicProvider struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewAnthropicProvider(cfg ProviderConfig) (*AnthropicProvider, error) {
+	apiKey := strings.TrimSpace(cfg.APIKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("Anthropic API key is required")
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		return nil, fmt.Errorf("Anthropic model is required")
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		baseURL = defaultAnthropicBaseURL
+	}
+
+	return &AnthropicProvider{
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    baseURL,
+		httpClient: newHTTPClient(cfg.Timeout),
+	}, nil
+}
+
+// ModelFor returns the default model; requests may still override it.
+func (p *AnthropicProvider) ModelFor(Task) string {
+	return p.model
+}
+
+// Complete has no JSON mode to switch on, so a JSON request adds the
+// instruction to the system prompt instead.
+func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	system := req.System
+	if req.JSON {
+		system += "\nRespond with a single JSON object and nothing else."
+	}
+	payload := map[string]any{
+		"model":       modelFor(req, p.model),
+		"max_tokens":  anthropicMaxTokens,
+		"system":      system,
+		"messages":    []message{{Role: "user", Content: req.User}},
+		"temperature": 0.2,
+	}
+
+	header := http.Header{}
+	header.Set("x-api-key", p.apiKey)
+	header.Set("anthropic-version", anthropicVersion)
+
+	var parsed struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := postJSON(ctx, p.httpClient, "Anthropic", p.baseURL+"/messages", header, payload, &parsed); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, block := range parsed.Content {
+		if block.Type == "text" {
+			b.WriteString(block.Text)
+		}
+	}
+	content := strings.TrimSpace(b.String())
+	if content == "" {
+		return "", fmt.Errorf("Anthropic response content is empty")
+	}
+	return content, nil
+}
@@ -0,0 +1,213 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"telegram-leetcode-bot/internal/bot"
+)
+
+// Coach grades answers, writes hints, answers follow-ups and formats
+// statements with whichever chat model Provider talks to.
+type Coach struct {
+	provider Provider
+}
+
+func NewCoach(provider Provider) *Coach {
+	return &Coach{provider: provider}
+}
+
+// FormatModel names the model FormatQuestion asks first, or "" when the
+// provider cannot tell.
+func (c *Coach) FormatModel() string {
+	if namer, ok := c.provider.(modelNamer); ok {
+		return namer.ModelFor(TaskFormat)
+	}
+	return ""
+}
+
+func (c *Coach) ReviewAnswer(ctx context.Context, question bot.Question, req bot.ReviewRequest) (bot.AnswerReview, error) {
+	system := "You are a senior coding interview coach. Be concise, specific, and actionable."
+	user := fmt.Sprintf(
+		"Question: %s (%s)\nLink: %s\n%s\nCandidate answer:\n%s\n\nReturn valid JSON only with keys: score (integer 1-10), feedback (string), guidance (string).\nRules:\n- grade the latest answer; use the conversation only for context.\n- feedback: max 4 short bullet points.\n- guidance: exactly 3 numbered steps.\n- keep each line under 20 words.\n- do not include filler text.",
+		question.Title,
+		question.Difficulty,
+		question.URL,
+		tutorContextPrompt(req.Statement, req.Transcript),
+		req.Answer,
+	)
+
+	content, err := c.provider.Complete(ctx, CompletionRequest{Task: TaskReview, System: system, User: user, JSON: true})
+	if err != nil {
+		return bot.AnswerReview{}, err
+	}
+
+	var parsed struct {
+		Score    int    `json:"score"`
+		Feedback string `json:"feedback"`
+		Guidance string `json:"guidance"`
+	}
+	if err := decodeJSONReply(content, &parsed); err != nil {
+		return bot.AnswerReview{}, fmt.Errorf("parse AI review JSON: %w", err)
+	}
+
+	return bot.AnswerReview{
+		Score:    parsed.Score,
+		Feedback: strings.TrimSpace(parsed.Feedback),
+		Guidance: strings.TrimSpace(parsed.Guidance),
+	}, nil
+}
+
+func (c *Coach) GenerateHint(ctx context.Context, question bot.Question, req bot.HintRequest) (string, error) {
+	system := "You are a coding interview coach. Give hints only, never the full final solution."
+	user := fmt.Sprintf(
+		"Question: %s (%s)\nLink: %s\n%s\nLearner context: %s\n%s\nReturn valid JSON only with key: hint (string).\nHint rules:\n- concise.\n- build on the conversation; do not repeat earlier hints or feedback.\n- include a short heading section and bullet points.\n- include a tiny pseudocode block when useful.\n- do not reveal the full solution or final code.",
+		question.Title,
+		question.Difficulty,
+		question.URL,
+		tutorContextPrompt(req.Statement, req.Transcript),
+		strings.TrimSpace(req.LearnerContext),
+		hintLadderPrompt(req),
+	)
+
+	content, err := c.provider.Complete(ctx, CompletionRequest{Task: TaskHint, System: system, User: user, JSON: true})
+	if err != nil {
+		return "", err
+	}
+
+	var parsed struct {
+		Hint string `json:"hint"`
+	}
+	if err := decodeJSONReply(content, &parsed); err != nil {
+		return "", fmt.Errorf("parse AI hint JSON: %w", err)
+	}
+
+	hint := strings.TrimSpace(parsed.Hint)
+	if hint == "" {
+		return "", fmt.Errorf("AI hint content is empty")
+	}
+	return hint, nil
+}
+
+// AnswerFollowUp answers a learner's question about the problem in the
+// context of the conversation so far. It uses the hint model.
+func (c *Coach) AnswerFollowUp(ctx context.Context, question bot.Question, req bot.FollowUpRequest) (string, error) {
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		return "", fmt.Errorf("follow-up message is empty")
+	}
+
+	system := "You are a coding interview coach in an ongoing tutoring conversation. Answer the learner's question directly, never the full final solution."
+	user := fmt.Sprintf(
+		"Question: %s (%s)\nLink: %s\n%s\nLearner's question:\n%s\n\nReturn valid JSON only with key: reply (string).\nReply rules:\n- answer this question only, referring to the learner's earlier answers when relevant.\n- at most 6 short lines or bullet points.\n- do not grade the learner or reveal the full solution or final code.",
+		question.Title,
+		question.Difficulty,
+		question.URL,
+		tutorContextPrompt(req.Statement, req.Transcript),
+		message,
+	)
+
+	content, err := c.provider.Complete(ctx, CompletionRequest{Task: TaskHint, System: system, User: user, JSON: true})
+	if err != nil {
+		return "", err
+	}
+
+	var parsed struct {
+		Reply string `json:"reply"`
+	}
+	if err := decodeJSONReply(content, &parsed); err != nil {
+		return "", fmt.Errorf("parse AI follow-up JSON: %w", err)
+	}
+
+	reply := strings.TrimSpace(parsed.Reply)
+	if reply == "" {
+		return "", fmt.Errorf("AI follow-up content is empty")
+	}
+	return reply, nil
+}
+
+// maxStatementRunes keeps long statements from crowding out the transcript.
+const maxStatementRunes = 4000
+
+var tutorTurnLabels = map[string]string{
+	bot.TutorTurnAnswer:   "Learner answer",
+	bot.TutorTurnReview:   "Coach review",
+	bot.TutorTurnHint:     "Coach hint",
+	bot.TutorTurnQuestion: "Learner question",
+	bot.TutorTurnReply:    "Coach reply",
+}
+
+// tutorContextPrompt renders the statement and the conversation so far, or
+// nothing when neither is known.
+func tutorContextPrompt(statement string, transcript []bot.TutorTurn) string {
+	var b strings.Builder
+	if statement = strings.TrimSpace(statement); statement != "" {
+		if runes := []rune(statement); len(runes) > maxStatementRunes {
+			statement = string(runes[:maxStatementRunes]) + "…"
+		}
+		b.WriteString("\nProblem statement:\n")
+		b.WriteString(statement)
+		b.WriteString("\n")
+	}
+	if len(transcript) > 0 {
+		b.WriteString("\nConversation so far, oldest first:\n")
+		for _, turn := range transcript {
+			label := tutorTurnLabels[turn.Kind]
+			if label == "" {
+				label = turn.Kind
+			}
+			fmt.Fprintf(&b, "[%s]\n%s\n", label, strings.TrimSpace(turn.Text))
+		}
+	}
+	return b.String()
+}
+
+// hintLadderPrompt tells the model which hints the learner has already seen
+// so the new one goes a step further instead of repeating them.
+func hintLadderPrompt(req bot.HintRequest) string {
+	if len(req.PreviousHints) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(req.PreviousHints)+2)
+	lines = append(lines, "Hints already shown:")
+	for i, hint := range req.PreviousHints {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, strings.TrimSpace(hint)))
+	}
+	lines = append(lines, fmt.Sprintf("This is hint %d of %d: go one step further than the hints above without giving the full solution.", req.Level, req.Total))
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (c *Coach) FormatQuestion(ctx context.Context, question bot.Question, prompt string) (string, error) {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return "", fmt.Errorf("question prompt is empty")
+	}
+
+	system := "You format LeetCode problem statements for Telegram chat. Preserve facts exactly and do not invent details."
+	user := fmt.Sprintf(
+		"Question: %s (%s)\nLink: %s\n\nRaw statement:\n%s\n\nReturn valid JSON only with key: formatted_prompt (string).\nFormatting rules:\n- plain text only; do not include raw markup tags.\n- use concise section headings starting with ## when helpful.\n- use '-' bullet points for lists.\n- format each example in a fenced code block using ```text.\n- preserve variable names, numbers, and logic exactly.\n- do not add constraints/examples that are not present.\n- keep it concise and scannable for chat.",
+		question.Title,
+		question.Difficulty,
+		question.URL,
+		prompt,
+	)
+
+	content, err := c.provider.Complete(ctx, CompletionRequest{Task: TaskFormat, System: system, User: user, JSON: true})
+	if err != nil {
+		return "", err
+	}
+
+	var parsed struct {
+		FormattedPrompt string `json:"formatted_prompt"`
+	}
+	if err := decodeJSONReply(content, &parsed); err != nil {
+		return "", fmt.Errorf("parse AI question format JSON: %w", err)
+	}
+
+	formatted := strings.TrimSpace(parsed.FormattedPrompt)
+	if formatted == "" {
+		return "", fmt.Errorf("AI formatted question content is empty")
+	}
+	return formatted, nil
+}
@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"telegram-leetcode-bot/internal/bot"
+)
+
+// capturingProvider records the last request and returns reply.
+type capturingProvider struct {
+	reply string
+	last  CompletionRequest
+}
+
+func (p *capturingProvider) Complete(_ context.Context, req CompletionRequest) (string, error) {
+	p.last = req
+	return p.reply, nil
+}
+
+func TestCoachAnswersFollowUpInContext(t *testing.T) {
+	provider := &capturingProvider{reply: `{"reply": "Duplicates are fine: check the map before inserting."}`}
+	transcript := []bot.TutorTurn{
+		{Kind: bot.TutorTurnAnswer, Text: "Store each number's index in a map."},
+		{Kind: bot.TutorTurnReview, Text: "Score 6/10 (AI). Explain the lookup order."},
+	}
+
+	reply, err := NewCoach(provider).AnswerFollowUp(context.Background(), twoSum, bot.FollowUpRequest{
+		Message:    "what about the duplicate case?",
+		Statement:  "Given an array of integers nums and an integer target...",
+		Transcript: transcript,
+	})
+	if err != nil {
+		t.Fatalf("AnswerFollowUp: %v", err)
+	}
+	if reply != "Duplicates are fine: check the map before inserting." {
+		t.Fatalf("reply = %q", reply)
+	}
+	if provider.last.Task != TaskHint {
+		t.Fatalf("task = %q, want the hint task", provider.last.Task)
+	}
+	for _, want := range []string{
+		"Given an array of integers nums",
+		"[Learner answer]\nStore each number's index in a map.",
+		"[Coach review]\nScore 6/10 (AI).",
+		"what about the duplicate case?",
+	} {
+		if !strings.Contains(provider.last.User, want) {
+			t.Fatalf("prompt is missing %q:\n%s", want, provider.last.User)
+		}
+	}
+}
+
+func TestCoachReviewSeesTranscript(t *testing.T) {
+	provider := &capturingProvider{reply: `{"score": 8, "feedback": "Better.", "guidance": "1. Test."}`}
+	_, err := NewCoach(provider).ReviewAnswer(context.Background(), twoSum, bot.ReviewRequest{
+		Answer:     "Check the map before inserting each number.",
+		Transcript: []bot.TutorTurn{{Kind: bot.TutorTurnHint, Text: "Think about complements."}},
+	})
+	if err != nil {
+		t.Fatalf("ReviewAnswer: %v", err)
+	}
+	if strings.Contains(provider.last.User, "Problem statement:") {
+		t.Fatalf("prompt has a statement section without a statement:\n%s", provider.last.User)
+	}
+	if !strings.Contains(provider.last.User, "[Coach hint]\nThink about complements.") {
+		t.Fatalf("prompt is missing the earlier hint:\n%s", provider.last.User)
+	}
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIProvider speaks the chat-completions API of OpenAI and of compatible
+// servers such as Ollama and llama.cpp.
+type OpenAIProvider struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewOpenAIProvider requires an API key for the public API. With BaseURL set
+// the key is optional, since local servers usually ignore it.
+func NewOpenAIProvider(cfg ProviderConfig) (*OpenAIProvider, error) {
+	apiKey := strings.TrimSpace(cfg.APIKey)
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		if apiKey == "" {
+			return nil, fmt.Errorf("OpenAI API key is required")
+		}
+		baseURL = defaultOpenAIBaseURL
+	}
+	model := strings.TrimSpace(cfg.Model)
+	if model == "" {
+		return nil, fmt.Errorf("OpenAI model is required")
+	}
+
+	return &OpenAIProvider{
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    baseURL,
+		httpClient: newHTTPClient(cfg.Timeout),
+	}, nil
+}
+
+// ModelFor returns the default model; requests may still override it.
+func (p *OpenAIProvider) ModelFor(Task) string {
+	return p.model
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	type chatMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	payload := map[string]any{
+		"model": modelFor(req, p.model),
+		"messages": []chatMessage{
+			{Role: "system", Content: req.System},
+			{Role: "user", Content: req.User},
+		},
+		"temperature": 0.2,
+	}
+	if req.JSON {
+		payload["response_format"] = map[string]any{"type": "json_object"}
+	}
+
+	header := http.Header{}
+	if p.apiKey != "" {
+		header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	var parsed struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := postJSON(ctx, p.httpClient, "OpenAI", p.baseURL+"/chat/completions", header, payload, &parsed); err != nil {
+		return "", err
+	}
+	if len(parsed.Choices) == 0 {
+		return "", fmt.Errorf("OpenAI response has no choices")
+	}
+
+	content := strings.TrimSpace(parsed.Choices[0].Message.Content)
+	if content == "" {
+		return "", fmt.Errorf("OpenAI response content is empty")
+	}
+	return content, nil
+}
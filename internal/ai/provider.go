@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultProviderTimeout = 25 * time.Second
+
+// Provider sends one system and user message pair to a chat model and
+// returns the reply text.
+type Provider interface {
+	Complete(ctx context.Context, req CompletionRequest) (string, error)
+}
+
+// modelNamer is implemented by providers that can name the model a task
+// is sent to.
+type modelNamer interface {
+	ModelFor(task Task) string
+}
+
+// CompletionRequest is a single-turn chat request. JSON asks the model to
+// reply with one JSON object. Model overrides the provider's default, and
+// Task lets a Router pick the model for the call.
+type CompletionRequest struct {
+	Task   Task
+	Model  string
+	System string
+	User   string
+	JSON   bool
+}
+
+// ProviderConfig configures a chat backend. An empty BaseURL selects the
+// vendor's public API.
+type ProviderConfig struct {
+	APIKey  string
+	Model   string
+	BaseURL string
+	Timeout time.Duration
+}
+
+// StatusError is a non-2xx reply from a provider. RetryAfter is set when
+// the reply carried a Retry-After header in seconds.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// modelFor returns the request's model, or fallback when it names none.
+func modelFor(req CompletionRequest, fallback string) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return fallback
+}
+
+func newHTTPClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = defaultProviderTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// postJSON sends payload to url and decodes a 2xx reply into out.
+func postJSON(ctx context.Context, client *http.Client, provider, url string, header http.Header, payload, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s payload: %w", provider, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build %s request: %w", provider, err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read %s response: %w", provider, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		statusErr := &StatusError{Provider: provider, StatusCode: resp.StatusCode, Body: string(raw)}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			statusErr.RetryAfter = time.Duration(secs) * time.Second
+		}
+		return statusErr
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode %s response: %w", provider, err)
+	}
+	return nil
+}
+
+// decodeJSONReply parses a model's JSON reply. Models without a JSON mode,
+// local ones in particular, often wrap it in a ```json fence or add a line
+// of prose, so only the outermost object is decoded.
+func decodeJSONReply(content string, v any) error {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return fmt.Errorf("reply has no JSON object")
+	}
+	return json.Unmarshal([]byte(content[start:end+1]), v)
+}
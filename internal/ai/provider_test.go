@@ -0,0 +1,138 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"telegram-leetcode-bot/internal/bot"
+)
+
+var twoSum = bot.Question{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"}
+
+// stubServer serves reply at path and hands each decoded request body and
+// its headers to inspect.
+func stubServer(t *testing.T, path string, status int, reply string, inspect func(http.Header, map[string]any)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			t.Errorf("path = %s, want %s", r.URL.Path, path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if inspect != nil {
+			inspect(r.Header, body)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOpenAIProviderReviewsAnswer(t *testing.T) {
+	reply := `{"choices":[{"message":{"content":"{\"score\":9,\"feedback\":\"Uses a hash map.\",\"guidance\":\"1. Ship it.\"}"}}]}`
+	srv := stubServer(t, "/v1/chat/completions", http.StatusOK, reply, func(h http.Header, body map[string]any) {
+		if got := h.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if body["model"] != "gpt-test" {
+			t.Errorf("model = %v", body["model"])
+		}
+		if format, _ := body["response_format"].(map[string]any); format["type"] != "json_object" {
+			t.Errorf("response_format = %v, want json_object", body["response_format"])
+		}
+	})
+
+	provider, err := NewOpenAIProvider(ProviderConfig{APIKey: "sk-test", Model: "gpt-test", BaseURL: srv.URL + "/v1/"})
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider: %v", err)
+	}
+	review, err := NewCoach(provider).ReviewAnswer(context.Background(), twoSum, bot.ReviewRequest{Answer: "hash map of complements"})
+	if err != nil {
+		t.Fatalf("ReviewAnswer: %v", err)
+	}
+	if review.Score != 9 || review.Feedback != "Uses a hash map." || review.Guidance != "1. Ship it." {
+		t.Fatalf("review = %+v", review)
+	}
+}
+
+func TestOpenAICompatibleProviderNeedsNoKey(t *testing.T) {
+	if _, err := NewOpenAIProvider(ProviderConfig{Model: "gpt-test"}); err == nil {
+		t.Fatalf("expected the public API to require a key")
+	}
+
+	// Local models tend to fence their JSON even in JSON mode.
+	reply := `{"choices":[{"message":{"content":"Sure!\n` + "```json" + `\n{\"hint\": \"Think about complements.\"}\n` + "```" + `"}}]}`
+	srv := stubServer(t, "/chat/completions", http.StatusOK, reply, func(h http.Header, _ map[string]any) {
+		if got := h.Get("Authorization"); got != "" {
+			t.Errorf("Authorization = %q, want none without a key", got)
+		}
+	})
+
+	provider, err := NewOpenAIProvider(ProviderConfig{Model: "llama3.1", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider: %v", err)
+	}
+	hint, err := NewCoach(provider).GenerateHint(context.Background(), twoSum, bot.HintRequest{})
+	if err != nil {
+		t.Fatalf("GenerateHint: %v", err)
+	}
+	if hint != "Think about complements." {
+		t.Fatalf("hint = %q", hint)
+	}
+}
+
+func TestAnthropicProviderFormatsQuestion(t *testing.T) {
+	reply := `{"content":[{"type":"text","text":"{\"formatted_prompt\": \"## Two Sum\\nFind two numbers.\"}"}],"stop_reason":"end_turn"}`
+	srv := stubServer(t, "/v1/messages", http.StatusOK, reply, func(h http.Header, body map[string]any) {
+		if got := h.Get("x-api-key"); got != "sk-ant-test" {
+			t.Errorf("x-api-key = %q", got)
+		}
+		if got := h.Get("anthropic-version"); got != anthropicVersion {
+			t.Errorf("anthropic-version = %q", got)
+		}
+		if body["model"] != "claude-test" || body["max_tokens"] != float64(anthropicMaxTokens) {
+			t.Errorf("model/max_tokens = %v/%v", body["model"], body["max_tokens"])
+		}
+		if system, _ := body["system"].(string); !strings.Contains(system, "single JSON object") {
+			t.Errorf("system prompt does not ask for JSON: %q", system)
+		}
+		messages, _ := body["messages"].([]any)
+		if len(messages) != 1 {
+			t.Errorf("messages = %v, want one user message", body["messages"])
+		}
+	})
+
+	provider, err := NewAnthropicProvider(ProviderConfig{APIKey: "sk-ant-test", Model: "claude-test", BaseURL: srv.URL + "/v1"})
+	if err != nil {
+		t.Fatalf("NewAnthropicProvider: %v", err)
+	}
+	formatted, err := NewCoach(provider).FormatQuestion(context.Background(), twoSum, "Given nums, find two numbers.")
+	if err != nil {
+		t.Fatalf("FormatQuestion: %v", err)
+	}
+	if formatted != "## Two Sum\nFind two numbers." {
+		t.Fatalf("formatted = %q", formatted)
+	}
+}
+
+func TestProviderReportsStatusError(t *testing.T) {
+	srv := stubServer(t, "/messages", http.StatusTooManyRequests, `{"type":"error","error":{"type":"rate_limit_error"}}`, nil)
+
+	provider, err := NewAnthropicProvider(ProviderConfig{APIKey: "sk-ant-test", Model: "claude-test", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewAnthropicProvider: %v", err)
+	}
+	_, err = provider.Complete(context.Background(), CompletionRequest{System: "s", User: "u"})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("err = %v, want a 429 StatusError", err)
+	}
+}
@@ -0,0 +1,168 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Task names the coach call a completion is made for.
+type Task string
+
+const (
+	TaskReview Task = "review"
+	TaskHint   Task = "hint"
+	TaskFormat Task = "format"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 500 * time.Millisecond
+	maxRetryDelay      = 8 * time.Second
+)
+
+// Route is a provider and the model to ask it for. An empty Model uses the
+// provider's default; Name labels the route in errors.
+type Route struct {
+	Name     string
+	Provider Provider
+	Model    string
+}
+
+// RouterConfig configures a Router. TaskModels overrides the primary
+// route's model per task. MaxAttempts counts tries per route, including the
+// first. Budget bounds a whole Complete call, retries and fallbacks
+// included; zero leaves it to the caller's context.
+type RouterConfig struct {
+	Primary     Route
+	TaskModels  map[Task]string
+	Fallbacks   []Route
+	MaxAttempts int
+	BaseDelay   time.Duration
+	Budget      time.Duration
+}
+
+// Router is a Provider that sends each task to its model on the primary
+// route and then down the fallback routes. Rate limits and server errors
+// are retried with exponential backoff before moving to the next route.
+type Router struct {
+	cfg   RouterConfig
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewRouter(cfg RouterConfig) *Router {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaultBaseDelay
+	}
+	return &Router{cfg: cfg, sleep: sleepContext}
+}
+
+func (r *Router) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	if r.cfg.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cfg.Budget)
+		defer cancel()
+	}
+
+	var errs []error
+	for _, route := range r.routes(req.Task) {
+		routeReq := req
+		routeReq.Model = route.Model
+		content, err := r.completeWithRetry(ctx, route, routeReq)
+		if err == nil {
+			return content, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", route.label(), err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return "", errors.Join(errs...)
+}
+
+// ModelFor labels the route task tries first, such as "openai/gpt-4o".
+func (r *Router) ModelFor(task Task) string {
+	return r.routes(task)[0].label()
+}
+
+// routes lists the routes to try for task, dropping fallbacks that repeat
+// an earlier route.
+func (r *Router) routes(task Task) []Route {
+	primary := r.cfg.Primary
+	if model := r.cfg.TaskModels[task]; model != "" {
+		primary.Model = model
+	}
+
+	out := []Route{primary}
+	for _, fallback := range r.cfg.Fallbacks {
+		duplicate := false
+		for _, seen := range out {
+			if seen.Provider == fallback.Provider && seen.Model == fallback.Model {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			out = append(out, fallback)
+		}
+	}
+	return out
+}
+
+func (r *Router) completeWithRetry(ctx context.Context, route Route, req CompletionRequest) (string, error) {
+	delay := r.cfg.BaseDelay
+	for attempt := 1; ; attempt++ {
+		content, err := route.Provider.Complete(ctx, req)
+		if err == nil || attempt >= r.cfg.MaxAttempts || !retryable(err) {
+			return content, err
+		}
+
+		wait := delay
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > wait {
+			wait = statusErr.RetryAfter
+		}
+		wait = min(wait, maxRetryDelay)
+		// A retry that cannot start before the deadline leaves what is left
+		// of the budget to the fallbacks.
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+			return "", err
+		}
+		if err := r.sleep(ctx, wait); err != nil {
+			return "", err
+		}
+		delay *= 2
+	}
+}
+
+func (r Route) label() string {
+	if r.Model == "" {
+		return r.Name
+	}
+	return r.Name + "/" + r.Model
+}
+
+// retryable reports rate limits and server errors, which usually pass.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
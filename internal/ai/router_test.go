@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedProvider returns errs in order, then reply, and records the model
+// of every call.
+type scriptedProvider struct {
+	errs   []error
+	reply  string
+	models []string
+}
+
+func (p *scriptedProvider) Complete(_ context.Context, req CompletionRequest) (string, error) {
+	p.models = append(p.models, req.Model)
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		return "", err
+	}
+	return p.reply, nil
+}
+
+func newTestRouter(cfg RouterConfig) (*Router, *[]time.Duration) {
+	var waits []time.Duration
+	router := NewRouter(cfg)
+	router.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return router, &waits
+}
+
+func TestRouterUsesTaskModelAndRetriesTransientErrors(t *testing.T) {
+	primary := &scriptedProvider{
+		errs: []error{
+			&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Second},
+			&StatusError{StatusCode: http.StatusBadGateway},
+		},
+		reply: "ok",
+	}
+	router, waits := newTestRouter(RouterConfig{
+		Primary:    Route{Name: "openai", Provider: primary, Model: "gpt-4o-mini"},
+		TaskModels: map[Task]string{TaskReview: "gpt-4o"},
+		BaseDelay:  100 * time.Millisecond,
+	})
+
+	got, err := router.Complete(context.Background(), CompletionRequest{Task: TaskReview})
+	if err != nil || got != "ok" {
+		t.Fatalf("Complete = %q, %v", got, err)
+	}
+	if strings.Join(primary.models, ",") != "gpt-4o,gpt-4o,gpt-4o" {
+		t.Fatalf("models = %v, want the review model three times", primary.models)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 2*time.Second || (*waits)[1] != 200*time.Millisecond {
+		t.Fatalf("waits = %v, want Retry-After then doubled backoff", *waits)
+	}
+
+	if _, err := router.Complete(context.Background(), CompletionRequest{Task: TaskFormat}); err != nil {
+		t.Fatalf("Complete(format): %v", err)
+	}
+	if last := primary.models[len(primary.models)-1]; last != "gpt-4o-mini" {
+		t.Fatalf("format model = %q, want the default model", last)
+	}
+}
+
+func TestRouterFallsBackAcrossProviders(t *testing.T) {
+	primary := &scriptedProvider{errs: []error{
+		&StatusError{StatusCode: http.StatusServiceUnavailable},
+		&StatusError{StatusCode: http.StatusServiceUnavailable},
+	}}
+	badRequest := &scriptedProvider{errs: []error{&StatusError{StatusCode: http.StatusBadRequest}}}
+	backup := &scriptedProvider{reply: "from backup"}
+	router, waits := newTestRouter(RouterConfig{
+		Primary: Route{Name: "openai", Provider: primary, Model: "gpt-4o"},
+		Fallbacks: []Route{
+			{Name: "openai", Provider: primary, Model: "gpt-4o"},
+			{Name: "ollama", Provider: badRequest, Model: "llama3.1"},
+			{Name: "anthropic", Provider: backup, Model: "claude-3-5-haiku-latest"},
+		},
+		MaxAttempts: 2,
+	})
+
+	got, err := router.Complete(context.Background(), CompletionRequest{Task: TaskHint})
+	if err != nil || got != "from backup" {
+		t.Fatalf("Complete = %q, %v", got, err)
+	}
+	if len(primary.models) != 2 {
+		t.Fatalf("primary called %d times, want 2 (the duplicate fallback is skipped)", len(primary.models))
+	}
+	if len(badRequest.models) != 1 || len(*waits) != 1 {
+		t.Fatalf("a 400 must not be retried: calls=%d waits=%v", len(badRequest.models), *waits)
+	}
+}
+
+func TestRouterReportsEveryRouteError(t *testing.T) {
+	router, _ := newTestRouter(RouterConfig{
+		Primary:     Route{Name: "openai", Provider: &scriptedProvider{errs: []error{errors.New("boom")}}, Model: "gpt-4o"},
+		Fallbacks:   []Route{{Name: "anthropic", Provider: &scriptedProvider{errs: []error{&StatusError{Provider: "Anthropic", StatusCode: http.StatusUnauthorized}}}}},
+		MaxAttempts: 1,
+	})
+
+	_, err := router.Complete(context.Background(), CompletionRequest{Task: TaskReview})
+	if err == nil || !strings.Contains(err.Error(), "openai/gpt-4o: boom") || !strings.Contains(err.Error(), "anthropic: Anthropic status 401") {
+		t.Fatalf("err = %v, want both route errors", err)
+	}
+}
+
+// blockingProvider waits for the context to end, like a hung upstream.
+type blockingProvider struct{ calls int }
+
+func (p *blockingProvider) Complete(ctx context.Context, _ CompletionRequest) (string, error) {
+	p.calls++
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestRouterStopsWhenBudgetIsSpent(t *testing.T) {
+	hung := &blockingProvider{}
+	backup := &scriptedProvider{reply: "from backup"}
+	router, _ := newTestRouter(RouterConfig{
+		Primary:   Route{Name: "openai", Provider: hung, Model: "gpt-4o"},
+		Fallbacks: []Route{{Name: "anthropic", Provider: backup}},
+		Budget:    20 * time.Millisecond,
+	})
+
+	start := time.Now()
+	_, err := router.Complete(context.Background(), CompletionRequest{Task: TaskReview})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the budget's deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Complete took %v, want it bounded by the budget", elapsed)
+	}
+	if hung.calls != 1 || len(backup.models) != 0 {
+		t.Fatalf("calls after the budget: primary=%d fallback=%d, want 1 and 0", hung.calls, len(backup.models))
+	}
+}
+
+func TestRouterSkipsRetriesThatOutlastTheBudget(t *testing.T) {
+	primary := &scriptedProvider{errs: []error{
+		&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 5 * time.Second},
+	}}
+	backup := &scriptedProvider{reply: "from backup"}
+	router, waits := newTestRouter(RouterConfig{
+		Primary:   Route{Name: "openai", Provider: primary, Model: "gpt-4o"},
+		Fallbacks: []Route{{Name: "anthropic", Provider: backup}},
+		Budget:    time.Second,
+	})
+
+	got, err := router.Complete(context.Background(), CompletionRequest{Task: TaskHint})
+	if err != nil || got != "from backup" {
+		t.Fatalf("Complete = %q, %v", got, err)
+	}
+	if len(primary.models) != 1 || len(*waits) != 0 {
+		t.Fatalf("a retry past the budget must be skipped: calls=%d waits=%v", len(primary.models), *waits)
+	}
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"telegram-leetcode-bot/internal/adapters"
+	"telegram-leetcode-bot/internal/bot"
+	"telegram-leetcode-bot/internal/config"
+	"telegram-leetcode-bot/internal/leetcode"
+)
+
+// newQuestionProvider picks the question bank. With QUESTION_SOURCE=live and
+// a catalog path, the snapshot backs up the live catalog; an unreadable
+// snapshot only disables the fallback.
+func newQuestionProvider(logger *log.Logger, cfg config.Config, client *leetcode.Client) (bot.QuestionProvider, error) {
+	live := adapters.NewLeetCodeProvider(client)
+	if cfg.QuestionSource == config.QuestionSourceCatalog {
+		catalog, err := leetcode.LoadCatalog(cfg.QuestionCatalogPath)
+		if err != nil {
+			return nil, err
+		}
+		logger.Printf("serving %d questions from catalog %s", len(catalog.Questions), cfg.QuestionCatalogPath)
+		return adapters.NewCatalogProvider(catalog), nil
+	}
+	if cfg.QuestionCatalogPath == "" {
+		return live, nil
+	}
+
+	catalog, err := leetcode.LoadCatalog(cfg.QuestionCatalogPath)
+	if err != nil {
+		logger.Printf("offline catalog fallback disabled: %v", err)
+		return live, nil
+	}
+	logger.Printf("offline catalog fallback enabled with %d questions from %s", len(catalog.Questions), cfg.QuestionCatalogPath)
+	return adapters.NewFallbackProvider(logger, live, adapters.NewCatalogProvider(catalog)), nil
+}
+
+// exportCatalog implements `export-catalog`, which snapshots the live
+// question list to a file usable as QUESTION_CATALOG_PATH.
+func exportCatalog(logger *log.Logger, args []string) error {
+	fs := flag.NewFlagSet("export-catalog", flag.ContinueOnError)
+	defaultOut := os.Getenv("QUESTION_CATALOG_PATH")
+	if defaultOut == "" {
+		defaultOut = "data/questions.json"
+	}
+	out := fs.String("out", defaultOut, "snapshot path; .yaml/.yml writes YAML, anything else JSON")
+	statements := fs.Bool("statements", false, "also fetch every problem statement (slow)")
+	timeout := fs.Duration("timeout", time.Hour, "give up after this long")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	catalog, err := leetcode.NewClient(time.Hour).ExportCatalog(ctx, *statements)
+	if err != nil {
+		return fmt.Errorf("export question catalog: %w", err)
+	}
+	if err := leetcode.WriteCatalog(*out, catalog); err != nil {
+		return err
+	}
+	logger.Printf("wrote %d questions to %s", len(catalog.Questions), *out)
+	return nil
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"telegram-leetcode-bot/internal/ai"
+	"telegram-leetcode-bot/internal/bot"
+	"telegram-leetcode-bot/internal/config"
+)
+
+// newCoach builds the AI coach for AI_PROVIDER with its per-task models and
+// AI_FALLBACKS routes. It returns nil, so answers are graded by the
+// heuristic alone, when AI is off or the primary provider cannot be set up;
+// a fallback that cannot be set up is only skipped.
+func newCoach(logger *log.Logger, cfg config.Config) bot.Coach {
+	if !cfg.AIEnabled {
+		logger.Printf("AI coach disabled (AI_ENABLED=false)")
+		return nil
+	}
+	timeout := time.Duration(cfg.AITimeoutSec) * time.Second
+	primary, err := newAIProvider(cfg.AIProvider, ai.ProviderConfig{
+		APIKey:  cfg.AIAPIKey,
+		Model:   cfg.AIModel,
+		BaseURL: cfg.AIBaseURL,
+		Timeout: timeout,
+	})
+	if err != nil {
+		logger.Printf("AI coach disabled due to initialization error: %v", err)
+		return nil
+	}
+
+	routerCfg := ai.RouterConfig{
+		Primary: ai.Route{Name: cfg.AIProvider, Provider: primary, Model: cfg.AIModel},
+		TaskModels: map[ai.Task]string{
+			ai.TaskReview: cfg.AIReviewModel,
+			ai.TaskHint:   cfg.AIHintModel,
+			ai.TaskFormat: cfg.AIFormatModel,
+		},
+		MaxAttempts: cfg.AIMaxAttempts,
+		// Twice the per-request timeout: room for one slow call plus
+		// retries or a fallback, without stalling the chat for minutes.
+		Budget: 2 * timeout,
+	}
+	for _, route := range cfg.AIFallbacks {
+		provider := ai.Provider(primary)
+		if route.Provider != cfg.AIProvider {
+			provider, err = newAIProvider(route.Provider, ai.ProviderConfig{
+				APIKey:  route.APIKey,
+				Model:   route.Model,
+				BaseURL: route.BaseURL,
+				Timeout: timeout,
+			})
+			if err != nil {
+				logger.Printf("AI fallback %s:%s skipped: %v", route.Provider, route.Model, err)
+				continue
+			}
+		}
+		routerCfg.Fallbacks = append(routerCfg.Fallbacks, ai.Route{Name: route.Provider, Provider: provider, Model: route.Model})
+	}
+
+	logger.Printf("AI coach enabled with %s model %s and %d fallback(s)", cfg.AIProvider, cfg.AIModel, len(routerCfg.Fallbacks))
+	return ai.NewCoach(ai.NewRouter(routerCfg))
+}
+
+func newAIProvider(name string, providerCfg ai.ProviderConfig) (ai.Provider, error) {
+	switch name {
+	case config.AIProviderOpenAI, config.AIProviderOllama, config.AIProviderOpenAICompatible:
+		return ai.NewOpenAIProvider(providerCfg)
+	case config.AIProviderAnthropic:
+		return ai.NewAnthropicProvider(providerCfg)
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q", name)
+	}
+}
@@ -13,16 +13,22 @@ import (
 	"cloud.google.com/go/firestore"
 
 	"telegram-leetcode-bot/internal/adapters"
-	"telegram-leetcode-bot/internal/ai"
 	"telegram-leetcode-bot/internal/bot"
 	"telegram-leetcode-bot/internal/config"
 	"telegram-leetcode-bot/internal/leetcode"
+	"telegram-leetcode-bot/internal/sandbox"
 	"telegram-leetcode-bot/internal/storage"
 	"telegram-leetcode-bot/internal/telegram"
 )
 
 func Main() {
 	logger := log.New(os.Stdout, "", log.LstdFlags|log.Lmicroseconds)
+	if len(os.Args) > 1 && os.Args[1] == "export-catalog" {
+		if err := exportCatalog(logger, os.Args[2:]); err != nil {
+			logger.Fatalf("%v", err)
+		}
+		return
+	}
 	if err := run(logger); err != nil {
 		logger.Fatalf("%v", err)
 	}
@@ -35,42 +41,31 @@ func run(logger *log.Logger) error {
 	}
 
 	ctx := context.Background()
-	fireClient, err := firestore.NewClient(ctx, cfg.FirestoreProject)
+	store, closeStore, err := newStateStore(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("create firestore client: %w", err)
+		return err
 	}
 	defer func() {
-		if err := fireClient.Close(); err != nil {
-			logger.Printf("close firestore client: %v", err)
+		if err := closeStore(); err != nil {
+			logger.Printf("close %s store: %v", cfg.StorageBackend, err)
 		}
 	}()
+	logger.Printf("using %s storage backend", cfg.StorageBackend)
 
 	tgClient := telegram.NewClient(cfg.TelegramBotToken)
 	lcClient := leetcode.NewClient(time.Duration(cfg.QuestionCacheSec) * time.Second)
-	store := storage.NewStore(fireClient, cfg.DefaultDailyTime, cfg.DefaultTimezone)
-	var coach bot.Coach
-	if cfg.AIEnabled && cfg.OpenAIAPIKey != "" {
-		c, err := ai.NewOpenAICoach(
-			cfg.OpenAIAPIKey,
-			cfg.OpenAIModel,
-			time.Duration(cfg.AITimeoutSec)*time.Second,
-		)
-		if err != nil {
-			logger.Printf("AI coach disabled due to initialization error: %v", err)
-		} else {
-			coach = c
-			logger.Printf("AI coach enabled with model %s", cfg.OpenAIModel)
-		}
-	} else {
-		logger.Printf("AI coach disabled (AI_ENABLED=%t, key_present=%t)", cfg.AIEnabled, cfg.OpenAIAPIKey != "")
+	questions, err := newQuestionProvider(logger, cfg, lcClient)
+	if err != nil {
+		return err
 	}
+	coach := newCoach(logger, cfg)
 
 	service := bot.NewService(
 		logger,
 		tgClient,
-		adapters.NewLeetCodeProvider(lcClient),
+		questions,
 		coach,
-		adapters.NewFirestoreStateStore(store),
+		store,
 		cfg.WebhookSecret,
 		cfg.CronSecret,
 		cfg.DefaultDailyTime,
@@ -78,8 +73,32 @@ func run(logger *log.Logger) error {
 		cfg.AllowedUsernames,
 		cfg.DailySchedulingEnabled,
 	)
+	service.SetFormatCache(cfg.AIFormatCacheSize, cfg.AIFormatCachePersist)
+	if username := resolveBotUsername(ctx, logger, tgClient, cfg.BotUsername); username != "" {
+		service.SetBotUsername(username)
+	}
+	if cfg.CodeExecutionEnabled {
+		if len(cfg.AllowedUsernames) == 0 {
+			return fmt.Errorf("CODE_EXECUTION_ENABLED requires ALLOWED_TELEGRAM_USERNAMES")
+		}
+		runner := sandbox.NewRunner(sandbox.Limits{
+			Timeout:    time.Duration(cfg.CodeExecutionTimeoutSec) * time.Second,
+			CPUSeconds: cfg.CodeExecutionTimeoutSec,
+			MemoryMB:   cfg.CodeExecutionMemoryMB,
+		})
+		if err := runner.Check(ctx); err != nil {
+			return fmt.Errorf("code execution: %w", err)
+		}
+		service.SetCodeRunner(adapters.NewCodeRunner(lcClient, runner))
+		logger.Printf("code execution enabled (timeout %ds, memory %dMB)", cfg.CodeExecutionTimeoutSec, cfg.CodeExecutionMemoryMB)
+	}
 
-	if cfg.AutoSetWebhook {
+	polling := cfg.UpdateMode == config.UpdateModePolling
+	if polling {
+		if err := deleteWebhook(ctx, tgClient); err != nil {
+			return fmt.Errorf("switch to polling mode: %w", err)
+		}
+	} else if cfg.AutoSetWebhook {
 		autoSetWebhook(ctx, logger, tgClient, cfg.BotBaseURL, cfg.WebhookSecret)
 	}
 
@@ -90,7 +109,9 @@ func run(logger *log.Logger) error {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	mux.HandleFunc("/webhook/", service.WebhookHandler)
+	if !polling {
+		mux.HandleFunc("/webhook/", service.WebhookHandler)
+	}
 	mux.HandleFunc("/cron/daily", service.CronHandler)
 
 	httpServer := &http.Server{
@@ -99,6 +120,9 @@ func run(logger *log.Logger) error {
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
+	runCtx, stopRun := context.WithCancel(context.Background())
+	defer stopRun()
+
 	shutdownDone := make(chan struct{})
 	go func() {
 		sigCh := make(chan os.Signal, 1)
@@ -106,6 +130,7 @@ func run(logger *log.Logger) error {
 		defer signal.Stop(sigCh)
 
 		<-sigCh
+		stopRun()
 
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 		defer cancel()
@@ -115,6 +140,20 @@ func run(logger *log.Logger) error {
 		close(shutdownDone)
 	}()
 
+	pollDone := make(chan struct{})
+	if polling {
+		poller := telegram.NewPoller(tgClient, time.Duration(cfg.PollTimeoutSec)*time.Second, service.HandleUpdate, logger)
+		go func() {
+			defer close(pollDone)
+			logger.Printf("long polling for updates (timeout %ds)", cfg.PollTimeoutSec)
+			if err := poller.Run(runCtx); err != nil && !telegram.IsPollingStopped(err) {
+				logger.Printf("polling stopped: %v", err)
+			}
+		}()
+	} else {
+		close(pollDone)
+	}
+
 	logger.Printf("bot server listening on %s", httpServer.Addr)
 	err = httpServer.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
@@ -122,10 +161,57 @@ func run(logger *log.Logger) error {
 	}
 
 	<-shutdownDone
+	<-pollDone
 	logger.Printf("shutdown complete")
 	return nil
 }
 
+func newStateStore(ctx context.Context, cfg config.Config) (bot.StateStore, func() error, error) {
+	switch cfg.StorageBackend {
+	case config.StorageBackendMemory:
+		store := storage.NewMemoryStore(cfg.DefaultDailyTime, cfg.DefaultTimezone)
+		return adapters.NewMemoryStateStore(store), func() error { return nil }, nil
+	case config.StorageBackendBolt:
+		store, err := storage.OpenBoltStore(cfg.BoltDBPath, cfg.DefaultDailyTime, cfg.DefaultTimezone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open bolt store: %w", err)
+		}
+		return adapters.NewBoltStateStore(store), store.Close, nil
+	default:
+		fireClient, err := firestore.NewClient(ctx, cfg.FirestoreProject)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create firestore client: %w", err)
+		}
+		store := storage.NewStore(fireClient, cfg.DefaultDailyTime, cfg.DefaultTimezone)
+		return adapters.NewFirestoreStateStore(store), fireClient.Close, nil
+	}
+}
+
+func deleteWebhook(ctx context.Context, client *telegram.Client) error {
+	deleteCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	return client.DeleteWebhook(deleteCtx)
+}
+
+// resolveBotUsername prefers the configured username and otherwise asks
+// Telegram. Failure is logged, not fatal: group commands then match any bot.
+func resolveBotUsername(ctx context.Context, logger *log.Logger, client *telegram.Client, configured string) string {
+	if configured != "" {
+		return configured
+	}
+
+	meCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	me, err := client.GetMe(meCtx)
+	if err != nil {
+		logger.Printf("look up bot username failed: %v", err)
+		return ""
+	}
+	return me.Username
+}
+
 func autoSetWebhook(ctx context.Context, logger *log.Logger, client *telegram.Client, baseURL, secret string) {
 	if baseURL == "" {
 		logger.Printf("AUTO_SET_WEBHOOK=true but BOT_BASE_URL is empty; skipping")
@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	CodeLanguagePython = "python"
+	CodeLanguageGo     = "go"
+)
+
+const maxExampleValueRunes = 80
+
+// codeRun is the outcome of running the fenced code in an answer. Err explains
+// why nothing could be run, e.g. a compile error.
+type codeRun struct {
+	Language string
+	Results  []ExampleResult
+	Err      string
+}
+
+var fencedCodePattern = regexp.MustCompile("(?s)```[ \\t]*([A-Za-z0-9+#]*)[^\\n]*\\n(.*?)```")
+
+// SetCodeRunner enables running fenced code in answers against the question's
+// examples. Without a runner answers are only reviewed as text.
+func (s *Service) SetCodeRunner(runner CodeRunner) {
+	s.codeRunner = runner
+}
+
+// extractFencedCode returns the first fenced Python or Go block in answer.
+// Untagged blocks are recognised by a def or func declaration.
+func extractFencedCode(answer string) (language, code string, ok bool) {
+	for _, match := range fencedCodePattern.FindAllStringSubmatch(answer, -1) {
+		code = strings.TrimSpace(match[2])
+		if code == "" {
+			continue
+		}
+		switch strings.ToLower(match[1]) {
+		case "python", "python3", "py":
+			return CodeLanguagePython, code, true
+		case "go", "golang":
+			return CodeLanguageGo, code, true
+		case "":
+			if strings.Contains(code, "def ") {
+				return CodeLanguagePython, code, true
+			}
+			if strings.Contains(code, "func ") {
+				return CodeLanguageGo, code, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// runAnswerCode runs the answer's fenced code, if any, against the examples.
+// It returns nil when code execution is off or the answer has no code.
+func (s *Service) runAnswerCode(ctx context.Context, q Question, answer string) *codeRun {
+	if s.codeRunner == nil {
+		return nil
+	}
+	language, code, ok := extractFencedCode(answer)
+	if !ok {
+		return nil
+	}
+
+	results, err := s.codeRunner.RunExamples(ctx, q, language, code)
+	run := &codeRun{Language: language, Results: results}
+	switch {
+	case errors.Is(err, ErrCodeRunUnsupported):
+		run.Err = "Running code isn't supported for this question yet."
+	case err != nil:
+		s.logger.Printf("run answer code failed for slug=%s lang=%s: %v", q.Slug, language, err)
+		run.Err = "Could not run your code: " + err.Error()
+	case len(results) == 0:
+		run.Err = "This question has no examples to run."
+	}
+	return run
+}
+
+func formatCodeRunLines(run codeRun) []string {
+	lines := []string{"__*Examples*__", ""}
+	if run.Err != "" {
+		return append(lines, escapeMarkdownV2("⚠️ "+run.Err))
+	}
+
+	passed := 0
+	for i, result := range run.Results {
+		line := fmt.Sprintf("✅ Example %d passed", i+1)
+		switch {
+		case result.Err != "":
+			line = fmt.Sprintf("⚠️ Example %d: %s", i+1, truncateExampleValue(result.Err))
+		case !result.Passed:
+			line = fmt.Sprintf("❌ Example %d: %s → expected %s, got %s", i+1,
+				truncateExampleValue(result.Input), truncateExampleValue(result.Expected), truncateExampleValue(result.Got))
+		default:
+			passed++
+		}
+		lines = append(lines, escapeMarkdownV2(line))
+	}
+	label := "Python"
+	if run.Language == CodeLanguageGo {
+		label = "Go"
+	}
+	summary := fmt.Sprintf("%s: %d/%d examples passed", label, passed, len(run.Results))
+	return append(lines, "", "_"+escapeMarkdownV2(summary)+"_")
+}
+
+func truncateExampleValue(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "nothing"
+	}
+	runes := []rune(value)
+	if len(runes) <= maxExampleValueRunes {
+		return value
+	}
+	return string(runes[:maxExampleValueRunes]) + "…"
+}
@@ -1,7 +1,11 @@
 package bot
 
-import "context"
+import (
+	"context"
 
-func (s *Service) handleCommand(ctx context.Context, chatID int64, text string) error {
-	return s.commandHandler.Handle(ctx, chatID, text)
+	"telegram-leetcode-bot/internal/bot/commands"
+)
+
+func (s *Service) handleCommand(ctx context.Context, key StateKey, text string) error {
+	return s.commandHandler.Handle(ctx, commands.StateKey(key), text)
 }
@@ -6,22 +6,22 @@ import (
 	"strings"
 )
 
-func (h *Handler) cmdAnsweredHistory(ctx context.Context, chatID int64, args []string) error {
+func (h *Handler) cmdAnsweredHistory(ctx context.Context, key StateKey, args []string) error {
 	limit := 10
 	if len(args) > 0 {
 		parsed, err := parsePositiveLimit(args[0], 50)
 		if err != nil {
-			return h.deps.SendMessage(ctx, chatID, "Usage: /answered [limit], e.g. /answered 10")
+			return h.deps.SendMessage(ctx, key, "Usage: /answered [limit], e.g. /answered 10")
 		}
 		limit = parsed
 	}
 
-	items, err := h.deps.ListAnsweredQuestions(ctx, chatID, limit)
+	items, err := h.deps.ListAnsweredQuestions(ctx, key, limit)
 	if err != nil {
 		return err
 	}
 	if len(items) == 0 {
-		return h.deps.SendMessage(ctx, chatID, "No answered questions yet. Use /lc and either solve correctly or /done.")
+		return h.deps.SendMessage(ctx, key, "No answered questions yet. Use /lc and either solve correctly or /done.")
 	}
 
 	lines := make([]string, 0, len(items)+4)
@@ -40,9 +40,9 @@ func (h *Handler) cmdAnsweredHistory(ctx context.Context, chatID int64, args []s
 			"",
 		)
 	}
-	lines = append(lines, escapeMarkdownV2("Use /revise <slug> to revisit a specific question, or /revise for a random one."))
+	lines = append(lines, escapeMarkdownV2("Use /revise <slug> to revisit a specific question, or /revise for the most overdue one."))
 
-	return h.deps.SendRichMessage(ctx, chatID, strings.Join(lines, "\n"))
+	return h.deps.SendRichMessage(ctx, key, strings.Join(lines, "\n"))
 }
 
 func escapeMarkdownV2(text string) string {
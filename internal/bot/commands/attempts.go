@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	attemptsListLimit     = 10
+	attemptAnswerMaxRunes = 160
+)
+
+func (h *Handler) cmdAttempts(ctx context.Context, key StateKey, args []string) error {
+	slug := ""
+	if len(args) > 0 {
+		slug = normalizeSlug(strings.Join(args, " "))
+		if slug == "" {
+			return h.deps.SendMessage(ctx, key, "Usage: /attempts [slug], e.g. /attempts two-sum")
+		}
+	} else {
+		settings, err := h.deps.GetChatSettings(ctx, key)
+		if err != nil {
+			return err
+		}
+		if settings.CurrentQuestion != nil {
+			slug = settings.CurrentQuestion.Slug
+		}
+	}
+
+	items, err := h.deps.ListAnswerAttempts(ctx, key, slug, attemptsListLimit)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return h.deps.SendMessage(ctx, key, "No graded attempts yet. Send your approach after /lc to get one.")
+	}
+
+	title := "*📝 Recent Attempts*"
+	if slug != "" {
+		title = fmt.Sprintf("*📝 Attempts for* `%s`", escapeMarkdownV2Code(slug))
+	}
+
+	lines := make([]string, 0, len(items)*5+2)
+	lines = append(lines, title, "")
+	for i, item := range items {
+		lines = append(lines,
+			fmt.Sprintf("*%d\\. %s*", i+1, escapeMarkdownV2(item.CreatedAt.UTC().Format("2006-01-02 15:04"))),
+		)
+		if slug == "" {
+			lines = append(lines, fmt.Sprintf("• Slug: `%s`", escapeMarkdownV2Code(item.Slug)))
+		}
+		lines = append(lines,
+			fmt.Sprintf("• Score: %d/10 \\(%s\\)", item.Score, escapeMarkdownV2(item.Source)),
+			fmt.Sprintf("• Answer: %s", escapeMarkdownV2(truncateText(item.Answer, attemptAnswerMaxRunes))),
+			fmt.Sprintf("• Feedback: %s", escapeMarkdownV2(truncateText(item.Feedback, attemptAnswerMaxRunes))),
+			"",
+		)
+	}
+
+	return h.deps.SendRichMessage(ctx, key, strings.TrimSpace(strings.Join(lines, "\n")))
+}
+
+func truncateText(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"context"
+	"strings"
+)
+
+const callbackCommandPrefix = "cmd:"
+
+// Callback data carried by inline keyboard buttons. Each maps onto the slash
+// command of the same name (Next onto /lc random) so buttons and typed
+// commands share one code path.
+const (
+	CallbackHint   = callbackCommandPrefix + "hint"
+	CallbackSkip   = callbackCommandPrefix + "skip"
+	CallbackDone   = callbackCommandPrefix + "done"
+	CallbackExit   = callbackCommandPrefix + "exit"
+	CallbackRevise = callbackCommandPrefix + "revise"
+	CallbackNext   = callbackCommandPrefix + "next"
+)
+
+var callbackCommands = map[string]string{
+	CallbackHint:   "/hint",
+	CallbackSkip:   "/skip",
+	CallbackDone:   "/done",
+	CallbackExit:   "/exit",
+	CallbackRevise: "/revise",
+	CallbackNext:   "/lc random",
+}
+
+func (h *Handler) HandleCallback(ctx context.Context, key StateKey, data string) error {
+	cmd, ok := callbackCommands[strings.TrimSpace(data)]
+	if !ok {
+		return h.deps.SendMessage(ctx, key, "That button is no longer supported. Use /help to see available commands.")
+	}
+	return h.Handle(ctx, key, cmd)
+}
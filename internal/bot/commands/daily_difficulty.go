@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const dailyDifficultyUsage = "Usage: /daily_difficulty <easy|medium|hard|any>"
+
+func (h *Handler) cmdDailyDifficulty(ctx context.Context, key StateKey, args []string) error {
+	if len(args) == 0 {
+		settings, err := h.deps.GetChatSettings(ctx, key)
+		if err != nil {
+			return err
+		}
+		return h.deps.SendMessage(ctx, key, fmt.Sprintf("Daily difficulty: %s\n%s", difficultyLabel(settings.Difficulty), dailyDifficultyUsage))
+	}
+
+	difficulty, ok := parseDifficulty(args[0])
+	if !ok {
+		if !strings.EqualFold(args[0], "any") {
+			return h.deps.SendMessage(ctx, key, dailyDifficultyUsage)
+		}
+		difficulty = ""
+	}
+
+	if err := h.deps.SetDifficultyPreference(ctx, key, difficulty); err != nil {
+		return err
+	}
+	return h.deps.SendMessage(ctx, key, fmt.Sprintf("Daily difficulty set to %s.", difficultyLabel(difficulty)))
+}
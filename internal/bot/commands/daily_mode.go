@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const groupDailyModeMessage = "Groups only support /daily_mode new: revisions follow each member's own history. Members can send /revise or /due to work through theirs."
+
+const dailyModeUsage = "Usage: /daily_mode <new|revise|mixed>\nnew - a new question every day\nrevise - a due revision question, or a new one if nothing is due\nmixed - a new question plus a reminder when a revision is due"
+
+func (h *Handler) cmdDailyMode(ctx context.Context, key StateKey, args []string) error {
+	if len(args) == 0 {
+		settings, err := h.deps.GetChatSettings(ctx, key)
+		if err != nil {
+			return err
+		}
+		return h.deps.SendMessage(ctx, key, fmt.Sprintf("Daily mode: %s\n%s", dailyModeLabel(settings.DailyMode), dailyModeUsage))
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(args[0]))
+	switch mode {
+	case "new", "revise", "mixed":
+	default:
+		return h.deps.SendMessage(ctx, key, dailyModeUsage)
+	}
+	if mode != "new" && key.ChatID < 0 {
+		// Revisions come from one learner's answered history; a group's
+		// members each keep their own, so the group key has nothing due.
+		return h.deps.SendMessage(ctx, key, groupDailyModeMessage)
+	}
+
+	if err := h.deps.SetDailyMode(ctx, key, mode); err != nil {
+		return err
+	}
+	return h.deps.SendMessage(ctx, key, fmt.Sprintf("Daily mode set to %s.", mode))
+}
+
+func dailyModeLabel(mode string) string {
+	if mode == "" {
+		return "new"
+	}
+	return mode
+}
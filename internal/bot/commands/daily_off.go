@@ -2,8 +2,8 @@ package commands
 
 import "context"
 
-func (h *Handler) cmdDailyOff(ctx context.Context, chatID int64) error {
-	settings, err := h.deps.GetChatSettings(ctx, chatID)
+func (h *Handler) cmdDailyOff(ctx context.Context, key StateKey) error {
+	settings, err := h.deps.GetChatSettings(ctx, key)
 	if err != nil {
 		return err
 	}
@@ -13,9 +13,9 @@ func (h *Handler) cmdDailyOff(ctx context.Context, chatID int64) error {
 		hhmm = h.deps.DefaultDailyHH()
 	}
 
-	if err := h.deps.UpsertDailySettings(ctx, chatID, false, hhmm, h.deps.DefaultTZ()); err != nil {
+	if err := h.deps.UpsertDailySettings(ctx, key, false, hhmm, h.deps.DefaultTZ()); err != nil {
 		return err
 	}
 
-	return h.deps.SendMessage(ctx, chatID, "Daily question is OFF. Use /daily_on to re-enable.")
+	return h.deps.SendMessage(ctx, key, "Daily question is OFF. Use /daily_on to re-enable.")
 }
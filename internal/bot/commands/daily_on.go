@@ -5,8 +5,8 @@ import (
 	"fmt"
 )
 
-func (h *Handler) cmdDailyOn(ctx context.Context, chatID int64, args []string) error {
-	settings, err := h.deps.GetChatSettings(ctx, chatID)
+func (h *Handler) cmdDailyOn(ctx context.Context, key StateKey, args []string) error {
+	settings, err := h.deps.GetChatSettings(ctx, key)
 	if err != nil {
 		return err
 	}
@@ -22,13 +22,13 @@ func (h *Handler) cmdDailyOn(ctx context.Context, chatID int64, args []string) e
 	if len(args) > 0 {
 		hhmm, err = normalizeHHMM(args[0])
 		if err != nil {
-			return h.deps.SendMessage(ctx, chatID, "Invalid time. Use 24h HH:MM, e.g. /daily_on 20:30")
+			return h.deps.SendMessage(ctx, key, "Invalid time. Use 24h HH:MM, e.g. /daily_on 20:30")
 		}
 	}
 
-	if err := h.deps.UpsertDailySettings(ctx, chatID, true, hhmm, tz); err != nil {
+	if err := h.deps.UpsertDailySettings(ctx, key, true, hhmm, tz); err != nil {
 		return err
 	}
 
-	return h.deps.SendMessage(ctx, chatID, fmt.Sprintf("Daily question is ON at %s %s. Use /daily_off to stop.", hhmm, tzLabel(tz)))
+	return h.deps.SendMessage(ctx, key, fmt.Sprintf("Daily question is ON at %s %s. Use /daily_off to stop.", hhmm, tzLabel(tz)))
 }
@@ -5,8 +5,8 @@ import (
 	"fmt"
 )
 
-func (h *Handler) cmdDailyStatus(ctx context.Context, chatID int64) error {
-	settings, err := h.deps.GetChatSettings(ctx, chatID)
+func (h *Handler) cmdDailyStatus(ctx context.Context, key StateKey) error {
+	settings, err := h.deps.GetChatSettings(ctx, key)
 	if err != nil {
 		return err
 	}
@@ -25,6 +25,6 @@ func (h *Handler) cmdDailyStatus(ctx context.Context, chatID int64) error {
 		zone = h.deps.DefaultTZ()
 	}
 
-	msg := fmt.Sprintf("Daily status: %s\nTime: %s\nTimezone: %s", status, hhmm, tzLabel(zone))
-	return h.deps.SendMessage(ctx, chatID, msg)
+	msg := fmt.Sprintf("Daily status: %s\nTime: %s\nTimezone: %s\nDifficulty: %s\nMode: %s", status, hhmm, tzLabel(zone), difficultyLabel(settings.Difficulty), dailyModeLabel(settings.DailyMode))
+	return h.deps.SendMessage(ctx, key, msg)
 }
@@ -2,18 +2,21 @@ package commands
 
 import "context"
 
-func (h *Handler) cmdDone(ctx context.Context, chatID int64) error {
-	settings, err := h.deps.GetChatSettings(ctx, chatID)
+func (h *Handler) cmdDone(ctx context.Context, key StateKey) error {
+	settings, err := h.deps.GetChatSettings(ctx, key)
 	if err != nil {
 		return err
 	}
 	if settings.CurrentQuestion == nil {
-		return h.deps.SendMessage(ctx, chatID, "No active question. Use /lc first.")
+		return h.deps.SendMessage(ctx, key, "No active question. Use /lc first.")
 	}
 
-	if err := h.deps.PersistCompletedQuestion(ctx, chatID, *settings.CurrentQuestion); err != nil {
+	if err := h.deps.PersistCompletedQuestion(ctx, key, *settings.CurrentQuestion); err != nil {
+		return err
+	}
+	if advanced, err := h.deps.AdvanceSession(ctx, key, settings.CurrentQuestion.Slug, SessionOutcomeDone); err != nil || advanced {
 		return err
 	}
 
-	return h.deps.SendMessage(ctx, chatID, "Marked as done and saved to your seen/revision history. Send /lc for another question.")
+	return h.deps.SendMessage(ctx, key, "Marked as done and saved to your seen/revision history. Send /lc for another question.")
 }
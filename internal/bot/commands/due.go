@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func (h *Handler) cmdDue(ctx context.Context, key StateKey) error {
+	queue, err := h.deps.ListReviewQueue(ctx, key, 50)
+	if err != nil {
+		return err
+	}
+	if len(queue) == 0 {
+		return h.deps.SendMessage(ctx, key, "No answered questions to revise yet. Complete one first with /lc and /done (or a correct attempt).")
+	}
+
+	now := h.deps.Now()
+	due := make([]AnsweredQuestion, 0, len(queue))
+	for _, item := range queue {
+		if item.DueAt.After(now) {
+			break
+		}
+		due = append(due, item)
+	}
+	if len(due) == 0 {
+		next := queue[0]
+		return h.deps.SendMessage(ctx, key, fmt.Sprintf("Nothing is due for revision. Next up: %s on %s.", next.Title, next.DueAt.UTC().Format("2006-01-02")))
+	}
+
+	lines := make([]string, 0, len(due)*5+4)
+	lines = append(lines, fmt.Sprintf("*🔁 Due for Revision \\(%d\\)*", len(due)), "")
+	for i, item := range due {
+		interval := "not yet graded"
+		if item.IntervalDays > 0 {
+			interval = fmt.Sprintf("%d day(s)", item.IntervalDays)
+		}
+		lines = append(lines,
+			fmt.Sprintf("*%d\\. %s*", i+1, escapeMarkdownV2(item.Title)),
+			fmt.Sprintf("• Slug: `%s`", escapeMarkdownV2Code(item.Slug)),
+			fmt.Sprintf("• Due since: %s", escapeMarkdownV2(item.DueAt.UTC().Format("2006-01-02"))),
+			fmt.Sprintf("• Last interval: %s", escapeMarkdownV2(interval)),
+			"",
+		)
+	}
+	lines = append(lines, escapeMarkdownV2("Use /revise to start the most overdue one, or /revise <slug> for a specific question."))
+
+	return h.deps.SendRichMessage(ctx, key, strings.Join(lines, "\n"))
+}
@@ -2,18 +2,22 @@ package commands
 
 import "context"
 
-func (h *Handler) cmdExit(ctx context.Context, chatID int64) error {
-	settings, err := h.deps.GetChatSettings(ctx, chatID)
+func (h *Handler) cmdExit(ctx context.Context, key StateKey) error {
+	settings, err := h.deps.GetChatSettings(ctx, key)
 	if err != nil {
 		return err
 	}
-	if settings.CurrentQuestion == nil {
-		return h.deps.SendMessage(ctx, chatID, "No active practice mode. Use /lc when you want a question.")
+	if settings.CurrentQuestion != nil {
+		if err := h.deps.ClearCurrentQuestion(ctx, key); err != nil {
+			return err
+		}
 	}
-
-	if err := h.deps.ClearCurrentQuestion(ctx, chatID); err != nil {
+	if ended, err := h.deps.EndSession(ctx, key); err != nil || ended {
 		return err
 	}
+	if settings.CurrentQuestion == nil {
+		return h.deps.SendMessage(ctx, key, "No active practice mode. Use /lc when you want a question.")
+	}
 
-	return h.deps.SendMessage(ctx, chatID, "Exited practice mode. Send /lc when you want another question.")
+	return h.deps.SendMessage(ctx, key, "Exited practice mode. Send /lc when you want another question.")
 }
@@ -15,7 +15,7 @@ func NewHandler(deps Dependencies) *Handler {
 	return &Handler{deps: deps}
 }
 
-func (h *Handler) Handle(ctx context.Context, chatID int64, text string) error {
+func (h *Handler) Handle(ctx context.Context, key StateKey, text string) error {
 	parts := strings.Fields(text)
 	if len(parts) == 0 {
 		return nil
@@ -23,46 +23,70 @@ func (h *Handler) Handle(ctx context.Context, chatID int64, text string) error {
 
 	cmd := normalizeCommand(parts[0])
 	args := parts[1:]
-	h.deps.SetPendingTopicSelection(chatID, false)
+	h.deps.SetPendingTopicSelection(key, false)
 	if isDailyCommand(cmd) && !h.deps.DailySchedulingEnabled() {
-		return h.deps.SendMessage(ctx, chatID, dailySchedulingOffMessage)
+		return h.deps.SendMessage(ctx, key, dailySchedulingOffMessage)
+	}
+	if isDailyCommand(cmd) {
+		// The daily schedule belongs to the chat, not to one group member.
+		key = StateKey{ChatID: key.ChatID}
 	}
 
 	switch cmd {
 	case "/start", "/help":
-		return h.cmdHelp(ctx, chatID)
+		return h.cmdHelp(ctx, key)
 	case "/lc":
-		return h.cmdLC(ctx, chatID, args)
+		return h.cmdLC(ctx, key, args)
 	case "/hint":
-		return h.cmdHint(ctx, chatID, args)
+		return h.cmdHint(ctx, key, args)
 	case "/done":
-		return h.cmdDone(ctx, chatID)
+		return h.cmdDone(ctx, key)
 	case "/skip":
-		return h.cmdSkip(ctx, chatID)
+		return h.cmdSkip(ctx, key)
 	case "/exit":
-		return h.cmdExit(ctx, chatID)
+		return h.cmdExit(ctx, key)
 	case "/delete":
-		return h.cmdDeleteRevisedQuestion(ctx, chatID, args)
+		return h.cmdDeleteRevisedQuestion(ctx, key, args)
 	case "/answered":
-		return h.cmdAnsweredHistory(ctx, chatID, args)
+		return h.cmdAnsweredHistory(ctx, key, args)
 	case "/revise":
-		return h.cmdRevise(ctx, chatID, args)
+		return h.cmdRevise(ctx, key, args)
+	case "/due":
+		return h.cmdDue(ctx, key)
+	case "/attempts":
+		return h.cmdAttempts(ctx, key, args)
+	case "/stats":
+		return h.cmdStats(ctx, key)
+	case "/plan":
+		return h.cmdPlan(ctx, key, args)
+	case "/list":
+		return h.cmdList(ctx, key, args)
+	case "/leaderboard":
+		return h.cmdLeaderboard(ctx, key, args)
+	case "/mock":
+		return h.cmdMock(ctx, key, args)
+	case "/session":
+		return h.cmdSession(ctx, key, args)
 	case "/daily_on":
-		return h.cmdDailyOn(ctx, chatID, args)
+		return h.cmdDailyOn(ctx, key, args)
 	case "/daily_off":
-		return h.cmdDailyOff(ctx, chatID)
+		return h.cmdDailyOff(ctx, key)
 	case "/daily_time":
-		return h.cmdDailyTime(ctx, chatID, args)
+		return h.cmdDailyTime(ctx, key, args)
 	case "/daily_status":
-		return h.cmdDailyStatus(ctx, chatID)
+		return h.cmdDailyStatus(ctx, key)
+	case "/daily_difficulty":
+		return h.cmdDailyDifficulty(ctx, key, args)
+	case "/daily_mode":
+		return h.cmdDailyMode(ctx, key, args)
 	default:
-		return h.deps.SendMessage(ctx, chatID, "Unknown command. Use /help to see available commands.")
+		return h.deps.SendMessage(ctx, key, "Unknown command. Use /help to see available commands.")
 	}
 }
 
 func isDailyCommand(cmd string) bool {
 	switch cmd {
-	case "/daily_on", "/daily_off", "/daily_time", "/daily_status":
+	case "/daily_on", "/daily_off", "/daily_time", "/daily_status", "/daily_difficulty", "/daily_mode":
 		return true
 	default:
 		return false
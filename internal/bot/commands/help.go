@@ -2,24 +2,36 @@ package commands
 
 import "context"
 
-func (h *Handler) cmdHelp(ctx context.Context, chatID int64) error {
-	return h.deps.SendMessage(ctx, chatID, helpText())
+func (h *Handler) cmdHelp(ctx context.Context, key StateKey) error {
+	return h.deps.SendMessage(ctx, key, helpText())
 }
 
 func helpText() string {
 	return `Commands:
-/lc - Get a random LeetCode question
+/lc [easy|medium|hard] [topic] - Get a random LeetCode question
+/plan [blind75|neetcode150|grind75|progress|off] - Follow a study list in order, or show its progress
+/list create|add|use|show - Build a team question list and serve it with /lc and the daily question
+/mock [minutes] [easy|medium|hard] - Start a timed mock interview (default 45 minutes)
+/session start [count] [easy|medium|hard] - Work through 1-6 questions back to back (default 3)
+/session report - Show the scorecard of your latest session
+/session end - Finish the running session now
 /hint [context] - Get a hint for the active question
 /done - Mark current question complete and save it to seen/revision history
 /skip - Skip the current question without adding it to seen history
 /exit - Exit active /lc practice mode
 /delete <slug> - Remove a question from revised history and seen set
 /answered [limit] - List previously answered questions
-/revise [slug] - Revisit an answered question (random if slug omitted)
+/revise [slug] - Revisit an answered question (most overdue if slug omitted)
+/due - List answered questions due for spaced-repetition review
+/stats - Show solved counts, streaks, and average attempts and score
+/attempts [slug] - Show graded attempts for a question (active question if slug omitted)
+/leaderboard [week|month|all] - Rank group members by weighted solves and score
 /daily_on [HH:MM] - Enable daily question in SGT (default 20:00)
 /daily_off - Disable daily question
 /daily_time HH:MM - Set daily time in SGT and enable
 /daily_status - Show current daily schedule
+/daily_difficulty <easy|medium|hard|any> - Set difficulty for the daily question
+/daily_mode <new|revise|mixed> - Choose new questions, due revisions, or both for the daily message
 
 After /lc, send your approach for evaluation.
 Use /hint anytime in active practice mode.`
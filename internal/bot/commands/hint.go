@@ -5,7 +5,7 @@ import (
 	"strings"
 )
 
-func (h *Handler) cmdHint(ctx context.Context, chatID int64, args []string) error {
+func (h *Handler) cmdHint(ctx context.Context, key StateKey, args []string) error {
 	learnerContext := strings.TrimSpace(strings.Join(args, " "))
-	return h.deps.SendHint(ctx, chatID, learnerContext)
+	return h.deps.SendHint(ctx, key, learnerContext)
 }
@@ -5,23 +5,24 @@ import (
 	"strings"
 )
 
-func (h *Handler) cmdLC(ctx context.Context, chatID int64, args []string) error {
+func (h *Handler) cmdLC(ctx context.Context, key StateKey, args []string) error {
 	if len(args) == 0 {
-		h.deps.SetPendingTopicSelection(chatID, true)
-		return h.deps.SendMessage(ctx, chatID, "Which topic do you want to practice? Reply with a topic like array, graph, dp, tree, or enter \"random\".")
+		h.deps.SetPendingTopicSelection(key, true)
+		return h.deps.SendMessage(ctx, key, "Which topic do you want to practice? Reply with a topic like array, graph, dp, tree, or enter \"random\".")
 	}
 
-	topic := strings.TrimSpace(strings.Join(args, " "))
-	if strings.EqualFold(topic, "random") || topic == "" {
+	difficulty, topic := splitDifficulty(args)
+	if strings.EqualFold(topic, "random") {
 		topic = ""
 	}
+	filter := QuestionFilter{Topic: topic, Difficulty: difficulty}
 
-	settings, err := h.deps.GetChatSettings(ctx, chatID)
+	settings, err := h.deps.GetChatSettings(ctx, key)
 	if err != nil {
 		return err
 	}
 	if settings.CurrentQuestion != nil {
-		return h.deps.SendUniqueQuestionByTopic(ctx, chatID, "Here is your random LeetCode question:", topic, settings.CurrentQuestion.Slug)
+		return h.deps.SendUniqueQuestionByFilter(ctx, key, "Here is your random LeetCode question:", filter, settings.CurrentQuestion.Slug)
 	}
-	return h.deps.SendUniqueQuestionByTopic(ctx, chatID, "Here is your random LeetCode question:", topic)
+	return h.deps.SendUniqueQuestionByFilter(ctx, key, "Here is your random LeetCode question:", filter)
 }
@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"context"
+	"strings"
+)
+
+func (h *Handler) cmdLeaderboard(ctx context.Context, key StateKey, args []string) error {
+	period := "week"
+	if len(args) > 0 {
+		period = strings.ToLower(strings.TrimSpace(args[0]))
+	}
+	switch period {
+	case "week", "month", "all":
+		return h.deps.SendLeaderboard(ctx, key, period)
+	default:
+		return h.deps.SendMessage(ctx, key, "Usage: /leaderboard [week|month|all]")
+	}
+}
@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const listUsage = "Usage: /list create <name> | /list add <name> <slug...> | /list use <name|off> | /list show [name]"
+
+// listNamePattern keeps list names short and safe to use as storage keys.
+var listNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// cmdList manages the chat's custom question lists. Lists belong to the
+// whole chat, and /list use makes one the source for /lc and the daily
+// question of every member without a /plan of their own.
+func (h *Handler) cmdList(ctx context.Context, key StateKey, args []string) error {
+	if len(args) == 0 {
+		return h.deps.SendMessage(ctx, key, listUsage)
+	}
+
+	rest := args[1:]
+	switch strings.ToLower(args[0]) {
+	case "create", "new":
+		if len(rest) != 1 {
+			return h.deps.SendMessage(ctx, key, "Usage: /list create <name>")
+		}
+		return h.createQuestionList(ctx, key, rest[0])
+	case "add":
+		if len(rest) < 2 {
+			return h.deps.SendMessage(ctx, key, "Usage: /list add <name> <slug...>")
+		}
+		return h.addToQuestionList(ctx, key, rest[0], rest[1:])
+	case "use":
+		if len(rest) != 1 {
+			return h.deps.SendMessage(ctx, key, "Usage: /list use <name|off>")
+		}
+		return h.useQuestionList(ctx, key, rest[0])
+	case "show", "ls":
+		if len(rest) == 0 {
+			return h.showQuestionLists(ctx, key)
+		}
+		return h.showQuestionList(ctx, key, rest[0])
+	default:
+		return h.deps.SendMessage(ctx, key, listUsage)
+	}
+}
+
+func (h *Handler) createQuestionList(ctx context.Context, key StateKey, raw string) error {
+	name := strings.ToLower(raw)
+	if !listNamePattern.MatchString(name) {
+		return h.deps.SendMessage(ctx, key, "List names use up to 32 lowercase letters, digits, - or _, e.g. week-12.")
+	}
+
+	_, err := h.deps.GetQuestionList(ctx, key.ChatID, name)
+	switch {
+	case err == nil:
+		return h.deps.SendMessage(ctx, key, fmt.Sprintf("List %s already exists. Add questions with /list add %s <slug...>.", name, name))
+	case !h.deps.IsQuestionListNotFound(err):
+		return err
+	}
+
+	now := h.deps.Now()
+	if err := h.deps.SaveQuestionList(ctx, key.ChatID, QuestionList{Name: name, CreatedAt: now, UpdatedAt: now}); err != nil {
+		return err
+	}
+	return h.deps.SendMessage(ctx, key, fmt.Sprintf("Created list %s. Add questions with /list add %s <slug...>, then /list use %s.", name, name, name))
+}
+
+func (h *Handler) addToQuestionList(ctx context.Context, key StateKey, rawName string, refs []string) error {
+	list, ok, err := h.loadQuestionList(ctx, key, rawName)
+	if err != nil || !ok {
+		return err
+	}
+
+	slugs := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if slug := normalizeSlug(ref); slug != "" {
+			slugs = append(slugs, slug)
+		}
+	}
+	found, unknown, err := h.deps.LookupQuestions(ctx, slugs)
+	if err != nil {
+		return err
+	}
+
+	inList := make(map[string]struct{}, len(list.Questions))
+	for _, q := range list.Questions {
+		inList[q.Slug] = struct{}{}
+	}
+	added, duplicates := 0, 0
+	for _, q := range found {
+		if _, ok := inList[q.Slug]; ok {
+			duplicates++
+			continue
+		}
+		inList[q.Slug] = struct{}{}
+		list.Questions = append(list.Questions, q)
+		added++
+	}
+	if added > 0 {
+		list.UpdatedAt = h.deps.Now()
+		if err := h.deps.SaveQuestionList(ctx, key.ChatID, list); err != nil {
+			return err
+		}
+	}
+
+	lines := []string{fmt.Sprintf("Added %d question(s) to %s (%d total).", added, list.Name, len(list.Questions))}
+	if duplicates > 0 {
+		lines = append(lines, fmt.Sprintf("Already in the list: %d.", duplicates))
+	}
+	if len(unknown) > 0 {
+		lines = append(lines, fmt.Sprintf("Unknown questions skipped: %s.", strings.Join(unknown, ", ")))
+	}
+	return h.deps.SendMessage(ctx, key, strings.Join(lines, "\n"))
+}
+
+func (h *Handler) useQuestionList(ctx context.Context, key StateKey, rawName string) error {
+	chatKey := StateKey{ChatID: key.ChatID}
+	switch strings.ToLower(rawName) {
+	case "off", "none", "clear":
+		settings, err := h.deps.GetChatSettings(ctx, chatKey)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(settings.StudyPlan, QuestionListPlanPrefix) {
+			return h.deps.SendMessage(ctx, key, "No question list is in use.")
+		}
+		if err := h.deps.SetStudyPlan(ctx, chatKey, ""); err != nil {
+			return err
+		}
+		return h.deps.SendMessage(ctx, key, "Question list turned off. /lc and the daily question pick random questions again.")
+	}
+
+	list, ok, err := h.loadQuestionList(ctx, key, rawName)
+	if err != nil || !ok {
+		return err
+	}
+	if len(list.Questions) == 0 {
+		return h.deps.SendMessage(ctx, key, fmt.Sprintf("List %s is empty. Add questions with /list add %s <slug...> first.", list.Name, list.Name))
+	}
+	if err := h.deps.SetStudyPlan(ctx, chatKey, QuestionListPlanPrefix+list.Name); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Now using list %s (%d questions). /lc and the daily question serve it in order; /plan progress shows how far you are.", list.Name, len(list.Questions))
+	if key.UserID != 0 {
+		msg += "\nMembers who picked their own /plan keep it until they send /plan off."
+	}
+	return h.deps.SendMessage(ctx, key, msg)
+}
+
+func (h *Handler) showQuestionLists(ctx context.Context, key StateKey) error {
+	lists, err := h.deps.ListQuestionLists(ctx, key.ChatID)
+	if err != nil {
+		return err
+	}
+	if len(lists) == 0 {
+		return h.deps.SendMessage(ctx, key, "No question lists yet. Create one with /list create <name>.")
+	}
+	settings, err := h.deps.GetChatSettings(ctx, StateKey{ChatID: key.ChatID})
+	if err != nil {
+		return err
+	}
+	active, inUse := strings.CutPrefix(settings.StudyPlan, QuestionListPlanPrefix)
+
+	lines := []string{"Question lists:"}
+	for _, list := range lists {
+		line := fmt.Sprintf("%s - %d questions", list.Name, len(list.Questions))
+		if inUse && list.Name == active {
+			line += " (in use)"
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", "Use /list show <name> to see a list's questions.")
+	return h.deps.SendMessage(ctx, key, strings.Join(lines, "\n"))
+}
+
+func (h *Handler) showQuestionList(ctx context.Context, key StateKey, rawName string) error {
+	list, ok, err := h.loadQuestionList(ctx, key, rawName)
+	if err != nil || !ok {
+		return err
+	}
+	if len(list.Questions) == 0 {
+		return h.deps.SendMessage(ctx, key, fmt.Sprintf("List %s is empty. Add questions with /list add %s <slug...>.", list.Name, list.Name))
+	}
+
+	lines := []string{fmt.Sprintf("List %s (%d questions):", list.Name, len(list.Questions))}
+	for i, q := range list.Questions {
+		lines = append(lines, fmt.Sprintf("%d. %s (%s) - %s", i+1, q.Title, q.Difficulty, q.Slug))
+	}
+	return h.deps.SendMessage(ctx, key, strings.Join(lines, "\n"))
+}
+
+// loadQuestionList fetches the chat's named list. It replies and returns
+// false when the list does not exist.
+func (h *Handler) loadQuestionList(ctx context.Context, key StateKey, rawName string) (QuestionList, bool, error) {
+	name := strings.ToLower(rawName)
+	list, err := h.deps.GetQuestionList(ctx, key.ChatID, name)
+	if err == nil {
+		return list, true, nil
+	}
+	if !h.deps.IsQuestionListNotFound(err) {
+		return QuestionList{}, false, err
+	}
+	return QuestionList{}, false, h.deps.SendMessage(ctx, key, fmt.Sprintf("No list named %s. /list show lists this chat's lists.", name))
+}
@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	defaultMockMinutes = 45
+	minMockMinutes     = 5
+	maxMockMinutes     = 120
+)
+
+func (h *Handler) cmdMock(ctx context.Context, key StateKey, args []string) error {
+	minutes, difficulty, ok := parseMockArgs(args)
+	if !ok {
+		return h.deps.SendMessage(ctx, key, fmt.Sprintf("Usage: /mock [minutes] [easy|medium|hard]\nMinutes must be between %d and %d (default %d).", minMockMinutes, maxMockMinutes, defaultMockMinutes))
+	}
+	h.deps.SetPendingTopicSelection(key, false)
+	return h.deps.StartMock(ctx, key, minutes, difficulty)
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const planUsage = "Usage: /plan <name> | /plan progress | /plan off"
+
+func (h *Handler) cmdPlan(ctx context.Context, key StateKey, args []string) error {
+	if len(args) == 0 {
+		settings, err := h.deps.GetChatSettings(ctx, key)
+		if err != nil {
+			return err
+		}
+		active := "none (random questions)"
+		if name, ok := strings.CutPrefix(settings.StudyPlan, QuestionListPlanPrefix); ok {
+			active = "list " + name
+		} else if plan, ok := h.deps.FindStudyPlan(settings.StudyPlan); ok {
+			active = plan.Title
+		}
+		return h.deps.SendMessage(ctx, key, fmt.Sprintf("Study plan: %s\n\n%s\n%s", active, h.studyPlanList(), planUsage))
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "progress":
+		return h.deps.SendPlanProgress(ctx, key)
+	case "off", "none", "clear":
+		if err := h.deps.SetStudyPlan(ctx, key, ""); err != nil {
+			return err
+		}
+		return h.deps.SendMessage(ctx, key, "Study plan cleared. /lc picks random questions again.")
+	}
+
+	plan, ok := h.deps.FindStudyPlan(strings.Join(args, " "))
+	if !ok {
+		return h.deps.SendMessage(ctx, key, fmt.Sprintf("Unknown study plan %q.\n\n%s", strings.Join(args, " "), h.studyPlanList()))
+	}
+	if err := h.deps.SetStudyPlan(ctx, key, plan.Name); err != nil {
+		return err
+	}
+	return h.deps.SendMessage(ctx, key, fmt.Sprintf("Study plan set to %s (%d questions). /lc and /skip now serve its questions in order; /plan progress shows how far you are.", plan.Title, plan.Size))
+}
+
+func (h *Handler) studyPlanList() string {
+	lines := []string{"Available plans:"}
+	for _, plan := range h.deps.StudyPlans() {
+		lines = append(lines, fmt.Sprintf("%s - %s (%d questions). %s", plan.Name, plan.Title, plan.Size, plan.Description))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,52 +2,48 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"strings"
 )
 
-func (h *Handler) cmdRevise(ctx context.Context, chatID int64, args []string) error {
+func (h *Handler) cmdRevise(ctx context.Context, key StateKey, args []string) error {
 	var (
-		q   Question
-		err error
+		q    Question
+		note string
+		err  error
 	)
 
 	if len(args) > 0 {
 		slug := normalizeSlug(strings.Join(args, " "))
 		if slug == "" {
-			return h.deps.SendMessage(ctx, chatID, "Usage: /revise <slug>, e.g. /revise two-sum")
+			return h.deps.SendMessage(ctx, key, "Usage: /revise <slug>, e.g. /revise two-sum")
 		}
-		q, err = h.deps.GetAnsweredQuestion(ctx, chatID, slug)
+		q, err = h.deps.GetAnsweredQuestion(ctx, key, slug)
 		if err != nil {
 			if h.deps.IsAnsweredQuestionNotFound(err) {
-				return h.deps.SendMessage(ctx, chatID, "I couldn't find that slug in your answered history. Use /answered to see available slugs.")
+				return h.deps.SendMessage(ctx, key, "I couldn't find that slug in your answered history. Use /answered to see available slugs.")
 			}
 			return err
 		}
 	} else {
-		items, listErr := h.deps.ListAnsweredQuestions(ctx, chatID, 50)
+		queue, listErr := h.deps.ListReviewQueue(ctx, key, 1)
 		if listErr != nil {
 			return listErr
 		}
-		if len(items) == 0 {
-			return h.deps.SendMessage(ctx, chatID, "No answered questions to revise yet. Complete one first with /lc and /done (or a correct attempt).")
+		if len(queue) == 0 {
+			return h.deps.SendMessage(ctx, key, "No answered questions to revise yet. Complete one first with /lc and /done (or a correct attempt).")
 		}
-		idx := int(h.deps.Now().UnixNano() % int64(len(items)))
-		if idx < 0 {
-			idx = -idx
+		next := queue[0]
+		q = next.Question
+		if next.DueAt.After(h.deps.Now()) {
+			note = fmt.Sprintf("Nothing is due yet. This is the next one on your schedule (due %s).", next.DueAt.UTC().Format("2006-01-02"))
 		}
-		q = items[idx].Question
 	}
 
-	if err := h.deps.SetCurrentQuestion(ctx, chatID, q); err != nil {
+	if err := h.deps.SetCurrentQuestion(ctx, key, q); err != nil {
 		return err
 	}
 
-	prompt, err := h.deps.QuestionPrompt(ctx, q.Slug)
-	if err != nil {
-		h.deps.Logf("revision question prompt lookup failed for slug=%s: %v", q.Slug, err)
-		prompt = ""
-	}
-
-	msg := h.deps.FormatQuestionMessage(ctx, "Revision question from your history:", "", q, prompt)
-	return h.deps.SendRichMessage(ctx, chatID, msg)
+	msg := h.deps.QuestionMessage(ctx, "Revision question from your history:", note, q)
+	return h.deps.SendQuestionMessage(ctx, key, msg)
 }
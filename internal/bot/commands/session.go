@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultSessionQuestions = 3
+	maxSessionQuestions     = 6
+)
+
+func (h *Handler) cmdSession(ctx context.Context, key StateKey, args []string) error {
+	usage := fmt.Sprintf("Usage: /session start [1-%d] [easy|medium|hard], /session report, or /session end", maxSessionQuestions)
+	if len(args) == 0 {
+		return h.deps.SendMessage(ctx, key, usage)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "start":
+		count, difficulty, ok := parseSessionArgs(args[1:])
+		if !ok {
+			return h.deps.SendMessage(ctx, key, usage)
+		}
+		h.deps.SetPendingTopicSelection(key, false)
+		return h.deps.StartSession(ctx, key, count, difficulty)
+	case "report":
+		return h.deps.SendSessionReport(ctx, key)
+	case "end", "stop":
+		ended, err := h.deps.EndSession(ctx, key)
+		if err != nil || ended {
+			return err
+		}
+		return h.deps.SendMessage(ctx, key, "No interview session is running. Start one with /session start.")
+	default:
+		return h.deps.SendMessage(ctx, key, usage)
+	}
+}
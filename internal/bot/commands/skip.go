@@ -2,14 +2,17 @@ package commands
 
 import "context"
 
-func (h *Handler) cmdSkip(ctx context.Context, chatID int64) error {
-	settings, err := h.deps.GetChatSettings(ctx, chatID)
+func (h *Handler) cmdSkip(ctx context.Context, key StateKey) error {
+	settings, err := h.deps.GetChatSettings(ctx, key)
 	if err != nil {
 		return err
 	}
 	if settings.CurrentQuestion == nil {
-		return h.deps.SendMessage(ctx, chatID, "No active question to skip. Use /lc first.")
+		return h.deps.SendMessage(ctx, key, "No active question to skip. Use /lc first.")
 	}
 
-	return h.deps.SendUniqueQuestion(ctx, chatID, "Skipped. Here is another LeetCode question:", settings.CurrentQuestion.Slug)
+	if advanced, err := h.deps.AdvanceSession(ctx, key, settings.CurrentQuestion.Slug, SessionOutcomeSkipped); err != nil || advanced {
+		return err
+	}
+	return h.deps.SendUniqueQuestion(ctx, key, "Skipped. Here is another LeetCode question:", settings.CurrentQuestion.Slug)
 }
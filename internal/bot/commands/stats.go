@@ -0,0 +1,7 @@
+package commands
+
+import "context"
+
+func (h *Handler) cmdStats(ctx context.Context, key StateKey) error {
+	return h.deps.SendStats(ctx, key)
+}
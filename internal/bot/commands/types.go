@@ -5,6 +5,13 @@ import (
 	"time"
 )
 
+// StateKey mirrors bot.StateKey. Replies go to ChatID; UserID selects a group
+// member's own state and is zero in private chats.
+type StateKey struct {
+	ChatID int64
+	UserID int64
+}
+
 type Question struct {
 	Slug       string
 	Title      string
@@ -19,6 +26,50 @@ type ChatSettings struct {
 	Timezone        string
 	CurrentQuestion *Question
 	LastDailySentOn string
+	Difficulty      string
+	DailyMode       string
+	StudyPlan       string
+}
+
+type AnswerAttempt struct {
+	Slug      string
+	Answer    string
+	Score     int
+	Source    string
+	Feedback  string
+	CreatedAt time.Time
+}
+
+// Session outcomes mirror the bot package values.
+const (
+	SessionOutcomeDone    = "done"
+	SessionOutcomeSkipped = "skipped"
+)
+
+// QuestionFilter narrows question selection. Empty fields match everything.
+type QuestionFilter struct {
+	Topic      string
+	Difficulty string
+}
+
+// StudyPlan describes a bundled study list; Size is its question count.
+type StudyPlan struct {
+	Name        string
+	Title       string
+	Description string
+	Size        int
+}
+
+// QuestionListPlanPrefix mirrors the bot package value that marks a custom
+// question list in ChatSettings.StudyPlan.
+const QuestionListPlanPrefix = "list:"
+
+// QuestionList is a custom question list shared by a chat's members.
+type QuestionList struct {
+	Name      string
+	Questions []Question
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type AnsweredQuestion struct {
@@ -26,27 +77,55 @@ type AnsweredQuestion struct {
 	FirstAnsweredAt time.Time
 	LastAnsweredAt  time.Time
 	Attempts        int
+	DueAt           time.Time
+	IntervalDays    int
 }
 
 type Dependencies interface {
-	SendMessage(ctx context.Context, chatID int64, text string) error
-	SendRichMessage(ctx context.Context, chatID int64, text string) error
-
-	GetChatSettings(ctx context.Context, chatID int64) (ChatSettings, error)
-	UpsertDailySettings(ctx context.Context, chatID int64, enabled bool, hhmm, tz string) error
-	SetCurrentQuestion(ctx context.Context, chatID int64, q Question) error
-	ClearCurrentQuestion(ctx context.Context, chatID int64) error
-	DeleteAnsweredQuestion(ctx context.Context, chatID int64, slug string) error
-	RemoveServedQuestion(ctx context.Context, chatID int64, slug string) error
-	ListAnsweredQuestions(ctx context.Context, chatID int64, limit int) ([]AnsweredQuestion, error)
-	GetAnsweredQuestion(ctx context.Context, chatID int64, slug string) (Question, error)
-
-	QuestionPrompt(ctx context.Context, slug string) (string, error)
-	SendUniqueQuestion(ctx context.Context, chatID int64, intro string, transientExclude ...string) error
-	SendUniqueQuestionByTopic(ctx context.Context, chatID int64, intro, topic string, transientExclude ...string) error
-	PersistCompletedQuestion(ctx context.Context, chatID int64, q Question) error
-	SendHint(ctx context.Context, chatID int64, learnerContext string) error
-	SetPendingTopicSelection(chatID int64, pending bool)
+	SendMessage(ctx context.Context, key StateKey, text string) error
+	SendRichMessage(ctx context.Context, key StateKey, text string) error
+	SendQuestionMessage(ctx context.Context, key StateKey, text string) error
+
+	GetChatSettings(ctx context.Context, key StateKey) (ChatSettings, error)
+	UpsertDailySettings(ctx context.Context, key StateKey, enabled bool, hhmm, tz string) error
+	SetDifficultyPreference(ctx context.Context, key StateKey, difficulty string) error
+	SetDailyMode(ctx context.Context, key StateKey, mode string) error
+	SetStudyPlan(ctx context.Context, key StateKey, name string) error
+	SetCurrentQuestion(ctx context.Context, key StateKey, q Question) error
+	ClearCurrentQuestion(ctx context.Context, key StateKey) error
+	DeleteAnsweredQuestion(ctx context.Context, key StateKey, slug string) error
+	RemoveServedQuestion(ctx context.Context, key StateKey, slug string) error
+	ListAnsweredQuestions(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error)
+	GetAnsweredQuestion(ctx context.Context, key StateKey, slug string) (Question, error)
+	ListReviewQueue(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error)
+	ListAnswerAttempts(ctx context.Context, key StateKey, slug string, limit int) ([]AnswerAttempt, error)
+	SaveQuestionList(ctx context.Context, chatID int64, list QuestionList) error
+	GetQuestionList(ctx context.Context, chatID int64, name string) (QuestionList, error)
+	ListQuestionLists(ctx context.Context, chatID int64) ([]QuestionList, error)
+	// LookupQuestions matches slugs against the question source, keeping
+	// input order, and returns the slugs it does not know in unknown.
+	LookupQuestions(ctx context.Context, slugs []string) (found []Question, unknown []string, err error)
+
+	SendUniqueQuestion(ctx context.Context, key StateKey, intro string, transientExclude ...string) error
+	SendUniqueQuestionByFilter(ctx context.Context, key StateKey, intro string, filter QuestionFilter, transientExclude ...string) error
+	PersistCompletedQuestion(ctx context.Context, key StateKey, q Question) error
+	SendHint(ctx context.Context, key StateKey, learnerContext string) error
+	SendStats(ctx context.Context, key StateKey) error
+	SendPlanProgress(ctx context.Context, key StateKey) error
+	SendLeaderboard(ctx context.Context, key StateKey, period string) error
+	StartMock(ctx context.Context, key StateKey, minutes int, difficulty string) error
+	StartSession(ctx context.Context, key StateKey, count int, difficulty string) error
+	// AdvanceSession moves a running interview session past slug. It reports
+	// false when slug is not the question the session is waiting on.
+	AdvanceSession(ctx context.Context, key StateKey, slug, outcome string) (bool, error)
+	// EndSession closes a running interview session early and reports false
+	// when none is running.
+	EndSession(ctx context.Context, key StateKey) (bool, error)
+	SendSessionReport(ctx context.Context, key StateKey) error
+	SetPendingTopicSelection(key StateKey, pending bool)
+	StudyPlans() []StudyPlan
+	// FindStudyPlan resolves a plan by name or title, loosely matched.
+	FindStudyPlan(name string) (StudyPlan, bool)
 
 	Now() time.Time
 	DefaultDailyHH() string
@@ -54,5 +133,7 @@ type Dependencies interface {
 	DailySchedulingEnabled() bool
 	Logf(format string, args ...any)
 	IsAnsweredQuestionNotFound(err error) bool
-	FormatQuestionMessage(ctx context.Context, intro, note string, q Question, prompt string) string
+	IsQuestionListNotFound(err error) bool
+	// QuestionMessage fetches q's statement and renders the question message.
+	QuestionMessage(ctx context.Context, intro, note string, q Question) string
 }
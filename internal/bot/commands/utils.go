@@ -59,6 +59,85 @@ func normalizeSlug(raw string) string {
 	return strings.Trim(v, " `/\\\"'<>[](){}.,;:|")
 }
 
+// parseDifficulty maps user input onto LeetCode's difficulty labels.
+func parseDifficulty(raw string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "easy":
+		return "Easy", true
+	case "medium", "med":
+		return "Medium", true
+	case "hard":
+		return "Hard", true
+	default:
+		return "", false
+	}
+}
+
+// splitDifficulty pulls a difficulty keyword out of /lc arguments and returns
+// the remaining words as the topic.
+func splitDifficulty(args []string) (difficulty, topic string) {
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if d, ok := parseDifficulty(arg); ok && difficulty == "" {
+			difficulty = d
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return difficulty, strings.TrimSpace(strings.Join(rest, " "))
+}
+
+// parseMockArgs reads /mock arguments in either order: an optional length in
+// minutes and an optional difficulty.
+func parseMockArgs(args []string) (minutes int, difficulty string, ok bool) {
+	minutes = defaultMockMinutes
+	seenMinutes := false
+	for _, arg := range args {
+		if n, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(arg), "m")); err == nil && !seenMinutes {
+			if n < minMockMinutes || n > maxMockMinutes {
+				return 0, "", false
+			}
+			minutes, seenMinutes = n, true
+			continue
+		}
+		if d, valid := parseDifficulty(arg); valid && difficulty == "" {
+			difficulty = d
+			continue
+		}
+		return 0, "", false
+	}
+	return minutes, difficulty, true
+}
+
+// parseSessionArgs reads an optional question count and difficulty in
+// either order.
+func parseSessionArgs(args []string) (count int, difficulty string, ok bool) {
+	count = defaultSessionQuestions
+	seenCount := false
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil && !seenCount {
+			if n < 1 || n > maxSessionQuestions {
+				return 0, "", false
+			}
+			count, seenCount = n, true
+			continue
+		}
+		if d, valid := parseDifficulty(arg); valid && difficulty == "" {
+			difficulty = d
+			continue
+		}
+		return 0, "", false
+	}
+	return count, difficulty, true
+}
+
+func difficultyLabel(difficulty string) string {
+	if difficulty == "" {
+		return "Any"
+	}
+	return difficulty
+}
+
 func tzLabel(tz string) string {
 	if tz == "Asia/Singapore" {
 		return "SGT"
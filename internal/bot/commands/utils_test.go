@@ -23,3 +23,78 @@ func TestNormalizeSlug(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitDifficulty(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantDifficulty string
+		wantTopic      string
+	}{
+		{name: "difficulty only", args: []string{"medium"}, wantDifficulty: "Medium"},
+		{name: "difficulty then topic", args: []string{"Hard", "graph"}, wantDifficulty: "Hard", wantTopic: "graph"},
+		{name: "topic then difficulty", args: []string{"sliding", "window", "easy"}, wantDifficulty: "Easy", wantTopic: "sliding window"},
+		{name: "topic only", args: []string{"dp"}, wantTopic: "dp"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			difficulty, topic := splitDifficulty(tc.args)
+			if difficulty != tc.wantDifficulty || topic != tc.wantTopic {
+				t.Fatalf("splitDifficulty(%v) = (%q, %q), want (%q, %q)", tc.args, difficulty, topic, tc.wantDifficulty, tc.wantTopic)
+			}
+		})
+	}
+}
+
+func TestParseMockArgs(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantMinutes    int
+		wantDifficulty string
+		wantOK         bool
+	}{
+		{name: "defaults", args: nil, wantMinutes: defaultMockMinutes, wantOK: true},
+		{name: "minutes then difficulty", args: []string{"30", "hard"}, wantMinutes: 30, wantDifficulty: "Hard", wantOK: true},
+		{name: "difficulty then minutes suffix", args: []string{"medium", "20m"}, wantMinutes: 20, wantDifficulty: "Medium", wantOK: true},
+		{name: "too short", args: []string{"2"}},
+		{name: "too long", args: []string{"600"}},
+		{name: "unknown word", args: []string{"graph"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			minutes, difficulty, ok := parseMockArgs(tc.args)
+			if ok != tc.wantOK || (ok && (minutes != tc.wantMinutes || difficulty != tc.wantDifficulty)) {
+				t.Fatalf("parseMockArgs(%v) = (%d, %q, %t), want (%d, %q, %t)", tc.args, minutes, difficulty, ok, tc.wantMinutes, tc.wantDifficulty, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseSessionArgs(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantCount      int
+		wantDifficulty string
+		wantOK         bool
+	}{
+		{name: "defaults", args: nil, wantCount: defaultSessionQuestions, wantOK: true},
+		{name: "count and difficulty", args: []string{"3", "medium"}, wantCount: 3, wantDifficulty: "Medium", wantOK: true},
+		{name: "difficulty first", args: []string{"hard", "2"}, wantCount: 2, wantDifficulty: "Hard", wantOK: true},
+		{name: "zero questions", args: []string{"0"}},
+		{name: "too many questions", args: []string{"10"}},
+		{name: "unknown word", args: []string{"arrays"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			count, difficulty, ok := parseSessionArgs(tc.args)
+			if ok != tc.wantOK || (ok && (count != tc.wantCount || difficulty != tc.wantDifficulty)) {
+				t.Fatalf("parseSessionArgs(%v) = (%d, %q, %t), want (%d, %q, %t)", tc.args, count, difficulty, ok, tc.wantCount, tc.wantDifficulty, tc.wantOK)
+			}
+		})
+	}
+}
@@ -16,89 +16,211 @@ func newCommandHandler(service *Service) *commands.Handler {
 	return commands.NewHandler(&commandDeps{service: service})
 }
 
-func (d *commandDeps) SendMessage(ctx context.Context, chatID int64, text string) error {
-	return d.service.tgClient.SendMessage(ctx, chatID, text)
+func (d *commandDeps) SendMessage(ctx context.Context, key commands.StateKey, text string) error {
+	return d.service.tgClient.SendMessage(ctx, key.ChatID, text)
 }
 
-func (d *commandDeps) SendRichMessage(ctx context.Context, chatID int64, text string) error {
-	return d.service.tgClient.SendRichMessage(ctx, chatID, text)
+func (d *commandDeps) SendRichMessage(ctx context.Context, key commands.StateKey, text string) error {
+	return d.service.tgClient.SendRichMessage(ctx, key.ChatID, text)
 }
 
-func (d *commandDeps) GetChatSettings(ctx context.Context, chatID int64) (commands.ChatSettings, error) {
-	settings, err := d.service.store.GetChatSettings(ctx, chatID)
+func (d *commandDeps) SendQuestionMessage(ctx context.Context, key commands.StateKey, text string) error {
+	return d.service.sendQuestionMessage(ctx, StateKey(key), text)
+}
+
+func (d *commandDeps) GetChatSettings(ctx context.Context, key commands.StateKey) (commands.ChatSettings, error) {
+	settings, err := d.service.chatSettings(ctx, StateKey(key))
 	if err != nil {
 		return commands.ChatSettings{}, err
 	}
 	return toCommandChatSettings(settings), nil
 }
 
-func (d *commandDeps) UpsertDailySettings(ctx context.Context, chatID int64, enabled bool, hhmm, tz string) error {
-	return d.service.store.UpsertDailySettings(ctx, chatID, enabled, hhmm, tz)
+func (d *commandDeps) UpsertDailySettings(ctx context.Context, key commands.StateKey, enabled bool, hhmm, tz string) error {
+	return d.service.store.UpsertDailySettings(ctx, StateKey(key), enabled, hhmm, tz)
+}
+
+func (d *commandDeps) SetDifficultyPreference(ctx context.Context, key commands.StateKey, difficulty string) error {
+	return d.service.store.SetDifficultyPreference(ctx, StateKey(key), difficulty)
+}
+
+func (d *commandDeps) SetStudyPlan(ctx context.Context, key commands.StateKey, name string) error {
+	return d.service.store.SetStudyPlan(ctx, StateKey(key), name)
 }
 
-func (d *commandDeps) SetCurrentQuestion(ctx context.Context, chatID int64, q commands.Question) error {
-	return d.service.store.SetCurrentQuestion(ctx, chatID, fromCommandQuestion(q))
+func (d *commandDeps) SetDailyMode(ctx context.Context, key commands.StateKey, mode string) error {
+	return d.service.store.SetDailyMode(ctx, StateKey(key), mode)
 }
 
-func (d *commandDeps) ClearCurrentQuestion(ctx context.Context, chatID int64) error {
-	return d.service.store.ClearCurrentQuestion(ctx, chatID)
+func (d *commandDeps) SetCurrentQuestion(ctx context.Context, key commands.StateKey, q commands.Question) error {
+	return d.service.store.SetCurrentQuestion(ctx, StateKey(key), fromCommandQuestion(q))
 }
 
-func (d *commandDeps) DeleteAnsweredQuestion(ctx context.Context, chatID int64, slug string) error {
-	return d.service.store.DeleteAnsweredQuestion(ctx, chatID, slug)
+func (d *commandDeps) ClearCurrentQuestion(ctx context.Context, key commands.StateKey) error {
+	return d.service.clearCurrentQuestion(ctx, StateKey(key))
 }
 
-func (d *commandDeps) RemoveServedQuestion(ctx context.Context, chatID int64, slug string) error {
-	return d.service.store.RemoveServedQuestion(ctx, chatID, slug)
+func (d *commandDeps) DeleteAnsweredQuestion(ctx context.Context, key commands.StateKey, slug string) error {
+	return d.service.store.DeleteAnsweredQuestion(ctx, StateKey(key), slug)
 }
 
-func (d *commandDeps) ListAnsweredQuestions(ctx context.Context, chatID int64, limit int) ([]commands.AnsweredQuestion, error) {
-	items, err := d.service.store.ListAnsweredQuestions(ctx, chatID, limit)
+func (d *commandDeps) RemoveServedQuestion(ctx context.Context, key commands.StateKey, slug string) error {
+	return d.service.store.RemoveServedQuestion(ctx, StateKey(key), slug)
+}
+
+func (d *commandDeps) ListAnsweredQuestions(ctx context.Context, key commands.StateKey, limit int) ([]commands.AnsweredQuestion, error) {
+	items, err := d.service.store.ListAnsweredQuestions(ctx, StateKey(key), limit)
 	if err != nil {
 		return nil, err
 	}
-	out := make([]commands.AnsweredQuestion, 0, len(items))
+	return toCommandAnsweredQuestions(items), nil
+}
+
+func (d *commandDeps) ListReviewQueue(ctx context.Context, key commands.StateKey, limit int) ([]commands.AnsweredQuestion, error) {
+	items, err := d.service.store.ListReviewQueue(ctx, StateKey(key), limit)
+	if err != nil {
+		return nil, err
+	}
+	return toCommandAnsweredQuestions(items), nil
+}
+
+func (d *commandDeps) ListAnswerAttempts(ctx context.Context, key commands.StateKey, slug string, limit int) ([]commands.AnswerAttempt, error) {
+	items, err := d.service.store.ListAnswerAttempts(ctx, StateKey(key), slug, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]commands.AnswerAttempt, 0, len(items))
 	for _, item := range items {
-		out = append(out, commands.AnsweredQuestion{
-			Question:        toCommandQuestion(item.Question),
-			FirstAnsweredAt: item.FirstAnsweredAt,
-			LastAnsweredAt:  item.LastAnsweredAt,
-			Attempts:        item.Attempts,
-		})
+		out = append(out, commands.AnswerAttempt(item))
 	}
 	return out, nil
 }
 
-func (d *commandDeps) GetAnsweredQuestion(ctx context.Context, chatID int64, slug string) (commands.Question, error) {
-	q, err := d.service.store.GetAnsweredQuestion(ctx, chatID, slug)
+func (d *commandDeps) GetAnsweredQuestion(ctx context.Context, key commands.StateKey, slug string) (commands.Question, error) {
+	q, err := d.service.store.GetAnsweredQuestion(ctx, StateKey(key), slug)
 	if err != nil {
 		return commands.Question{}, err
 	}
 	return toCommandQuestion(q), nil
 }
 
-func (d *commandDeps) QuestionPrompt(ctx context.Context, slug string) (string, error) {
-	return d.service.questions.QuestionPrompt(ctx, slug)
+func (d *commandDeps) SendUniqueQuestion(ctx context.Context, key commands.StateKey, intro string, transientExclude ...string) error {
+	return d.service.sendUniqueQuestion(ctx, StateKey(key), intro, transientExclude...)
+}
+
+func (d *commandDeps) SendUniqueQuestionByFilter(ctx context.Context, key commands.StateKey, intro string, filter commands.QuestionFilter, transientExclude ...string) error {
+	return d.service.sendFilteredQuestion(ctx, StateKey(key), intro, questionFilter{Topic: filter.Topic, Difficulty: filter.Difficulty}, transientExclude...)
 }
 
-func (d *commandDeps) SendUniqueQuestion(ctx context.Context, chatID int64, intro string, transientExclude ...string) error {
-	return d.service.sendUniqueQuestion(ctx, chatID, intro, transientExclude...)
+// PersistCompletedQuestion saves a question finished with /done, which counts
+// as a bare pass on the revision schedule so a due review moves on.
+func (d *commandDeps) PersistCompletedQuestion(ctx context.Context, key commands.StateKey, q commands.Question) error {
+	question := fromCommandQuestion(q)
+	if err := d.service.persistCompletedQuestion(ctx, StateKey(key), question, 0); err != nil {
+		return err
+	}
+	d.service.recordReview(ctx, StateKey(key), question, correctAnswerScoreThreshold)
+	return nil
+}
+
+func (d *commandDeps) SendHint(ctx context.Context, key commands.StateKey, learnerContext string) error {
+	return d.service.sendHintForChat(ctx, StateKey(key), learnerContext)
+}
+
+func (d *commandDeps) SaveQuestionList(ctx context.Context, chatID int64, list commands.QuestionList) error {
+	out := QuestionList{
+		Name:      list.Name,
+		CreatedAt: list.CreatedAt,
+		UpdatedAt: list.UpdatedAt,
+	}
+	for _, q := range list.Questions {
+		out.Questions = append(out.Questions, fromCommandQuestion(q))
+	}
+	return d.service.store.SaveQuestionList(ctx, chatID, out)
 }
 
-func (d *commandDeps) SendUniqueQuestionByTopic(ctx context.Context, chatID int64, intro, topic string, transientExclude ...string) error {
-	return d.service.sendUniqueQuestionByTopic(ctx, chatID, intro, topic, transientExclude...)
+func (d *commandDeps) GetQuestionList(ctx context.Context, chatID int64, name string) (commands.QuestionList, error) {
+	list, err := d.service.store.GetQuestionList(ctx, chatID, name)
+	if err != nil {
+		return commands.QuestionList{}, err
+	}
+	return toCommandQuestionList(list), nil
 }
 
-func (d *commandDeps) PersistCompletedQuestion(ctx context.Context, chatID int64, q commands.Question) error {
-	return d.service.persistCompletedQuestion(ctx, chatID, fromCommandQuestion(q))
+func (d *commandDeps) ListQuestionLists(ctx context.Context, chatID int64) ([]commands.QuestionList, error) {
+	lists, err := d.service.store.ListQuestionLists(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]commands.QuestionList, 0, len(lists))
+	for _, list := range lists {
+		out = append(out, toCommandQuestionList(list))
+	}
+	return out, nil
 }
 
-func (d *commandDeps) SendHint(ctx context.Context, chatID int64, learnerContext string) error {
-	return d.service.sendHintForChat(ctx, chatID, learnerContext)
+func (d *commandDeps) LookupQuestions(ctx context.Context, slugs []string) ([]commands.Question, []string, error) {
+	found, unknown, err := d.service.lookupQuestions(ctx, slugs)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := make([]commands.Question, 0, len(found))
+	for _, q := range found {
+		out = append(out, toCommandQuestion(q))
+	}
+	return out, unknown, nil
 }
 
-func (d *commandDeps) SetPendingTopicSelection(chatID int64, pending bool) {
-	d.service.setPendingTopicSelection(chatID, pending)
+func (d *commandDeps) SendPlanProgress(ctx context.Context, key commands.StateKey) error {
+	return d.service.sendPlanProgress(ctx, StateKey(key))
+}
+
+func (d *commandDeps) StudyPlans() []commands.StudyPlan {
+	out := make([]commands.StudyPlan, 0, len(studyPlans))
+	for _, plan := range studyPlans {
+		out = append(out, toCommandStudyPlan(plan))
+	}
+	return out
+}
+
+func (d *commandDeps) FindStudyPlan(name string) (commands.StudyPlan, bool) {
+	plan, ok := lookupStudyPlan(name)
+	if !ok {
+		return commands.StudyPlan{}, false
+	}
+	return toCommandStudyPlan(plan), true
+}
+
+func (d *commandDeps) SendStats(ctx context.Context, key commands.StateKey) error {
+	return d.service.sendStats(ctx, StateKey(key))
+}
+
+func (d *commandDeps) SendLeaderboard(ctx context.Context, key commands.StateKey, period string) error {
+	return d.service.sendLeaderboard(ctx, StateKey(key), period)
+}
+
+func (d *commandDeps) StartMock(ctx context.Context, key commands.StateKey, minutes int, difficulty string) error {
+	return d.service.startMock(ctx, StateKey(key), minutes, difficulty)
+}
+
+func (d *commandDeps) StartSession(ctx context.Context, key commands.StateKey, count int, difficulty string) error {
+	return d.service.startSession(ctx, StateKey(key), count, difficulty)
+}
+
+func (d *commandDeps) AdvanceSession(ctx context.Context, key commands.StateKey, slug, outcome string) (bool, error) {
+	return d.service.advanceSession(ctx, StateKey(key), slug, outcome)
+}
+
+func (d *commandDeps) EndSession(ctx context.Context, key commands.StateKey) (bool, error) {
+	return d.service.endSession(ctx, StateKey(key))
+}
+
+func (d *commandDeps) SendSessionReport(ctx context.Context, key commands.StateKey) error {
+	return d.service.sendSessionReport(ctx, StateKey(key))
+}
+
+func (d *commandDeps) SetPendingTopicSelection(key commands.StateKey, pending bool) {
+	d.service.setPendingTopicSelection(StateKey(key), pending)
 }
 
 func (d *commandDeps) Now() time.Time {
@@ -125,9 +247,12 @@ func (d *commandDeps) IsAnsweredQuestionNotFound(err error) bool {
 	return errors.Is(err, ErrAnsweredQuestionNotFound)
 }
 
-func (d *commandDeps) FormatQuestionMessage(ctx context.Context, intro, note string, q commands.Question, prompt string) string {
-	prompt = d.service.formatQuestionPrompt(ctx, fromCommandQuestion(q), prompt)
-	return formatQuestionMessage(intro, note, fromCommandQuestion(q), prompt)
+func (d *commandDeps) IsQuestionListNotFound(err error) bool {
+	return errors.Is(err, ErrQuestionListNotFound)
+}
+
+func (d *commandDeps) QuestionMessage(ctx context.Context, intro, note string, q commands.Question) string {
+	return d.service.questionMessage(ctx, intro, note, fromCommandQuestion(q))
 }
 
 func toCommandQuestion(q Question) commands.Question {
@@ -139,6 +264,21 @@ func toCommandQuestion(q Question) commands.Question {
 	}
 }
 
+func toCommandAnsweredQuestions(items []AnsweredQuestion) []commands.AnsweredQuestion {
+	out := make([]commands.AnsweredQuestion, 0, len(items))
+	for _, item := range items {
+		out = append(out, commands.AnsweredQuestion{
+			Question:        toCommandQuestion(item.Question),
+			FirstAnsweredAt: item.FirstAnsweredAt,
+			LastAnsweredAt:  item.LastAnsweredAt,
+			Attempts:        item.Attempts,
+			DueAt:           item.Review.DueAt,
+			IntervalDays:    item.Review.IntervalDays,
+		})
+	}
+	return out
+}
+
 func fromCommandQuestion(q commands.Question) Question {
 	return Question{
 		Slug:       q.Slug,
@@ -148,6 +288,27 @@ func fromCommandQuestion(q commands.Question) Question {
 	}
 }
 
+func toCommandStudyPlan(plan StudyPlan) commands.StudyPlan {
+	return commands.StudyPlan{
+		Name:        plan.Name,
+		Title:       plan.Title,
+		Description: plan.Description,
+		Size:        len(plan.Questions),
+	}
+}
+
+func toCommandQuestionList(in QuestionList) commands.QuestionList {
+	out := commands.QuestionList{
+		Name:      in.Name,
+		CreatedAt: in.CreatedAt,
+		UpdatedAt: in.UpdatedAt,
+	}
+	for _, q := range in.Questions {
+		out.Questions = append(out.Questions, toCommandQuestion(q))
+	}
+	return out
+}
+
 func toCommandChatSettings(in ChatSettings) commands.ChatSettings {
 	out := commands.ChatSettings{
 		ChatID:          in.ChatID,
@@ -155,6 +316,9 @@ func toCommandChatSettings(in ChatSettings) commands.ChatSettings {
 		DailyTime:       in.DailyTime,
 		Timezone:        in.Timezone,
 		LastDailySentOn: in.LastDailySentOn,
+		Difficulty:      in.Difficulty,
+		DailyMode:       in.DailyMode,
+		StudyPlan:       in.StudyPlan,
 	}
 	if in.CurrentQuestion != nil {
 		q := toCommandQuestion(*in.CurrentQuestion)
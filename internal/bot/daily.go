@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+)
+
+const dailyIntro = "Daily LeetCode challenge:"
+
+// sendDaily delivers the scheduled question for one chat according to its
+// DailyMode. Revise mode falls back to a new question when nothing is due so
+// the chat still gets something every day. Groups always get a new question:
+// the group key has no answered history to revise.
+func (s *Service) sendDaily(ctx context.Context, chat ChatSettings) error {
+	key := ChatKey(chat.ChatID)
+	mode := chat.DailyMode
+	if isGroupChatID(chat.ChatID) {
+		mode = DailyModeNew
+	}
+	sendNew := func() error {
+		if err := s.sendFilteredQuestion(ctx, key, dailyIntro, questionFilter{Difficulty: chat.Difficulty}); err != nil {
+			return err
+		}
+		return s.markGroupDailyServed(ctx, key)
+	}
+
+	switch mode {
+	case DailyModeRevise:
+		due, err := s.nextDueRevision(ctx, key)
+		if err != nil {
+			return err
+		}
+		if due == nil {
+			return sendNew()
+		}
+		return s.sendRevisionQuestion(ctx, key, dailyIntro, "Daily revision: this one is due from your answered history.", *due)
+	case DailyModeMixed:
+		if err := sendNew(); err != nil {
+			return err
+		}
+		due, err := s.nextDueRevision(ctx, key)
+		if err != nil {
+			s.logger.Printf("daily revision lookup failed for chat %d: %v", chat.ChatID, err)
+			return nil
+		}
+		if due == nil {
+			return nil
+		}
+		// Only one question can be active, so the revision is a reminder the
+		// learner picks up with /revise after the new question.
+		reminder := fmt.Sprintf("🔁 Also due for revision today: %s (%s). Send /revise when you're done with the question above.", due.Title, due.Difficulty)
+		return s.tgClient.SendMessage(ctx, chat.ChatID, reminder)
+	default:
+		return sendNew()
+	}
+}
+
+// markGroupDailyServed records the question just posted to a group as served
+// on the group key. Members solve it under their own keys, so without this
+// the group's plan or list would post the same first item every day.
+func (s *Service) markGroupDailyServed(ctx context.Context, key StateKey) error {
+	if !isGroupChatID(key.ChatID) {
+		return nil
+	}
+	settings, err := s.store.GetChatSettings(ctx, key)
+	if err != nil || settings.CurrentQuestion == nil {
+		return err
+	}
+	return s.store.AddServedQuestion(ctx, key, *settings.CurrentQuestion)
+}
+
+// nextDueRevision returns the most overdue answered question, or nil when
+// nothing is due yet.
+func (s *Service) nextDueRevision(ctx context.Context, key StateKey) (*Question, error) {
+	queue, err := s.store.ListReviewQueue(ctx, key, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(queue) == 0 || queue[0].Review.DueAt.After(s.nowFn()) {
+		return nil, nil
+	}
+	q := queue[0].Question
+	return &q, nil
+}
+
+func (s *Service) sendRevisionQuestion(ctx context.Context, key StateKey, intro, note string, q Question) error {
+	if err := s.store.SetCurrentQuestion(ctx, key, q); err != nil {
+		return err
+	}
+
+	msg := s.questionMessage(ctx, intro, note, q)
+	return s.sendQuestionMessage(ctx, key, msg)
+}
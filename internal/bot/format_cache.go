@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+)
+
+const defaultFormatCacheSize = 256
+
+// formatCache is a size-bounded LRU of AI-formatted statements. Keys change
+// whenever the slug, model or raw statement does, so entries never expire.
+type formatCache struct {
+	capacity int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type formatEntry struct {
+	key  string
+	text string
+}
+
+func newFormatCache(capacity int) *formatCache {
+	return &formatCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *formatCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*formatEntry).text, true
+}
+
+func (c *formatCache) put(key, text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*formatEntry).text = text
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&formatEntry{key: key, text: text})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*formatEntry).key)
+	}
+}
+
+// formatCacheKey is the slug followed by a hash of the model and the raw
+// statement, which keeps it readable and safe as a document ID.
+func formatCacheKey(slug, model, prompt string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + prompt))
+	return slug + "-" + hex.EncodeToString(sum[:12])
+}
+
+// SetFormatCache resizes the in-process cache of formatted statements and,
+// with persist, also keeps them in the store when it supports that.
+func (s *Service) SetFormatCache(size int, persist bool) {
+	if size <= 0 {
+		size = defaultFormatCacheSize
+	}
+	s.formatCache = newFormatCache(size)
+	s.formatStore = nil
+	if persist {
+		if store, ok := s.store.(FormattedPromptStore); ok {
+			s.formatStore = store
+		} else {
+			s.logger.Printf("state store cannot persist formatted questions; caching in memory only")
+		}
+	}
+}
+
+// cachedFormattedPrompt looks key up in memory, then in the store.
+func (s *Service) cachedFormattedPrompt(ctx context.Context, key string) (string, bool) {
+	if text, ok := s.formatCache.get(key); ok {
+		return text, true
+	}
+	if s.formatStore == nil {
+		return "", false
+	}
+	prompt, err := s.formatStore.GetFormattedPrompt(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrFormattedPromptNotFound) {
+			s.logger.Printf("formatted question lookup failed for key=%s: %v", key, err)
+		}
+		return "", false
+	}
+	s.formatCache.put(key, prompt.Text)
+	return prompt.Text, true
+}
+
+func (s *Service) storeFormattedPrompt(ctx context.Context, prompt FormattedPrompt) {
+	s.formatCache.put(prompt.Key, prompt.Text)
+	if s.formatStore == nil {
+		return
+	}
+	prompt.CreatedAt = s.nowFn()
+	if err := s.formatStore.SaveFormattedPrompt(ctx, prompt); err != nil {
+		s.logger.Printf("save formatted question failed for slug=%s: %v", prompt.Slug, err)
+	}
+}
@@ -0,0 +1,35 @@
+package bot
+
+import "testing"
+
+func TestFormatCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newFormatCache(2)
+	cache.put("a", "A")
+	cache.put("b", "B")
+	if _, ok := cache.get("a"); !ok {
+		t.Fatalf("expected a to be cached")
+	}
+	cache.put("c", "C")
+
+	if _, ok := cache.get("b"); ok {
+		t.Fatalf("expected b to be evicted as least recently used")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.get(key); !ok {
+			t.Fatalf("expected %s to stay cached", key)
+		}
+	}
+}
+
+func TestFormatCacheKeyChangesWithModelAndStatement(t *testing.T) {
+	base := formatCacheKey("two-sum", "openai/gpt-4o-mini", "Given nums")
+	if base != formatCacheKey("two-sum", "openai/gpt-4o-mini", "Given nums") {
+		t.Fatalf("key is not stable")
+	}
+	if base == formatCacheKey("two-sum", "openai/gpt-4o", "Given nums") {
+		t.Fatalf("key must change with the model")
+	}
+	if base == formatCacheKey("two-sum", "openai/gpt-4o-mini", "Given nums, return indices") {
+		t.Fatalf("key must change with the statement")
+	}
+}
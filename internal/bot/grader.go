@@ -5,7 +5,26 @@ import (
 	"strings"
 )
 
-func gradeAnswer(answer string, difficulty string) (int, string) {
+// topicKeywords lists answer words and phrases that show a topic's technique
+// beyond the tag name itself. Keys are lower-cased LeetCode topic names.
+// Entries match whole words only, so "set" does not match "subset".
+var topicKeywords = map[string][]string{
+	"hash table":            {"hash", "hashmap", "hashset", "hashing", "map", "dict", "dictionary", "set"},
+	"dynamic programming":   {"dp", "memo", "memoize", "memoization", "memoise", "memoisation", "tabulation"},
+	"two pointers":          {"two pointer", "two pointers", "pointers"},
+	"sliding window":        {"window", "windows"},
+	"breadth-first search":  {"bfs"},
+	"depth-first search":    {"dfs", "recursion", "recursive", "recurse"},
+	"heap (priority queue)": {"heap", "heaps", "priority queue"},
+	"union find":            {"union", "disjoint"},
+	"monotonic stack":       {"stack", "stacks"},
+	"prefix sum":            {"prefix", "prefixes"},
+}
+
+// gradeAnswer scores a text answer. topics are the question's LeetCode topic
+// names; naming a fitting technique earns a point, and nothing changes when
+// they are unknown.
+func gradeAnswer(answer string, difficulty string, topics []string) (int, string) {
 	text := strings.TrimSpace(answer)
 	lower := strings.ToLower(text)
 	wordCount := len(strings.Fields(text))
@@ -44,6 +63,15 @@ func gradeAnswer(answer string, difficulty string) (int, string) {
 		feedback = append(feedback, "Mention edge cases to improve robustness.")
 	}
 
+	if len(topics) > 0 {
+		if mentionsTopic(lower, topics) {
+			score++
+			feedback = append(feedback, "The technique fits how this problem is usually solved.")
+		} else {
+			feedback = append(feedback, "Name the core technique (e.g. hash map, two pointers, DP) you rely on.")
+		}
+	}
+
 	if difficulty == "Hard" && !hasAny(lower, "o(", "time complexity") {
 		score--
 		feedback = append(feedback, "For Hard questions, complexity analysis is essential.")
@@ -66,6 +94,43 @@ func gradeAnswer(answer string, difficulty string) (int, string) {
 	return score, fmt.Sprintf("Verdict: %s\n%s\nNote: This is heuristic grading from text/pseudocode, not code execution.", verdict, strings.Join(feedback, "\n"))
 }
 
+func mentionsTopic(lower string, topics []string) bool {
+	for _, topic := range topics {
+		name := strings.ToLower(strings.TrimSpace(topic))
+		if name != "" && containsWord(lower, name) {
+			return true
+		}
+		for _, keyword := range topicKeywords[name] {
+			if containsWord(lower, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsWord reports whether phrase occurs in s with no letter, digit or
+// underscore on either side.
+func containsWord(s, phrase string) bool {
+	for start := 0; start < len(s); {
+		i := strings.Index(s[start:], phrase)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(phrase)
+		if (i == 0 || !isWordByte(s[i-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return true
+		}
+		start = i + 1
+	}
+	return false
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9'
+}
+
 func hasAny(s string, patterns ...string) bool {
 	for _, p := range patterns {
 		if strings.Contains(s, p) {
@@ -0,0 +1,29 @@
+package bot
+
+import "testing"
+
+func TestMentionsTopicMatchesWholeWords(t *testing.T) {
+	tests := []struct {
+		answer string
+		topics []string
+		want   bool
+	}{
+		{answer: "store complements in a hash map", topics: []string{"Hash Table"}, want: true},
+		{answer: "keep a set of seen values", topics: []string{"Hash Table"}, want: true},
+		{answer: "try every subset and predict the sum", topics: []string{"Hash Table"}, want: false},
+		{answer: "recursive dfs over the grid", topics: []string{"Depth-First Search"}, want: true},
+		{answer: "a min-heap of the k largest", topics: []string{"Heap (Priority Queue)"}, want: true},
+		{answer: "use the windowsill trick", topics: []string{"Sliding Window"}, want: false},
+		{answer: "push onto a stack", topics: []string{"Monotonic Stack"}, want: true},
+		{answer: "sort the substrings", topics: []string{"String"}, want: false},
+		{answer: "walk the string once", topics: []string{"String"}, want: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.answer, func(t *testing.T) {
+			if got := mentionsTopic(tc.answer, tc.topics); got != tc.want {
+				t.Fatalf("mentionsTopic(%q, %v) = %v, want %v", tc.answer, tc.topics, got, tc.want)
+			}
+		})
+	}
+}
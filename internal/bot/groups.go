@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"telegram-leetcode-bot/internal/telegram"
+)
+
+// SetBotUsername records the bot's own @username so group messages can be
+// told apart: commands addressed to other bots are ignored and mentions of
+// this bot count as addressing it. Without it every /cmd@name is accepted.
+func (s *Service) SetBotUsername(username string) {
+	s.botUsername = normalizeTelegramUsername(username)
+	s.botMention = nil
+	if s.botUsername != "" {
+		s.botMention = regexp.MustCompile(`(?i)@` + regexp.QuoteMeta(s.botUsername) + `\b`)
+	}
+}
+
+func isGroupChat(chat telegram.Chat) bool {
+	return chat.Type == "group" || chat.Type == "supergroup"
+}
+
+// isGroupChatID reports whether chatID belongs to a group; Telegram gives
+// groups and supergroups negative IDs.
+func isGroupChatID(chatID int64) bool {
+	return chatID < 0
+}
+
+// stateKeyFor returns whose state a message acts on. Each group member keeps
+// their own question and history; private chats use the chat itself.
+func stateKeyFor(chat telegram.Chat, from telegram.User) StateKey {
+	if isGroupChat(chat) && from.ID != 0 {
+		return StateKey{ChatID: chat.ID, UserID: from.ID}
+	}
+	return ChatKey(chat.ID)
+}
+
+// groupMessageText reports whether a group message is meant for the bot and
+// returns its text with any @mention removed. Commands count unless they
+// name another bot; free text counts only as a reply to the bot or when it
+// mentions the bot, so ordinary group chatter is never graded.
+func (s *Service) groupMessageText(msg telegram.Message, text string) (string, bool) {
+	if strings.HasPrefix(text, "/") {
+		cmd := strings.Fields(text)[0]
+		if at := strings.Index(cmd, "@"); at >= 0 && s.botUsername != "" {
+			return text, normalizeTelegramUsername(cmd[at+1:]) == s.botUsername
+		}
+		return text, true
+	}
+
+	if s.botMention != nil {
+		if loc := s.botMention.FindStringIndex(text); loc != nil {
+			return strings.TrimSpace(text[:loc[0]] + text[loc[1]:]), true
+		}
+	}
+
+	reply := msg.ReplyToMessage
+	if reply == nil || !reply.From.IsBot {
+		return "", false
+	}
+	return text, s.botUsername == "" || normalizeTelegramUsername(reply.From.Username) == s.botUsername
+}
+
+// clearCurrentQuestion ends key's current question. A member working on the
+// inherited group question holds no copy to clear, so the question is
+// dismissed for them instead.
+func (s *Service) clearCurrentQuestion(ctx context.Context, key StateKey) error {
+	if key.UserID != 0 {
+		own, err := s.store.GetChatSettings(ctx, key)
+		if err != nil {
+			return err
+		}
+		if own.CurrentQuestion == nil {
+			group, err := s.store.GetChatSettings(ctx, ChatKey(key.ChatID))
+			if err != nil {
+				return err
+			}
+			if group.CurrentQuestion != nil {
+				return s.store.DismissQuestion(ctx, key, group.CurrentQuestion.Slug)
+			}
+		}
+	}
+	return s.store.ClearCurrentQuestion(ctx, key)
+}
+
+// chatSettings loads settings for key. Group members inherit the group's
+// timezone, its study plan when they have not picked their own and, until
+// they finish or /exit it, the group's current question, so the daily question posted
+// once to the group can be answered by everyone.
+func (s *Service) chatSettings(ctx context.Context, key StateKey) (ChatSettings, error) {
+	settings, err := s.store.GetChatSettings(ctx, key)
+	if err != nil || key.UserID == 0 {
+		return settings, err
+	}
+
+	group, err := s.store.GetChatSettings(ctx, ChatKey(key.ChatID))
+	if err != nil {
+		return ChatSettings{}, err
+	}
+	settings.Timezone = group.Timezone
+	if settings.StudyPlan == "" {
+		settings.StudyPlan = group.StudyPlan
+	}
+	if settings.CurrentQuestion != nil || group.CurrentQuestion == nil || group.CurrentQuestion.Slug == settings.DismissedSlug {
+		return settings, nil
+	}
+
+	_, err = s.store.GetAnsweredQuestion(ctx, key, group.CurrentQuestion.Slug)
+	switch {
+	case errors.Is(err, ErrAnsweredQuestionNotFound):
+		q := *group.CurrentQuestion
+		settings.CurrentQuestion = &q
+	case err != nil:
+		return ChatSettings{}, err
+	}
+	return settings, nil
+}
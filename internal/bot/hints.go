@@ -5,27 +5,73 @@ import (
 	"strings"
 )
 
-func (s *Service) sendHintForChat(ctx context.Context, chatID int64, learnerContext string) error {
-	settings, err := s.store.GetChatSettings(ctx, chatID)
+// maxAIHints is how many AI rungs follow LeetCode's own hints on the ladder.
+const maxAIHints = 2
+
+func (s *Service) sendHintForChat(ctx context.Context, key StateKey, learnerContext string) error {
+	settings, err := s.chatSettings(ctx, key)
 	if err != nil {
 		return err
 	}
 	if settings.CurrentQuestion == nil {
-		return s.tgClient.SendMessage(ctx, chatID, "No active question. Use /lc first.")
+		return s.tgClient.SendMessage(ctx, key.ChatID, "No active question. Use /lc first.")
 	}
 
-	return s.sendHint(ctx, chatID, *settings.CurrentQuestion, learnerContext)
+	return s.sendHint(ctx, key, settings, learnerContext)
 }
 
-func (s *Service) sendHint(ctx context.Context, chatID int64, q Question, learnerContext string) error {
-	hint, source := s.generateHint(ctx, q, learnerContext)
-	msg := formatHintMessage(q, source, hint)
-	return s.tgClient.SendRichMessage(ctx, chatID, msg)
+// sendHint climbs the hint ladder for the current question: LeetCode's own
+// hints one at a time, then up to maxAIHints escalating AI hints that see the
+// official ones. Requests past the top repeat the last rung. Questions
+// without official hints get open-ended AI hints and no "n/m" label.
+func (s *Service) sendHint(ctx context.Context, key StateKey, settings ChatSettings, learnerContext string) error {
+	q := *settings.CurrentQuestion
+	detail := s.questionDetail(ctx, q.Slug)
+	official := detail.Hints
+
+	shown := hintLevel(settings)
+	total, level := 0, shown+1
+	if len(official) > 0 {
+		total = len(official) + maxAIHints
+		level = min(level, total)
+	}
+
+	var hint, source string
+	if level <= len(official) {
+		hint, source = official[level-1], "LeetCode"
+	} else {
+		hint, source = s.generateHint(ctx, q, HintRequest{
+			LearnerContext: learnerContext,
+			PreviousHints:  official,
+			Level:          level,
+			Total:          total,
+			Statement:      statementText(q, detail.Content),
+			Transcript:     tutorTranscript(settings),
+		})
+	}
+
+	if level != shown || settings.HintSlug != q.Slug {
+		if err := s.store.SetHintLevel(ctx, key, q.Slug, level); err != nil {
+			s.logger.Printf("save hint level failed for chat %s slug=%s: %v", key, q.Slug, err)
+		}
+	}
+	s.recordTutorTurns(ctx, key, settings, TutorTurn{Kind: TutorTurnHint, Text: hint})
+	msg := formatHintMessage(q, source, hint, level, total)
+	return s.tgClient.SendRichMessage(ctx, key.ChatID, msg)
+}
+
+// hintLevel returns how many hints were shown for the current question,
+// ignoring a level saved for another one.
+func hintLevel(settings ChatSettings) int {
+	if settings.CurrentQuestion == nil || settings.HintSlug != settings.CurrentQuestion.Slug {
+		return 0
+	}
+	return settings.HintLevel
 }
 
-func (s *Service) generateHint(ctx context.Context, q Question, learnerContext string) (string, string) {
+func (s *Service) generateHint(ctx context.Context, q Question, req HintRequest) (string, string) {
 	if s.coach != nil {
-		hint, err := s.coach.GenerateHint(ctx, q, learnerContext)
+		hint, err := s.coach.GenerateHint(ctx, q, req)
 		if err == nil {
 			hint = strings.TrimSpace(hint)
 			if hint != "" {
@@ -36,7 +82,7 @@ func (s *Service) generateHint(ctx context.Context, q Question, learnerContext s
 		}
 	}
 
-	return fallbackHint(q, learnerContext), "Heuristic"
+	return fallbackHint(q, req.LearnerContext), "Heuristic"
 }
 
 func fallbackHint(q Question, learnerContext string) string {
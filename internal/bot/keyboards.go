@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"telegram-leetcode-bot/internal/bot/commands"
+	"telegram-leetcode-bot/internal/telegram"
+)
+
+func questionKeyboard() telegram.InlineKeyboardMarkup {
+	return telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{
+		{hintButton(), skipButton()},
+		{doneButton(), exitButton()},
+	}}
+}
+
+// evaluationKeyboard follows a graded answer. Hint, Skip and Done only apply
+// while the question is still active; once a passing answer clears it the
+// learner moves on instead, and next is false when a running session serves
+// its own next question.
+func evaluationKeyboard(active, next bool) telegram.InlineKeyboardMarkup {
+	if active {
+		return telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{
+			{hintButton(), skipButton(), doneButton()},
+			{reviseButton(), exitButton()},
+		}}
+	}
+	row := []telegram.InlineKeyboardButton{reviseButton()}
+	if next {
+		row = append([]telegram.InlineKeyboardButton{nextButton()}, row...)
+	}
+	return telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{row}}
+}
+
+func hintButton() telegram.InlineKeyboardButton {
+	return telegram.InlineKeyboardButton{Text: "💡 Hint", CallbackData: commands.CallbackHint}
+}
+
+func skipButton() telegram.InlineKeyboardButton {
+	return telegram.InlineKeyboardButton{Text: "⏭ Skip", CallbackData: commands.CallbackSkip}
+}
+
+func doneButton() telegram.InlineKeyboardButton {
+	return telegram.InlineKeyboardButton{Text: "✅ Done", CallbackData: commands.CallbackDone}
+}
+
+func exitButton() telegram.InlineKeyboardButton {
+	return telegram.InlineKeyboardButton{Text: "🚪 Exit", CallbackData: commands.CallbackExit}
+}
+
+func nextButton() telegram.InlineKeyboardButton {
+	return telegram.InlineKeyboardButton{Text: "➡️ Next", CallbackData: commands.CallbackNext}
+}
+
+func reviseButton() telegram.InlineKeyboardButton {
+	return telegram.InlineKeyboardButton{Text: "🔁 Revise", CallbackData: commands.CallbackRevise}
+}
@@ -0,0 +1,257 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"telegram-leetcode-bot/internal/telegram"
+)
+
+const (
+	LeaderboardWeek  = "week"
+	LeaderboardMonth = "month"
+	LeaderboardAll   = "all"
+)
+
+var difficultyWeights = map[string]int{"Easy": 1, "Medium": 2, "Hard": 3}
+
+type leaderboardEntry struct {
+	Name     string
+	Solved   int
+	Weighted int
+	AvgScore float64
+	Points   float64
+}
+
+// rememberMember records a group member's display name so solves can be
+// attributed on the leaderboard.
+func (s *Service) rememberMember(key StateKey, name string) {
+	if key.UserID == 0 || name == "" {
+		return
+	}
+	s.memberNamesMu.Lock()
+	defer s.memberNamesMu.Unlock()
+	s.memberNames[key] = name
+}
+
+func displayName(user telegram.User) string {
+	if name := strings.TrimSpace(user.FirstName); name != "" {
+		return name
+	}
+	if user.Username != "" {
+		return "@" + user.Username
+	}
+	return ""
+}
+
+func (s *Service) memberName(key StateKey) string {
+	s.memberNamesMu.RLock()
+	defer s.memberNamesMu.RUnlock()
+	if name, ok := s.memberNames[key]; ok {
+		return name
+	}
+	return "Member " + strconv.FormatInt(key.UserID, 10)
+}
+
+// recordGroupSolve logs a completed question for the group leaderboard.
+// Private chats have no leaderboard and are skipped.
+func (s *Service) recordGroupSolve(ctx context.Context, key StateKey, q Question, score int) {
+	if key.UserID == 0 {
+		return
+	}
+	solve := GroupSolve{
+		UserID:     key.UserID,
+		Name:       s.memberName(key),
+		Slug:       q.Slug,
+		Difficulty: normalizeDifficultyLabel(q.Difficulty),
+		Score:      score,
+		SolvedAt:   s.nowFn().UTC(),
+	}
+	if err := s.store.RecordGroupSolve(ctx, key.ChatID, solve); err != nil {
+		s.logger.Printf("record group solve failed for chat %s slug=%s: %v", key, q.Slug, err)
+	}
+}
+
+func (s *Service) sendLeaderboard(ctx context.Context, key StateKey, period string) error {
+	if key.UserID == 0 {
+		return s.tgClient.SendMessage(ctx, key.ChatID, "Leaderboards rank members of a group chat. Add me to a group to compete.")
+	}
+
+	group, err := s.store.GetChatSettings(ctx, ChatKey(key.ChatID))
+	if err != nil {
+		return err
+	}
+	now := s.nowFn().In(s.resolveLocation(group.Timezone))
+	solves, err := s.store.ListGroupSolves(ctx, key.ChatID, leaderboardStart(period, now))
+	if err != nil {
+		return err
+	}
+
+	msg := formatLeaderboardMessage(leaderboardTitle(period), computeLeaderboard(solves))
+	return s.tgClient.SendRichMessage(ctx, key.ChatID, msg)
+}
+
+// sendWeeklySummaries posts last week's leaderboard to every group with
+// recent solves, once its local time reaches the group's daily time in a new
+// week. It runs whether or not the group gets daily questions.
+func (s *Service) sendWeeklySummaries(ctx context.Context, nowUTC time.Time) {
+	// A day of slack keeps groups ahead of UTC whose week already started.
+	since := leaderboardStart(LeaderboardWeek, nowUTC).AddDate(0, 0, -8)
+	groups, err := s.store.ListActiveGroups(ctx, since)
+	if err != nil {
+		s.logger.Printf("list active groups failed: %v", err)
+		return
+	}
+	for _, chat := range groups {
+		now := nowUTC.In(s.resolveLocation(chat.Timezone))
+		hhmm := chat.DailyTime
+		if hhmm == "" {
+			hhmm = s.defaultDailyHH
+		}
+		if now.Format("15:04") < hhmm {
+			continue
+		}
+		if err := s.sendWeeklySummary(ctx, chat, now); err != nil {
+			s.logger.Printf("weekly summary failed for chat %d: %v", chat.ChatID, err)
+		}
+	}
+}
+
+// sendWeeklySummary posts last week's leaderboard to a group once per ISO
+// week. Weeks without solves are marked but stay silent.
+func (s *Service) sendWeeklySummary(ctx context.Context, chat ChatSettings, now time.Time) error {
+	thisWeek := leaderboardStart(LeaderboardWeek, now)
+	lastWeek := thisWeek.AddDate(0, 0, -7)
+	year, week := lastWeek.ISOWeek()
+	label := fmt.Sprintf("%d-W%02d", year, week)
+	if chat.LastWeeklySummaryOn == label {
+		return nil
+	}
+
+	solves, err := s.store.ListGroupSolves(ctx, chat.ChatID, lastWeek)
+	if err != nil {
+		return err
+	}
+	inWeek := solves[:0]
+	for _, solve := range solves {
+		if solve.SolvedAt.Before(thisWeek) {
+			inWeek = append(inWeek, solve)
+		}
+	}
+
+	if entries := computeLeaderboard(inWeek); len(entries) > 0 {
+		msg := formatLeaderboardMessage("Last week's results", entries)
+		if err := s.tgClient.SendRichMessage(ctx, chat.ChatID, msg); err != nil {
+			return err
+		}
+	}
+	return s.store.MarkWeeklySummarySent(ctx, ChatKey(chat.ChatID), label)
+}
+
+// leaderboardStart returns the beginning of the period containing now: the
+// Monday of its ISO week, the first of its month, or the zero time.
+func leaderboardStart(period string, now time.Time) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch period {
+	case LeaderboardMonth:
+		return midnight.AddDate(0, 0, 1-now.Day())
+	case LeaderboardAll:
+		return time.Time{}
+	default:
+		offset := (int(now.Weekday()) + 6) % 7
+		return midnight.AddDate(0, 0, -offset)
+	}
+}
+
+func leaderboardTitle(period string) string {
+	switch period {
+	case LeaderboardMonth:
+		return "This month"
+	case LeaderboardAll:
+		return "All time"
+	default:
+		return "This week"
+	}
+}
+
+// computeLeaderboard ranks members by difficulty-weighted solves (Easy 1,
+// Medium 2, Hard 3) scaled by their average score out of 10. Each slug counts
+// once per member at its best score; ungraded /done solves count at the pass
+// mark.
+func computeLeaderboard(solves []GroupSolve) []leaderboardEntry {
+	type member struct {
+		name string
+		best map[string]GroupSolve
+	}
+	members := make(map[int64]*member)
+	for _, solve := range solves {
+		m, ok := members[solve.UserID]
+		if !ok {
+			m = &member{best: make(map[string]GroupSolve)}
+			members[solve.UserID] = m
+		}
+		if solve.Name != "" {
+			m.name = solve.Name
+		}
+		if solve.Score == 0 {
+			solve.Score = correctAnswerScoreThreshold
+		}
+		if prev, seen := m.best[solve.Slug]; !seen || solve.Score > prev.Score {
+			m.best[solve.Slug] = solve
+		}
+	}
+
+	out := make([]leaderboardEntry, 0, len(members))
+	for userID, m := range members {
+		entry := leaderboardEntry{Name: m.name, Solved: len(m.best)}
+		if entry.Name == "" {
+			entry.Name = "Member " + strconv.FormatInt(userID, 10)
+		}
+		total := 0
+		for _, solve := range m.best {
+			entry.Weighted += max(difficultyWeights[solve.Difficulty], 1)
+			total += clampScore(solve.Score)
+		}
+		entry.AvgScore = float64(total) / float64(entry.Solved)
+		entry.Points = float64(entry.Weighted) * entry.AvgScore / 10
+		out = append(out, entry)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Points != out[j].Points {
+			return out[i].Points > out[j].Points
+		}
+		if out[i].Solved != out[j].Solved {
+			return out[i].Solved > out[j].Solved
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func formatLeaderboardMessage(title string, entries []leaderboardEntry) string {
+	lines := []string{
+		"*🏆 Leaderboard: " + escapeMarkdownV2(title) + "*",
+		"",
+	}
+	if len(entries) == 0 {
+		lines = append(lines, escapeMarkdownV2("No solves yet. Answer a question with /lc to get on the board."))
+		return strings.Join(lines, "\n")
+	}
+
+	medals := []string{"🥇", "🥈", "🥉"}
+	for i, entry := range entries {
+		rank := fmt.Sprintf("%d.", i+1)
+		if i < len(medals) {
+			rank = medals[i]
+		}
+		line := fmt.Sprintf("%s %s: %.1f pts (%d solved, avg %.1f/10)", rank, entry.Name, entry.Points, entry.Solved, entry.AvgScore)
+		lines = append(lines, escapeMarkdownV2(line))
+	}
+	lines = append(lines, "", escapeMarkdownV2("Points = difficulty-weighted solves × average score / 10."))
+	return strings.Join(lines, "\n")
+}
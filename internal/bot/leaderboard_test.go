@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeLeaderboardWeightsDifficultyAndScore(t *testing.T) {
+	solves := []GroupSolve{
+		{UserID: 1, Name: "Alice", Slug: "two-sum", Difficulty: "Easy", Score: 6},
+		{UserID: 1, Name: "Alice", Slug: "two-sum", Difficulty: "Easy", Score: 10},
+		{UserID: 1, Name: "Alice", Slug: "merge-intervals", Difficulty: "Medium", Score: 8},
+		{UserID: 2, Name: "Bob", Slug: "trapping-rain-water", Difficulty: "Hard", Score: 10},
+		{UserID: 3, Slug: "valid-parentheses", Difficulty: "Easy"},
+	}
+
+	entries := computeLeaderboard(solves)
+
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 members, got %+v", entries)
+	}
+	// Alice: weight 1+2 at avg 9 = 2.7; Bob: weight 3 at avg 10 = 3.0.
+	if entries[0].Name != "Bob" || entries[0].Points != 3.0 {
+		t.Fatalf("first place = %+v, want Bob with 3.0", entries[0])
+	}
+	if entries[1].Name != "Alice" || entries[1].Solved != 2 || entries[1].Weighted != 3 || entries[1].AvgScore != 9 {
+		t.Fatalf("second place = %+v, want Alice with best scores per slug", entries[1])
+	}
+	if entries[2].Name != "Member 3" || entries[2].AvgScore != float64(correctAnswerScoreThreshold) {
+		t.Fatalf("ungraded solve = %+v, want the pass mark under a fallback name", entries[2])
+	}
+}
+
+func TestLeaderboardStart(t *testing.T) {
+	loc := time.FixedZone("SGT", 8*3600)
+	now := time.Date(2026, 3, 12, 21, 30, 0, 0, loc) // Thursday
+
+	if got, want := leaderboardStart(LeaderboardWeek, now), time.Date(2026, 3, 9, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("week start = %v, want %v", got, want)
+	}
+	if got, want := leaderboardStart(LeaderboardMonth, now), time.Date(2026, 3, 1, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("month start = %v, want %v", got, want)
+	}
+	if got := leaderboardStart(LeaderboardAll, now); !got.IsZero() {
+		t.Fatalf("all-time start = %v, want zero", got)
+	}
+	sunday := time.Date(2026, 3, 15, 23, 0, 0, 0, loc)
+	if got, want := leaderboardStart(LeaderboardWeek, sunday), time.Date(2026, 3, 9, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("Sunday belongs to the week starting %v, got %v", want, got)
+	}
+}
+
+func TestFormatLeaderboardMessageEscapesMarkdown(t *testing.T) {
+	msg := formatLeaderboardMessage("This week", []leaderboardEntry{{Name: "a_b", Solved: 1, Weighted: 2, AvgScore: 9, Points: 1.8}})
+	if !strings.Contains(msg, "🥇 a\\_b: 1\\.8 pts") {
+		t.Fatalf("expected escaped entry, got: %s", msg)
+	}
+}
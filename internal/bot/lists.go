@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// questionListPlanPrefix marks a StudyPlan setting that names one of the
+// chat's custom question lists instead of a bundled plan.
+const questionListPlanPrefix = "list:"
+
+// questionListPlan loads the chat's named list as a study plan.
+func (s *Service) questionListPlan(ctx context.Context, chatID int64, name string) (StudyPlan, bool, error) {
+	list, err := s.store.GetQuestionList(ctx, chatID, name)
+	if errors.Is(err, ErrQuestionListNotFound) {
+		s.logger.Printf("chat %d uses missing question list %q", chatID, name)
+		return StudyPlan{}, false, nil
+	}
+	if err != nil {
+		return StudyPlan{}, false, err
+	}
+	return StudyPlan{
+		Name:      questionListPlanPrefix + list.Name,
+		Title:     list.Name,
+		Questions: list.Questions,
+	}, true, nil
+}
+
+// lookupQuestions matches slugs against the provider's question set, keeping
+// input order. Slugs the provider does not know are returned in unknown.
+func (s *Service) lookupQuestions(ctx context.Context, slugs []string) (found []Question, unknown []string, err error) {
+	all, err := s.questions.AllQuestions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	bySlug := make(map[string]Question, len(all))
+	for _, q := range all {
+		bySlug[strings.ToLower(q.Slug)] = q
+	}
+	for _, slug := range slugs {
+		q, ok := bySlug[strings.ToLower(slug)]
+		if !ok {
+			unknown = append(unknown, slug)
+			continue
+		}
+		found = append(found, q)
+	}
+	return found, unknown, nil
+}
@@ -2,6 +2,7 @@ package bot
 
 import (
 	"fmt"
+	"html"
 	"regexp"
 	"strings"
 	"unicode/utf8"
@@ -12,7 +13,9 @@ const maxFeedbackRunes = 1400
 const maxGuidanceRunes = 1600
 const maxHintRunes = 3400
 
-func formatQuestionMessage(intro, note string, q Question, prompt string) string {
+// formatQuestionMessage renders a question. detail adds the acceptance,
+// topic and similar-question lines when the lookup succeeded.
+func formatQuestionMessage(intro, note string, q Question, prompt string, detail QuestionDetail) string {
 	_ = strings.TrimSpace(intro)
 	note = strings.TrimSpace(note)
 	prompt = strings.TrimSpace(prompt)
@@ -35,13 +38,21 @@ func formatQuestionMessage(intro, note string, q Question, prompt string) string
 		lines = append(lines, "_"+escapeMarkdownV2(note)+"_")
 	}
 
+	lines = append(lines, titleLine)
+	if meta := formatQuestionMeta(detail); meta != "" {
+		lines = append(lines, escapeMarkdownV2(meta))
+	}
 	lines = append(lines,
-		titleLine,
 		"",
 		"__*Problem*__",
 		"",
 		renderStructuredTextForTelegram(prompt),
 		"",
+	)
+	if similar := formatSimilarQuestions(detail.SimilarQuestions); similar != "" {
+		lines = append(lines, escapeMarkdownV2(similar), "")
+	}
+	lines = append(lines,
 		"__*Next*__",
 		escapeMarkdownV2("Reply with your approach. Use /hint for guidance, /skip for another question, or /exit."),
 	)
@@ -49,6 +60,47 @@ func formatQuestionMessage(intro, note string, q Question, prompt string) string
 	return strings.Join(lines, "\n")
 }
 
+const maxSimilarQuestions = 3
+
+// formatQuestionMeta renders e.g. "Acceptance 53.9% • 👍 56.2k • Array, Hash
+// Table", skipping whatever LeetCode did not report.
+func formatQuestionMeta(detail QuestionDetail) string {
+	parts := make([]string, 0, 3)
+	if detail.AcceptanceRate > 0 {
+		parts = append(parts, fmt.Sprintf("Acceptance %.1f%%", detail.AcceptanceRate))
+	}
+	if detail.Likes > 0 {
+		parts = append(parts, "👍 "+formatCount(detail.Likes))
+	}
+	if len(detail.TopicTags) > 0 {
+		parts = append(parts, strings.Join(detail.TopicTags, ", "))
+	}
+	return strings.Join(parts, " • ")
+}
+
+func formatSimilarQuestions(similar []Question) string {
+	if len(similar) == 0 {
+		return ""
+	}
+	names := make([]string, 0, maxSimilarQuestions)
+	for _, q := range similar[:min(len(similar), maxSimilarQuestions)] {
+		names = append(names, fmt.Sprintf("%s (%s)", q.Title, q.Difficulty))
+	}
+	return "Similar: " + strings.Join(names, ", ")
+}
+
+// formatCount abbreviates large counts: 950, 12.3k, 1.2M.
+func formatCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
 func stripDuplicatedQuestionHeader(prompt string, q Question) string {
 	lines := strings.Split(strings.TrimSpace(prompt), "\n")
 	for len(lines) > 0 {
@@ -95,7 +147,10 @@ func isQuestionTitleLine(line string, q Question) bool {
 	return false
 }
 
-func formatEvaluationMessage(q Question, score int, source, feedback, guidance, status string) string {
+// formatEvaluationMessage renders a graded answer. elapsed is the mock
+// interview clock and is omitted when empty; run adds the example results
+// when the answer's code was executed.
+func formatEvaluationMessage(q Question, score int, source, feedback, guidance, status, elapsed string, run *codeRun) string {
 	feedback = truncateRunes(strings.TrimSpace(feedback), maxFeedbackRunes)
 	guidance = truncateRunes(strings.TrimSpace(guidance), maxGuidanceRunes)
 	status = strings.TrimSpace(status)
@@ -104,11 +159,22 @@ func formatEvaluationMessage(q Question, score int, source, feedback, guidance,
 		"*🧠 Evaluation*",
 		fmt.Sprintf("*%s* \\(%s\\)", escapeMarkdownV2(q.Title), escapeMarkdownV2(q.Difficulty)),
 		fmt.Sprintf("Score: *%d/10* • Source: %s", score, escapeMarkdownV2(source)),
+	}
+	if elapsed = strings.TrimSpace(elapsed); elapsed != "" {
+		lines = append(lines, escapeMarkdownV2("⏱ Elapsed: "+elapsed))
+	}
+	lines = append(lines,
 		"",
 		"__*Feedback*__",
 		"",
 		renderStructuredTextForTelegram(feedback),
 		"",
+	)
+	if run != nil {
+		lines = append(lines, formatCodeRunLines(*run)...)
+		lines = append(lines, "")
+	}
+	lines = append(lines,
 		"__*Next Steps*__",
 		"",
 		renderStructuredTextForTelegram(guidance),
@@ -118,19 +184,25 @@ func formatEvaluationMessage(q Question, score int, source, feedback, guidance,
 		escapeMarkdownV2(status),
 		"",
 		escapeMarkdownV2("Send another attempt, /hint, /skip, /done, /exit, or /lc."),
-	}
+	)
 
 	return strings.Join(lines, "\n")
 }
 
-func formatHintMessage(q Question, source, hint string) string {
+// formatHintMessage renders hint number level of total; a zero total means
+// the ladder is open-ended and no position is shown.
+func formatHintMessage(q Question, source, hint string, level, total int) string {
 	hint = strings.TrimSpace(hint)
 	if !strings.Contains(hint, "```") {
 		hint = truncateRunes(hint, maxHintRunes)
 	}
 
+	title := "*💡 Hint*"
+	if total > 0 {
+		title = fmt.Sprintf("*💡 Hint %d/%d*", level, total)
+	}
 	lines := []string{
-		"*💡 Hint*",
+		title,
 		fmt.Sprintf("*%s* \\(%s\\)", escapeMarkdownV2(q.Title), escapeMarkdownV2(q.Difficulty)),
 		fmt.Sprintf("Source: %s", escapeMarkdownV2(source)),
 		"",
@@ -142,6 +214,24 @@ func formatHintMessage(q Question, source, hint string) string {
 	return strings.Join(lines, "\n")
 }
 
+// formatTutorReplyMessage renders the coach's answer to a follow-up question.
+func formatTutorReplyMessage(q Question, reply string) string {
+	reply = strings.TrimSpace(reply)
+	if !strings.Contains(reply, "```") {
+		reply = truncateRunes(reply, maxHintRunes)
+	}
+
+	lines := []string{
+		"*💬 Coach*",
+		fmt.Sprintf("*%s* \\(%s\\)", escapeMarkdownV2(q.Title), escapeMarkdownV2(q.Difficulty)),
+		"",
+		renderStructuredTextForTelegram(reply),
+		"",
+		escapeMarkdownV2("Ask another question, or send your approach for evaluation."),
+	}
+	return strings.Join(lines, "\n")
+}
+
 var numberedListPattern = regexp.MustCompile(`^(\d+)[\.)]\s+(.*)$`)
 var fencedCodeLangPattern = regexp.MustCompile(`[^a-zA-Z0-9_+\-]`)
 
@@ -225,6 +315,23 @@ func formatHeadingLine(line string) (string, bool) {
 	return "", false
 }
 
+var (
+	htmlBreakPattern = regexp.MustCompile(`(?i)<\s*br\s*/?\s*>|</\s*(p|div|pre|li|ul|ol|h[1-6])\s*>`)
+	htmlTagPattern   = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9]*(\s[^<>]*)?/?>`)
+	blankRunPattern  = regexp.MustCompile(`\n{3,}`)
+)
+
+// statementText returns a statement as the plain text the question message
+// shows: markup, such as from a hand-written catalog, is stripped and the
+// title and link lines are dropped.
+func statementText(q Question, content string) string {
+	text := htmlBreakPattern.ReplaceAllString(content, "\n")
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, ""))
+	text = blankRunPattern.ReplaceAllString(text, "\n\n")
+	text = stripQuestionLinkLines(text, q.URL)
+	return stripDuplicatedQuestionHeader(text, q)
+}
+
 func stripQuestionLinkLines(prompt, questionURL string) string {
 	lines := strings.Split(strings.TrimSpace(prompt), "\n")
 	if len(lines) == 0 {
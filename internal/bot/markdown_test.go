@@ -3,6 +3,7 @@ package bot
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFormatQuestionMessageIncludesSectionsAndEscapes(t *testing.T) {
@@ -11,6 +12,7 @@ func TestFormatQuestionMessageIncludesSectionsAndEscapes(t *testing.T) {
 		"",
 		Question{Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
 		"Given nums[i] and target (int), find answer_1.",
+		QuestionDetail{},
 	)
 
 	for _, marker := range []string{"*[Two Sum](https://leetcode.com/problems/two-sum/)* \\(Easy\\)", "__*Problem*__", "__*Next*__"} {
@@ -29,6 +31,36 @@ func TestFormatQuestionMessageIncludesSectionsAndEscapes(t *testing.T) {
 	}
 }
 
+func TestFormatQuestionMessageShowsDetailMetadata(t *testing.T) {
+	detail := QuestionDetail{
+		AcceptanceRate: 53.94,
+		Likes:          56210,
+		TopicTags:      []string{"Array", "Hash Table"},
+		SimilarQuestions: []Question{
+			{Slug: "3sum", Title: "3Sum", Difficulty: "Medium"},
+			{Slug: "4sum", Title: "4Sum", Difficulty: "Medium"},
+			{Slug: "two-sum-ii", Title: "Two Sum II", Difficulty: "Medium"},
+			{Slug: "two-sum-iii", Title: "Two Sum III", Difficulty: "Easy"},
+		},
+	}
+	msg := formatQuestionMessage("", "", Question{Title: "Two Sum", Difficulty: "Easy"}, "Find two numbers.", detail)
+
+	for _, marker := range []string{
+		"Acceptance 53\\.9% • 👍 56\\.2k • Array, Hash Table",
+		"Similar: 3Sum \\(Medium\\), 4Sum \\(Medium\\), Two Sum II \\(Medium\\)",
+	} {
+		if !strings.Contains(msg, marker) {
+			t.Fatalf("expected question message to include %q: %s", marker, msg)
+		}
+	}
+	if strings.Contains(msg, "Two Sum III") {
+		t.Fatalf("expected similar questions to be capped: %s", msg)
+	}
+	if plain := formatQuestionMessage("", "", Question{Title: "Two Sum", Difficulty: "Easy"}, "Find two numbers.", QuestionDetail{}); strings.Contains(plain, "Acceptance") || strings.Contains(plain, "Similar") {
+		t.Fatalf("expected no metadata lines without a detail: %s", plain)
+	}
+}
+
 func TestFormatEvaluationMessageIncludesStatusSection(t *testing.T) {
 	msg := formatEvaluationMessage(
 		Question{Title: "Two Sum", Difficulty: "Easy"},
@@ -37,6 +69,8 @@ func TestFormatEvaluationMessageIncludesStatusSection(t *testing.T) {
 		"Good approach.",
 		"State complexity.",
 		"Correct. Saved to history.",
+		"",
+		nil,
 	)
 
 	for _, marker := range []string{"*🧠 Evaluation*", "__*Feedback*__", "__*Next Steps*__", "__*Status*__", "Correct\\. Saved"} {
@@ -44,6 +78,73 @@ func TestFormatEvaluationMessageIncludesStatusSection(t *testing.T) {
 			t.Fatalf("expected evaluation message to include %q: %s", marker, msg)
 		}
 	}
+	if strings.Contains(msg, "Elapsed") {
+		t.Fatalf("expected no clock outside a mock interview: %s", msg)
+	}
+}
+
+func TestFormatEvaluationMessageShowsMockClock(t *testing.T) {
+	msg := formatEvaluationMessage(Question{Title: "Two Sum", Difficulty: "Easy"}, 6, "AI", "Close.", "Handle duplicates.", "Not saved yet.", formatMockClock(12*time.Minute+5*time.Second, 45), nil)
+	if !strings.Contains(msg, "⏱ Elapsed: 12m 05s of 45m") {
+		t.Fatalf("expected elapsed time in the evaluation: %s", msg)
+	}
+}
+
+func TestFormatEvaluationMessageListsExampleResults(t *testing.T) {
+	run := &codeRun{
+		Language: CodeLanguagePython,
+		Results: []ExampleResult{
+			{Input: "nums = [2,7,11,15], target = 9", Expected: "[0,1]", Got: "[0,1]", Passed: true},
+			{Input: "nums = [3,2,4], target = 6", Expected: "[1,2]", Got: "[2,1]"},
+			{Input: "nums = [3,3], target = 6", Expected: "[0,1]", Err: "IndexError: list index out of range"},
+		},
+	}
+	msg := formatEvaluationMessage(Question{Title: "Two Sum", Difficulty: "Easy"}, 7, "AI", "Close.", "Check the order.", "Not saved yet.", "", run)
+
+	for _, marker := range []string{
+		"__*Examples*__",
+		"✅ Example 1 passed",
+		"❌ Example 2: nums \\= \\[3,2,4\\], target \\= 6 → expected \\[1,2\\], got \\[2,1\\]",
+		"⚠️ Example 3: IndexError",
+		"_Python: 1/3 examples passed_",
+	} {
+		if !strings.Contains(msg, marker) {
+			t.Fatalf("expected evaluation message to include %q: %s", marker, msg)
+		}
+	}
+}
+
+func TestFormatEvaluationMessageExplainsSkippedCodeRun(t *testing.T) {
+	run := &codeRun{Language: CodeLanguageGo, Err: "Running code isn't supported for this question yet."}
+	msg := formatEvaluationMessage(Question{Title: "LRU Cache", Difficulty: "Medium"}, 7, "AI", "Close.", "Check eviction.", "Not saved yet.", "", run)
+	if !strings.Contains(msg, "⚠️ Running code isn't supported for this question yet\\.") {
+		t.Fatalf("expected the skipped run to be explained: %s", msg)
+	}
+}
+
+func TestExtractFencedCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		answer   string
+		language string
+		code     string
+		ok       bool
+	}{
+		{name: "python tag", answer: "Use a map.\n```python\nclass Solution:\n    pass\n```", language: CodeLanguagePython, code: "class Solution:\n    pass", ok: true},
+		{name: "golang tag", answer: "```golang\nfunc twoSum() {}\n```", language: CodeLanguageGo, code: "func twoSum() {}", ok: true},
+		{name: "untagged python", answer: "```\ndef twoSum(nums, target):\n    return []\n```", language: CodeLanguagePython, code: "def twoSum(nums, target):\n    return []", ok: true},
+		{name: "untagged go", answer: "```\nfunc twoSum(nums []int) []int { return nil }\n```", language: CodeLanguageGo, code: "func twoSum(nums []int) []int { return nil }", ok: true},
+		{name: "other language", answer: "```java\nclass Solution {}\n```", ok: false},
+		{name: "prose only", answer: "I would use a hash map in O(n).", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, code, ok := extractFencedCode(tt.answer)
+			if ok != tt.ok || language != tt.language || code != tt.code {
+				t.Fatalf("extractFencedCode() = (%q, %q, %v), want (%q, %q, %v)", language, code, ok, tt.language, tt.code, tt.ok)
+			}
+		})
+	}
 }
 
 func TestRenderStructuredTextForTelegramHeadingsListsAndCodeBlocks(t *testing.T) {
@@ -71,6 +172,7 @@ func TestFormatQuestionMessageStripsDuplicatedQuestionHeader(t *testing.T) {
 		"",
 		Question{Title: "Form Array by Concatenating Subarrays of Another Array", Difficulty: "Medium", URL: "https://leetcode.com/problems/form-array-by-concatenating-subarrays-of-another-array/"},
 		"Form Array by Concatenating Subarrays of Another Array (Medium)\n\nProblem\n\nForm Array by Concatenating Subarrays of Another Array (Medium)\n\nProblem\nYou are given groups and nums.",
+		QuestionDetail{},
 	)
 
 	if strings.Count(msg, "Form Array by Concatenating Subarrays of Another Array") != 1 {
@@ -83,3 +185,14 @@ func TestFormatQuestionMessageStripsDuplicatedQuestionHeader(t *testing.T) {
 		t.Fatalf("expected actual statement body to remain: %s", msg)
 	}
 }
+
+func TestStatementTextStripsMarkup(t *testing.T) {
+	q := Question{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"}
+	content := "<p>Given an array <code>nums</code> &amp; a target.</p><ul><li>1 &lt;= nums.length</li></ul>\n2 <= n <= 10^4 and nums[i] > 0"
+
+	got := statementText(q, content)
+	want := "Given an array nums & a target.\n1 <= nums.length\n\n2 <= n <= 10^4 and nums[i] > 0"
+	if got != want {
+		t.Fatalf("statementText = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,163 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// startMock serves a fresh question and starts the interview clock on it.
+func (s *Service) startMock(ctx context.Context, key StateKey, minutes int, difficulty string) error {
+	if err := s.store.ClearCurrentQuestion(ctx, key); err != nil {
+		return err
+	}
+	if err := s.sendFilteredQuestion(ctx, key, "Here is your mock interview question:", questionFilter{Difficulty: difficulty}); err != nil {
+		return err
+	}
+
+	settings, err := s.store.GetChatSettings(ctx, key)
+	if err != nil {
+		return err
+	}
+	if settings.CurrentQuestion == nil {
+		// No question matched; the learner has already been told why.
+		return nil
+	}
+	err = s.store.SetMockSession(ctx, key, MockSession{
+		Slug:      settings.CurrentQuestion.Slug,
+		StartedAt: s.nowFn().UTC(),
+		Minutes:   minutes,
+	})
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("⏱ Mock interview started: %d minutes on the clock. Explain your approach and complexity as you would to an interviewer. I'll warn you at halfway and with one minute left.", minutes)
+	return s.tgClient.SendMessage(ctx, key.ChatID, msg)
+}
+
+// activeMock returns the running mock when it still covers the current
+// question. /skip, /exit, /lc and the daily question all move on without
+// touching the session, so a stale one is simply ignored.
+func activeMock(settings ChatSettings) *MockSession {
+	if settings.Mock == nil || settings.CurrentQuestion == nil || settings.Mock.Slug != settings.CurrentQuestion.Slug {
+		return nil
+	}
+	return settings.Mock
+}
+
+// tickMockSessions runs on every cron tick: it warns at halfway and with one
+// minute left, and closes sessions whose time is up.
+func (s *Service) tickMockSessions(ctx context.Context, now time.Time) {
+	sessions, err := s.store.ListMockSessions(ctx)
+	if err != nil {
+		s.logger.Printf("list mock sessions failed: %v", err)
+		return
+	}
+	for _, settings := range sessions {
+		if err := s.tickMock(ctx, settings, now); err != nil {
+			s.logger.Printf("mock tick failed for chat %s: %v", settings.Key(), err)
+		}
+	}
+}
+
+func (s *Service) tickMock(ctx context.Context, settings ChatSettings, now time.Time) error {
+	key := settings.Key()
+	mock := activeMock(settings)
+	if mock == nil {
+		return s.store.ClearMockSession(ctx, key)
+	}
+
+	limit := time.Duration(mock.Minutes) * time.Minute
+	elapsed := now.Sub(mock.StartedAt)
+	var msg string
+	switch {
+	case elapsed >= limit:
+		return s.finishMock(ctx, key, *settings.CurrentQuestion, *mock, elapsed, tutorTranscript(settings))
+	case elapsed >= limit-time.Minute && !mock.WarnedFinal:
+		mock.WarnedHalfway, mock.WarnedFinal = true, true
+		msg = "⏰ One minute left in your mock interview. Send your final answer now."
+	case elapsed >= limit/2 && !mock.WarnedHalfway:
+		mock.WarnedHalfway = true
+		msg = fmt.Sprintf("⏳ Halfway through your mock interview: %s left.", formatMockDuration(limit-elapsed))
+	default:
+		return nil
+	}
+
+	if key.UserID != 0 {
+		msg = s.memberName(key) + ": " + msg
+	}
+	if err := s.tgClient.SendMessage(ctx, key.ChatID, msg); err != nil {
+		return err
+	}
+	return s.store.SetMockSession(ctx, key, *mock)
+}
+
+// finishMock closes a timed-out session with a final evaluation of the last
+// answer submitted during it. A passing answer is saved; otherwise the
+// question is dropped without touching history.
+func (s *Service) finishMock(ctx context.Context, key StateKey, q Question, mock MockSession, elapsed time.Duration, transcript []TutorTurn) error {
+	attempts, err := s.store.ListAnswerAttempts(ctx, key, q.Slug, 1)
+	if err != nil {
+		return err
+	}
+	answer := ""
+	if len(attempts) > 0 && !attempts[0].CreatedAt.Before(mock.StartedAt) {
+		answer = attempts[0].Answer
+	}
+
+	score, source := 0, "Timer"
+	feedback := "No answer was submitted before time ran out."
+	guidance := fallbackGuidance(q, "")
+	var run *codeRun
+	if answer != "" {
+		review, aiUsed := s.reviewAnswer(ctx, q, answer, transcript)
+		run = s.runAnswerCode(ctx, q, answer)
+		score, source = clampScore(review.Score), "Heuristic"
+		if aiUsed {
+			source = "AI"
+		}
+		if text := strings.TrimSpace(review.Feedback); text != "" {
+			feedback = text
+		}
+		if text := strings.TrimSpace(review.Guidance); text != "" {
+			guidance = text
+		} else {
+			guidance = fallbackGuidance(q, answer)
+		}
+	}
+
+	status := "Time's up. The question was not saved; start another round with /mock."
+	if score >= correctAnswerScoreThreshold {
+		if err := s.persistCompletedQuestion(ctx, key, q, score); err != nil {
+			return err
+		}
+		status = "Time's up. Your final answer passed and was saved to history."
+	} else if err := s.store.ClearCurrentQuestion(ctx, key); err != nil {
+		return err
+	}
+	if err := s.store.ClearMockSession(ctx, key); err != nil {
+		return err
+	}
+
+	reply := formatEvaluationMessage(q, score, source, feedback, guidance, status, formatMockClock(elapsed, mock.Minutes), run)
+	return s.tgClient.SendRichMessage(ctx, key.ChatID, reply)
+}
+
+// formatMockClock renders elapsed time against the session length, e.g.
+// "12m 30s of 45m".
+func formatMockClock(elapsed time.Duration, minutes int) string {
+	return fmt.Sprintf("%s of %dm", formatMockDuration(elapsed), minutes)
+}
+
+func formatMockDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Truncate(time.Second)
+	m, sec := int(d/time.Minute), int(d%time.Minute/time.Second)
+	if sec == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dm %02ds", m, sec)
+}
@@ -0,0 +1,218 @@
+package bot
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+//go:embed plans/*.json
+var studyPlanFiles embed.FS
+
+// StudyPlan is a curated, ordered question list such as Blind 75. Questions
+// are served in file order.
+type StudyPlan struct {
+	Name        string
+	Title       string
+	Description string
+	Questions   []Question
+}
+
+// studyPlans holds the bundled plans sorted by file name.
+var studyPlans = mustLoadStudyPlans()
+
+func mustLoadStudyPlans() []StudyPlan {
+	entries, err := studyPlanFiles.ReadDir("plans")
+	if err != nil {
+		panic(fmt.Sprintf("read bundled study plans: %v", err))
+	}
+
+	plans := make([]StudyPlan, 0, len(entries))
+	for _, entry := range entries {
+		raw, err := studyPlanFiles.ReadFile(path.Join("plans", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("read study plan %s: %v", entry.Name(), err))
+		}
+		var file struct {
+			Name        string `json:"name"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Questions   []struct {
+				Slug       string `json:"slug"`
+				Title      string `json:"title"`
+				Difficulty string `json:"difficulty"`
+			} `json:"questions"`
+		}
+		if err := json.Unmarshal(raw, &file); err != nil {
+			panic(fmt.Sprintf("decode study plan %s: %v", entry.Name(), err))
+		}
+
+		plan := StudyPlan{Name: file.Name, Title: file.Title, Description: file.Description}
+		for _, q := range file.Questions {
+			plan.Questions = append(plan.Questions, Question{
+				Slug:       q.Slug,
+				Title:      q.Title,
+				Difficulty: q.Difficulty,
+				URL:        "https://leetcode.com/problems/" + q.Slug + "/",
+			})
+		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+// lookupStudyPlan matches name against plan names and titles ignoring case,
+// spaces and punctuation, so "Blind 75", "blind-75" and "blind75" all match.
+func lookupStudyPlan(name string) (StudyPlan, bool) {
+	want := normalizePlanName(name)
+	if want == "" {
+		return StudyPlan{}, false
+	}
+	for _, plan := range studyPlans {
+		if normalizePlanName(plan.Name) == want || normalizePlanName(plan.Title) == want {
+			return plan, true
+		}
+	}
+	return StudyPlan{}, false
+}
+
+func normalizePlanName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// activeStudyPlan returns the chat's plan or custom question list, ignoring
+// names that no longer match either.
+func (s *Service) activeStudyPlan(ctx context.Context, key StateKey) (StudyPlan, bool, error) {
+	settings, err := s.chatSettings(ctx, key)
+	if err != nil {
+		return StudyPlan{}, false, err
+	}
+	if settings.StudyPlan == "" {
+		return StudyPlan{}, false, nil
+	}
+	if name, ok := strings.CutPrefix(settings.StudyPlan, questionListPlanPrefix); ok {
+		return s.questionListPlan(ctx, key.ChatID, name)
+	}
+	plan, ok := lookupStudyPlan(settings.StudyPlan)
+	if !ok {
+		s.logger.Printf("chat %s has unknown study plan %q", key, settings.StudyPlan)
+	}
+	return plan, ok, nil
+}
+
+// nextPlanQuestion returns the first plan question that is neither seen nor
+// excluded. finished reports that every plan question has been seen.
+func nextPlanQuestion(plan StudyPlan, seen, exclude map[string]struct{}) (q Question, ok, finished bool) {
+	finished = true
+	for _, item := range plan.Questions {
+		if _, done := seen[item.Slug]; done {
+			continue
+		}
+		finished = false
+		if _, skip := exclude[item.Slug]; skip {
+			continue
+		}
+		return item, true, false
+	}
+	return Question{}, false, finished
+}
+
+// availablePlan keeps the plan questions that are in the catalog and match,
+// in plan order, using the catalog entries so topic tags are known. A nil
+// matches keeps every catalog question. skipped counts plan questions
+// missing from the catalog, such as paid-only ones, which have no statement
+// and cannot be opened.
+func availablePlan(plan StudyPlan, catalog []Question, matches func(Question) bool) (available StudyPlan, skipped int) {
+	bySlug := make(map[string]Question, len(catalog))
+	for _, q := range catalog {
+		bySlug[q.Slug] = q
+	}
+	available = plan
+	available.Questions = make([]Question, 0, len(plan.Questions))
+	for _, item := range plan.Questions {
+		q, ok := bySlug[item.Slug]
+		if !ok {
+			skipped++
+			continue
+		}
+		if matches == nil || matches(q) {
+			available.Questions = append(available.Questions, q)
+		}
+	}
+	return available, skipped
+}
+
+func (s *Service) sendPlanProgress(ctx context.Context, key StateKey) error {
+	plan, ok, err := s.activeStudyPlan(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return s.tgClient.SendMessage(ctx, key.ChatID, "No study plan is active. Use /plan <name> to start one.")
+	}
+	all, err := s.questions.AllQuestions(ctx)
+	if err != nil {
+		return err
+	}
+	answered, err := s.store.ListAllAnsweredQuestions(ctx, key)
+	if err != nil {
+		return err
+	}
+	available, skipped := availablePlan(plan, all, nil)
+	return s.tgClient.SendRichMessage(ctx, key.ChatID, formatPlanProgress(available, skipped, answered))
+}
+
+// formatPlanProgress reports progress over the available plan questions;
+// skipped ones are counted on their own line.
+func formatPlanProgress(plan StudyPlan, skipped int, answered []AnsweredQuestion) string {
+	solved := make(map[string]struct{}, len(answered))
+	for _, item := range answered {
+		solved[item.Slug] = struct{}{}
+	}
+
+	done := 0
+	total := make(map[string]int, len(statsDifficulties))
+	byDifficulty := make(map[string]int, len(statsDifficulties))
+	var next *Question
+	for i, q := range plan.Questions {
+		difficulty := normalizeDifficultyLabel(q.Difficulty)
+		total[difficulty]++
+		if _, ok := solved[q.Slug]; ok {
+			done++
+			byDifficulty[difficulty]++
+		} else if next == nil {
+			next = &plan.Questions[i]
+		}
+	}
+
+	lines := []string{
+		fmt.Sprintf("*📚 %s*", escapeMarkdownV2(plan.Title)),
+		"",
+		escapeMarkdownV2(fmt.Sprintf("Solved %d/%d (%d%%)", done, len(plan.Questions), done*100/max(len(plan.Questions), 1))),
+	}
+	for _, d := range statsDifficulties {
+		if total[d] > 0 {
+			lines = append(lines, fmt.Sprintf("• %s: %d/%d", d, byDifficulty[d], total[d]))
+		}
+	}
+	if skipped > 0 {
+		lines = append(lines, escapeMarkdownV2(fmt.Sprintf("Skipped %d paid-only or unavailable question(s).", skipped)))
+	}
+	lines = append(lines, "")
+	if next == nil {
+		lines = append(lines, escapeMarkdownV2("Plan complete. Pick another with /plan <name> or go back to random questions with /plan off."))
+	} else {
+		lines = append(lines, fmt.Sprintf("Next: [%s](%s) \\(%s\\)", escapeMarkdownV2(next.Title), escapeMarkdownV2URL(next.URL), escapeMarkdownV2(next.Difficulty)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,50 @@
+package bot
+
+import "testing"
+
+func TestBundledStudyPlans(t *testing.T) {
+	sizes := map[string]int{"blind75": 75, "grind75": 75, "neetcode150": 150}
+	if len(studyPlans) != len(sizes) {
+		t.Fatalf("expected %d bundled plans, got %d", len(sizes), len(studyPlans))
+	}
+	for _, plan := range studyPlans {
+		if got := len(plan.Questions); got != sizes[plan.Name] {
+			t.Fatalf("plan %s has %d questions, want %d", plan.Name, got, sizes[plan.Name])
+		}
+		seen := make(map[string]struct{}, len(plan.Questions))
+		for _, q := range plan.Questions {
+			if _, dup := seen[q.Slug]; dup {
+				t.Fatalf("plan %s lists %s twice", plan.Name, q.Slug)
+			}
+			seen[q.Slug] = struct{}{}
+			if q.Title == "" || normalizeDifficultyLabel(q.Difficulty) == "Other" {
+				t.Fatalf("plan %s has an incomplete entry: %+v", plan.Name, q)
+			}
+		}
+	}
+}
+
+func TestLookupStudyPlanIgnoresSpacingAndCase(t *testing.T) {
+	for _, name := range []string{"blind75", "Blind 75", "blind-75", "BLIND_75"} {
+		if plan, ok := lookupStudyPlan(name); !ok || plan.Name != "blind75" {
+			t.Fatalf("lookupStudyPlan(%q) = %q, %v; want blind75", name, plan.Name, ok)
+		}
+	}
+	if _, ok := lookupStudyPlan("blind"); ok {
+		t.Fatalf("expected a partial name not to match")
+	}
+}
+
+func TestNextPlanQuestion(t *testing.T) {
+	plan := StudyPlan{Questions: []Question{{Slug: "a"}, {Slug: "b"}}}
+
+	if q, ok, _ := nextPlanQuestion(plan, map[string]struct{}{"a": {}}, nil); !ok || q.Slug != "b" {
+		t.Fatalf("expected b after a is seen, got %+v, %v", q, ok)
+	}
+	if _, ok, finished := nextPlanQuestion(plan, map[string]struct{}{"a": {}}, map[string]struct{}{"b": {}}); ok || finished {
+		t.Fatalf("expected no pick but an unfinished plan when the last question is excluded")
+	}
+	if _, ok, finished := nextPlanQuestion(plan, map[string]struct{}{"a": {}, "b": {}}, nil); ok || !finished {
+		t.Fatalf("expected the plan to be finished once every question is seen")
+	}
+}
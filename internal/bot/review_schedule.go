@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	defaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+	// passingRecallQuality is the lowest SM-2 grade that counts as recalled.
+	passingRecallQuality = 3
+)
+
+// scheduleReview applies one SM-2 step for an evaluation score (1-10).
+func scheduleReview(state ReviewState, score int, now time.Time) ReviewState {
+	quality := recallQuality(score)
+
+	ease := state.EaseFactor
+	if ease <= 0 {
+		ease = defaultEaseFactor
+	}
+
+	next := ReviewState{EaseFactor: ease}
+	if quality < passingRecallQuality {
+		next.Repetitions = 0
+		next.IntervalDays = 1
+	} else {
+		switch state.Repetitions {
+		case 0:
+			next.IntervalDays = 1
+		case 1:
+			next.IntervalDays = 6
+		default:
+			interval := max(state.IntervalDays, 1)
+			next.IntervalDays = int(math.Round(float64(interval) * ease))
+		}
+		next.Repetitions = state.Repetitions + 1
+	}
+
+	miss := float64(5 - quality)
+	next.EaseFactor = math.Max(minEaseFactor, ease+0.1-miss*(0.08+miss*0.02))
+	next.DueAt = now.Add(time.Duration(next.IntervalDays) * 24 * time.Hour)
+	return next
+}
+
+// recallQuality maps the 1-10 evaluation score onto SM-2's 0-5 grade so that
+// the pass mark lines up with correctAnswerScoreThreshold.
+func recallQuality(score int) int {
+	switch score = clampScore(score); {
+	case score >= 10:
+		return 5
+	case score >= 9:
+		return 4
+	case score >= correctAnswerScoreThreshold:
+		return passingRecallQuality
+	case score >= 5:
+		return 2
+	case score >= 3:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// recordReview advances the schedule for a question that is already in the
+// answered history. Questions that were never completed have no schedule.
+func (s *Service) recordReview(ctx context.Context, key StateKey, q Question, score int) {
+	state, err := s.store.GetReviewState(ctx, key, q.Slug)
+	if errors.Is(err, ErrAnsweredQuestionNotFound) {
+		return
+	}
+	if err != nil {
+		s.logger.Printf("load review state failed for chat %s slug=%s: %v", key, q.Slug, err)
+		return
+	}
+
+	next := scheduleReview(state, score, s.nowFn().UTC())
+	if err := s.store.UpdateReviewState(ctx, key, q.Slug, next); err != nil {
+		s.logger.Printf("update review state failed for chat %s slug=%s: %v", key, q.Slug, err)
+	}
+}
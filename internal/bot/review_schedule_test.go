@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleReviewFollowsSM2(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	first := scheduleReview(ReviewState{}, 9, now)
+	if first.Repetitions != 1 || first.IntervalDays != 1 || first.EaseFactor != defaultEaseFactor {
+		t.Fatalf("unexpected first review: %+v", first)
+	}
+	if !first.DueAt.Equal(now.Add(24 * time.Hour)) {
+		t.Fatalf("DueAt = %s, want one day later", first.DueAt)
+	}
+
+	second := scheduleReview(first, 9, now)
+	if second.Repetitions != 2 || second.IntervalDays != 6 {
+		t.Fatalf("unexpected second review: %+v", second)
+	}
+
+	third := scheduleReview(second, 10, now)
+	if third.Repetitions != 3 || third.IntervalDays != 15 {
+		t.Fatalf("expected interval 6*2.5=15, got %+v", third)
+	}
+	if third.EaseFactor <= second.EaseFactor {
+		t.Fatalf("perfect recall should raise ease, got %v -> %v", second.EaseFactor, third.EaseFactor)
+	}
+
+	lapse := scheduleReview(third, 4, now)
+	if lapse.Repetitions != 0 || lapse.IntervalDays != 1 {
+		t.Fatalf("failed recall should restart the schedule, got %+v", lapse)
+	}
+	if lapse.EaseFactor >= third.EaseFactor {
+		t.Fatalf("failed recall should lower ease, got %v -> %v", third.EaseFactor, lapse.EaseFactor)
+	}
+}
+
+func TestScheduleReviewEaseFloor(t *testing.T) {
+	state := ReviewState{EaseFactor: minEaseFactor}
+	for i := 0; i < 5; i++ {
+		state = scheduleReview(state, 1, time.Now())
+	}
+	if state.EaseFactor != minEaseFactor {
+		t.Fatalf("EaseFactor = %v, want floor %v", state.EaseFactor, minEaseFactor)
+	}
+}
+
+func TestRecallQualityPassesAtCorrectThreshold(t *testing.T) {
+	if got := recallQuality(correctAnswerScoreThreshold); got != passingRecallQuality {
+		t.Fatalf("recallQuality(%d) = %d, want %d", correctAnswerScoreThreshold, got, passingRecallQuality)
+	}
+	if got := recallQuality(correctAnswerScoreThreshold - 1); got >= passingRecallQuality {
+		t.Fatalf("recallQuality(%d) = %d, want below passing", correctAnswerScoreThreshold-1, got)
+	}
+}
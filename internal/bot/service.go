@@ -8,6 +8,7 @@ import (
 	"log"
 	"math/rand"
 	"net/http"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -23,6 +24,7 @@ type Service struct {
 	tgClient               TelegramSender
 	questions              QuestionProvider
 	coach                  Coach
+	codeRunner             CodeRunner
 	store                  StateStore
 	commandHandler         *commands.Handler
 	allowedUsers           map[string]struct{}
@@ -34,7 +36,13 @@ type Service struct {
 	defaultLoc             *time.Location
 	nowFn                  func() time.Time
 	pendingTopicMu         sync.RWMutex
-	pendingTopic           map[int64]bool
+	pendingTopic           map[StateKey]bool
+	botUsername            string
+	botMention             *regexp.Regexp
+	memberNamesMu          sync.RWMutex
+	memberNames            map[StateKey]string
+	formatCache            *formatCache
+	formatStore            FormattedPromptStore
 }
 
 func NewService(
@@ -69,7 +77,9 @@ func NewService(
 		dailySchedulingEnabled: dailySchedulingEnabled,
 		defaultLoc:             loc,
 		nowFn:                  time.Now,
-		pendingTopic:           make(map[int64]bool),
+		pendingTopic:           make(map[StateKey]bool),
+		memberNames:            make(map[StateKey]string),
+		formatCache:            newFormatCache(defaultFormatCacheSize),
 	}
 	svc.commandHandler = newCommandHandler(svc)
 	return svc
@@ -93,13 +103,24 @@ func (s *Service) WebhookHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.HandleUpdate(r.Context(), update)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleUpdate processes one Telegram update regardless of whether it arrived
+// through the webhook or long polling.
+func (s *Service) HandleUpdate(ctx context.Context, update telegram.Update) {
 	if update.Message != nil {
-		if err := s.handleMessage(r.Context(), *update.Message); err != nil {
+		if err := s.handleMessage(ctx, *update.Message); err != nil {
 			s.logger.Printf("handle message failed: %v", err)
 		}
 	}
-
-	w.WriteHeader(http.StatusOK)
+	if update.CallbackQuery != nil {
+		if err := s.handleCallbackQuery(ctx, *update.CallbackQuery); err != nil {
+			s.logger.Printf("handle callback query failed: %v", err)
+		}
+	}
 }
 
 func (s *Service) CronHandler(w http.ResponseWriter, r *http.Request) {
@@ -111,6 +132,8 @@ func (s *Service) CronHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
+	s.tickMockSessions(r.Context(), s.nowFn().UTC())
+	s.sendWeeklySummaries(r.Context(), s.nowFn().UTC())
 	if !s.dailySchedulingEnabled {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("daily scheduling is off"))
@@ -143,11 +166,11 @@ func (s *Service) CronHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		processed++
-		if err := s.sendUniqueQuestion(r.Context(), chat.ChatID, "Daily LeetCode challenge:"); err != nil {
+		if err := s.sendDaily(r.Context(), chat); err != nil {
 			s.logger.Printf("daily send failed for chat %d: %v", chat.ChatID, err)
 			continue
 		}
-		if err := s.store.MarkDailySent(r.Context(), chat.ChatID, today); err != nil {
+		if err := s.store.MarkDailySent(r.Context(), ChatKey(chat.ChatID), today); err != nil {
 			s.logger.Printf("mark daily sent failed for chat %d: %v", chat.ChatID, err)
 		}
 		sent++
@@ -165,41 +188,75 @@ func (s *Service) Warmup(ctx context.Context) {
 }
 
 func (s *Service) handleMessage(ctx context.Context, msg telegram.Message) error {
+	text := strings.TrimSpace(msg.Text)
+	if isGroupChat(msg.Chat) {
+		var addressed bool
+		if text, addressed = s.groupMessageText(msg, text); !addressed {
+			return nil
+		}
+	}
+
 	if !s.isAllowedUsername(msg.From.Username) {
 		s.logger.Printf("blocked message from unauthorized username=%q chat=%d", msg.From.Username, msg.Chat.ID)
 		return s.tgClient.SendMessage(ctx, msg.Chat.ID, "You are not allowed to use this bot.")
 	}
 
-	text := strings.TrimSpace(msg.Text)
 	if text == "" {
 		return nil
 	}
 
+	key := stateKeyFor(msg.Chat, msg.From)
+	s.rememberMember(key, displayName(msg.From))
 	if strings.HasPrefix(text, "/") {
-		return s.handleCommand(ctx, msg.Chat.ID, text)
+		return s.handleCommand(ctx, key, text)
 	}
 
-	if s.isPendingTopicSelection(msg.Chat.ID) {
-		s.setPendingTopicSelection(msg.Chat.ID, false)
-		return s.sendUniqueQuestionByTopic(ctx, msg.Chat.ID, "Here is your random LeetCode question:", text)
+	if s.isPendingTopicSelection(key) {
+		s.setPendingTopicSelection(key, false)
+		return s.sendUniqueQuestionByTopic(ctx, key, "Here is your random LeetCode question:", text)
 	}
 
-	return s.handleFreeTextAnswer(ctx, msg.Chat.ID, text)
+	return s.handleFreeTextAnswer(ctx, key, text)
 }
 
-func (s *Service) handleFreeTextAnswer(ctx context.Context, chatID int64, answer string) error {
-	settings, err := s.store.GetChatSettings(ctx, chatID)
+func (s *Service) handleCallbackQuery(ctx context.Context, query telegram.CallbackQuery) error {
+	if query.Message == nil {
+		return s.tgClient.AnswerCallbackQuery(ctx, query.ID, "This button has expired.")
+	}
+
+	key := stateKeyFor(query.Message.Chat, query.From)
+	if !s.isAllowedUsername(query.From.Username) {
+		s.logger.Printf("blocked callback from unauthorized username=%q chat=%s", query.From.Username, key)
+		return s.tgClient.AnswerCallbackQuery(ctx, query.ID, "You are not allowed to use this bot.")
+	}
+
+	if err := s.tgClient.AnswerCallbackQuery(ctx, query.ID, ""); err != nil {
+		s.logger.Printf("answer callback query failed for chat %s: %v", key, err)
+	}
+	s.rememberMember(key, displayName(query.From))
+	s.setPendingTopicSelection(key, false)
+	return s.commandHandler.HandleCallback(ctx, commands.StateKey(key), query.Data)
+}
+
+func (s *Service) handleFreeTextAnswer(ctx context.Context, key StateKey, answer string) error {
+	settings, err := s.chatSettings(ctx, key)
 	if err != nil {
 		return err
 	}
 	if settings.CurrentQuestion == nil {
-		return s.tgClient.SendMessage(ctx, chatID, "No active question. Use /lc first.")
+		return s.tgClient.SendMessage(ctx, key.ChatID, "No active question. Use /lc first.")
 	}
 	if learnerContext, isHint := parseHintRequest(answer); isHint {
-		return s.sendHint(ctx, chatID, *settings.CurrentQuestion, learnerContext)
+		return s.sendHint(ctx, key, settings, learnerContext)
+	}
+	if question, ok := followUpQuestion(answer); ok {
+		if handled, err := s.answerFollowUp(ctx, key, settings, question); handled {
+			return err
+		}
 	}
 
-	review, aiUsed := s.reviewAnswer(ctx, *settings.CurrentQuestion, answer)
+	review, aiUsed := s.reviewAnswer(ctx, *settings.CurrentQuestion, answer, tutorTranscript(settings))
+	run := s.runAnswerCode(ctx, *settings.CurrentQuestion, answer)
 	source := "Heuristic"
 	if aiUsed {
 		source = "AI"
@@ -214,36 +271,101 @@ func (s *Service) handleFreeTextAnswer(ctx context.Context, chatID int64, answer
 		guidance = fallbackGuidance(*settings.CurrentQuestion, answer)
 	}
 
+	elapsed := ""
+	mock := activeMock(settings)
+	if mock != nil {
+		elapsed = formatMockClock(s.nowFn().Sub(mock.StartedAt), mock.Minutes)
+	}
+
 	score := clampScore(review.Score)
+	attempt := AnswerAttempt{
+		Slug:      settings.CurrentQuestion.Slug,
+		Answer:    answer,
+		Score:     score,
+		Source:    source,
+		Feedback:  feedback,
+		CreatedAt: s.nowFn().UTC(),
+	}
+	if err := s.store.RecordAnswerAttempt(ctx, key, attempt); err != nil {
+		s.logger.Printf("record answer attempt failed for chat %s slug=%s: %v", key, attempt.Slug, err)
+	}
+	s.recordSessionAttempt(ctx, key, attempt.Slug, score)
+	s.recordTutorTurns(ctx, key, settings,
+		TutorTurn{Kind: TutorTurnAnswer, Text: answer},
+		TutorTurn{Kind: TutorTurnReview, Text: reviewTurnText(score, source, feedback)},
+	)
+
 	status := "Not saved yet. Improve and resubmit, or use /done."
+	keyboard := evaluationKeyboard(true, false)
 	if score >= correctAnswerScoreThreshold {
-		if err := s.persistCompletedQuestion(ctx, chatID, *settings.CurrentQuestion); err != nil {
+		session, err := s.sessionQuestion(ctx, key, attempt.Slug)
+		if err != nil {
+			return err
+		}
+		keyboard = evaluationKeyboard(false, session == nil)
+		if err := s.persistCompletedQuestion(ctx, key, *settings.CurrentQuestion, score); err != nil {
 			return err
 		}
 		status = "Correct. Saved to history."
+		if mock != nil {
+			if err := s.store.ClearMockSession(ctx, key); err != nil {
+				return err
+			}
+			status = "Correct. Saved to history. Mock interview complete."
+		}
 	}
+	s.recordReview(ctx, key, *settings.CurrentQuestion, score)
 
-	reply := formatEvaluationMessage(*settings.CurrentQuestion, score, source, feedback, guidance, status)
-	if err := s.tgClient.SendRichMessage(ctx, chatID, reply); err != nil {
+	reply := formatEvaluationMessage(*settings.CurrentQuestion, score, source, feedback, guidance, status, elapsed, run)
+	if err := s.tgClient.SendRichMessageWithKeyboard(ctx, key.ChatID, reply, keyboard); err != nil {
 		return err
 	}
 
+	if score >= correctAnswerScoreThreshold {
+		if _, err := s.advanceSession(ctx, key, attempt.Slug, SessionOutcomeSolved); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (s *Service) sendUniqueQuestion(ctx context.Context, chatID int64, intro string, transientExclude ...string) error {
-	seen, err := s.store.SeenQuestionSet(ctx, chatID)
+// sendUniqueQuestion serves the next unseen question of the active study
+// plan, or a random unseen one from the catalog when no plan is active or the
+// plan has nothing left to offer.
+func (s *Service) sendUniqueQuestion(ctx context.Context, key StateKey, intro string, transientExclude ...string) error {
+	seen, err := s.store.SeenQuestionSet(ctx, key)
 	if err != nil {
 		return err
 	}
 
 	excludeSet := toSlugSet(transientExclude)
-	effectiveSeen := mergeSlugSets(seen, excludeSet)
+	note := ""
+	plan, hasPlan, err := s.activeStudyPlan(ctx, key)
+	if err != nil {
+		return err
+	}
+	if hasPlan {
+		all, err := s.questions.AllQuestions(ctx)
+		if err != nil {
+			return err
+		}
+		available, _ := availablePlan(plan, all, nil)
+		q, ok, finished := nextPlanQuestion(available, seen, excludeSet)
+		if ok {
+			if err := s.store.SetCurrentQuestion(ctx, key, q); err != nil {
+				return err
+			}
+			return s.sendQuestionMessage(ctx, key, s.questionMessage(ctx, intro, "", q))
+		}
+		if finished {
+			note = fmt.Sprintf("You have finished every question in %s, so this one is random. Send /plan off or pick another plan.\n\n", plan.Title)
+		}
+	}
 
+	effectiveSeen := mergeSlugSets(seen, excludeSet)
 	q, err := s.questions.RandomQuestion(ctx, effectiveSeen)
-	note := ""
 	if errors.Is(err, ErrNoUnseenQuestions) {
-		if err := s.store.ResetServedQuestions(ctx, chatID); err != nil {
+		if err := s.store.ResetServedQuestions(ctx, key); err != nil {
 			return err
 		}
 		q, err = s.questions.RandomQuestion(ctx, excludeSet)
@@ -256,29 +378,33 @@ func (s *Service) sendUniqueQuestion(ctx context.Context, chatID int64, intro st
 		return err
 	}
 
-	if err := s.store.SetCurrentQuestion(ctx, chatID, q); err != nil {
+	if err := s.store.SetCurrentQuestion(ctx, key, q); err != nil {
 		return err
 	}
 
-	prompt, err := s.questions.QuestionPrompt(ctx, q.Slug)
-	if err != nil {
-		s.logger.Printf("question prompt lookup failed for slug=%s: %v", q.Slug, err)
-		prompt = ""
-	}
-	prompt = s.formatQuestionPrompt(ctx, q, prompt)
+	msg := s.questionMessage(ctx, intro, note, q)
+	return s.sendQuestionMessage(ctx, key, msg)
+}
+
+func (s *Service) sendUniqueQuestionByTopic(ctx context.Context, key StateKey, intro, topic string, transientExclude ...string) error {
+	return s.sendFilteredQuestion(ctx, key, intro, questionFilter{Topic: topic}, transientExclude...)
+}
 
-	msg := formatQuestionMessage(intro, note, q, prompt)
-	return s.tgClient.SendRichMessage(ctx, chatID, msg)
+// questionFilter narrows question selection. Empty fields match everything.
+type questionFilter struct {
+	Topic      string
+	Difficulty string
 }
 
-func (s *Service) sendUniqueQuestionByTopic(ctx context.Context, chatID int64, intro, topic string, transientExclude ...string) error {
-	topic = strings.TrimSpace(strings.ToLower(topic))
+func (s *Service) sendFilteredQuestion(ctx context.Context, key StateKey, intro string, filter questionFilter, transientExclude ...string) error {
+	topic := strings.TrimSpace(strings.ToLower(filter.Topic))
 	if topic == "random" {
 		topic = ""
 	}
+	difficulty := strings.TrimSpace(filter.Difficulty)
 
-	if topic == "" {
-		return s.sendUniqueQuestion(ctx, chatID, intro, transientExclude...)
+	if topic == "" && difficulty == "" {
+		return s.sendUniqueQuestion(ctx, key, intro, transientExclude...)
 	}
 
 	all, err := s.questions.AllQuestions(ctx)
@@ -286,58 +412,129 @@ func (s *Service) sendUniqueQuestionByTopic(ctx context.Context, chatID int64, i
 		return err
 	}
 
-	seen, err := s.store.SeenQuestionSet(ctx, chatID)
+	seen, err := s.store.SeenQuestionSet(ctx, key)
 	if err != nil {
 		return err
 	}
 	excludeSet := toSlugSet(transientExclude)
-	effectiveSeen := mergeSlugSets(seen, excludeSet)
+
+	tag := resolveTopicTag(topic)
+	matches := func(q Question) bool {
+		if difficulty != "" && !strings.EqualFold(q.Difficulty, difficulty) {
+			return false
+		}
+		return topic == "" || questionMatchesTopic(q, topic, tag)
+	}
+
+	note := ""
+	plan, hasPlan, err := s.activeStudyPlan(ctx, key)
+	if err != nil {
+		return err
+	}
+	if hasPlan {
+		available, _ := availablePlan(plan, all, matches)
+		if q, ok, _ := nextPlanQuestion(available, seen, excludeSet); ok {
+			if err := s.store.SetCurrentQuestion(ctx, key, q); err != nil {
+				return err
+			}
+			return s.sendQuestionMessage(ctx, key, s.questionMessage(ctx, intro, "", q))
+		}
+		note = fmt.Sprintf("Nothing unseen in %s matches this filter, so this one is from the whole catalog.\n\n", plan.Title)
+	}
 
 	candidates := make([]Question, 0)
+	seenMatches := make([]Question, 0)
 	for _, q := range all {
-		if _, exists := effectiveSeen[q.Slug]; exists {
+		if !matches(q) {
 			continue
 		}
-		haystack := strings.ToLower(q.Title + " " + q.Slug + " " + q.Difficulty)
-		if strings.Contains(haystack, topic) {
-			candidates = append(candidates, q)
+		if _, excluded := excludeSet[q.Slug]; excluded {
+			continue
 		}
+		if _, exists := seen[q.Slug]; exists {
+			seenMatches = append(seenMatches, q)
+			continue
+		}
+		candidates = append(candidates, q)
+	}
+
+	if len(candidates) == 0 && topic == "" && len(seenMatches) > 0 {
+		// A difficulty-only pool behaves like the full catalog: reset history
+		// once it is exhausted instead of refusing to serve.
+		if err := s.store.ResetServedQuestions(ctx, key); err != nil {
+			return err
+		}
+		candidates = seenMatches
+		note += "Question history exhausted and reset to allow new picks.\n\n"
 	}
 
 	if len(candidates) == 0 {
-		return s.tgClient.SendMessage(ctx, chatID, "No unseen questions found for that topic. Try another topic or send /lc random.")
+		return s.tgClient.SendMessage(ctx, key.ChatID, noFilteredQuestionsMessage(topic, difficulty))
 	}
 
 	q := candidates[rand.Intn(len(candidates))]
-	if err := s.store.SetCurrentQuestion(ctx, chatID, q); err != nil {
+	if err := s.store.SetCurrentQuestion(ctx, key, q); err != nil {
 		return err
 	}
 
-	prompt, err := s.questions.QuestionPrompt(ctx, q.Slug)
-	if err != nil {
-		s.logger.Printf("question prompt lookup failed for slug=%s: %v", q.Slug, err)
-		prompt = ""
+	msg := s.questionMessage(ctx, intro, note, q)
+	return s.sendQuestionMessage(ctx, key, msg)
+}
+
+func noFilteredQuestionsMessage(topic, difficulty string) string {
+	switch {
+	case topic == "":
+		return fmt.Sprintf("No %s questions are available right now. Try /lc random.", difficulty)
+	case difficulty == "":
+		return "No unseen questions found for that topic. Try another topic or send /lc random."
+	default:
+		return fmt.Sprintf("No unseen %s questions found for that topic. Try another topic or difficulty, or send /lc random.", difficulty)
 	}
-	prompt = s.formatQuestionPrompt(ctx, q, prompt)
+}
 
-	msg := formatQuestionMessage(intro, "", q, prompt)
-	return s.tgClient.SendRichMessage(ctx, chatID, msg)
+func (s *Service) sendQuestionMessage(ctx context.Context, key StateKey, msg string) error {
+	return s.tgClient.SendRichMessageWithKeyboard(ctx, key.ChatID, msg, questionKeyboard())
 }
 
-func (s *Service) setPendingTopicSelection(chatID int64, pending bool) {
+func (s *Service) setPendingTopicSelection(key StateKey, pending bool) {
 	s.pendingTopicMu.Lock()
 	defer s.pendingTopicMu.Unlock()
 	if pending {
-		s.pendingTopic[chatID] = true
+		s.pendingTopic[key] = true
 		return
 	}
-	delete(s.pendingTopic, chatID)
+	delete(s.pendingTopic, key)
 }
 
-func (s *Service) isPendingTopicSelection(chatID int64) bool {
+func (s *Service) isPendingTopicSelection(key StateKey) bool {
 	s.pendingTopicMu.RLock()
 	defer s.pendingTopicMu.RUnlock()
-	return s.pendingTopic[chatID]
+	return s.pendingTopic[key]
+}
+
+// questionMessage fetches q's statement and renders the question message. A
+// failed lookup still sends the title and link.
+func (s *Service) questionMessage(ctx context.Context, intro, note string, q Question) string {
+	detail := s.questionDetail(ctx, q.Slug)
+	prompt := s.formatQuestionPrompt(ctx, q, detail.Content)
+	return formatQuestionMessage(intro, note, q, prompt, detail)
+}
+
+// questionDetail returns the question page, or a zero detail when the lookup
+// fails so callers can degrade to title-only behaviour.
+func (s *Service) questionDetail(ctx context.Context, slug string) QuestionDetail {
+	detail, err := s.questions.QuestionDetail(ctx, slug)
+	if err != nil {
+		s.logger.Printf("question detail lookup failed for slug=%s: %v", slug, err)
+		return QuestionDetail{}
+	}
+	return detail
+}
+
+// questionStatement returns q's statement as plain text for the coach, or ""
+// when it could not be fetched.
+func (s *Service) questionStatement(ctx context.Context, q Question) string {
+	return statementText(q, s.questionDetail(ctx, q.Slug).Content)
 }
 
 func (s *Service) formatQuestionPrompt(ctx context.Context, q Question, prompt string) string {
@@ -351,6 +548,15 @@ func (s *Service) formatQuestionPrompt(ctx context.Context, q Question, prompt s
 		return prompt
 	}
 
+	model := ""
+	if namer, ok := formatter.(FormatModelNamer); ok {
+		model = namer.FormatModel()
+	}
+	key := formatCacheKey(q.Slug, model, prompt)
+	if cached, ok := s.cachedFormattedPrompt(ctx, key); ok {
+		return cached
+	}
+
 	formatted, err := formatter.FormatQuestion(ctx, q, prompt)
 	if err != nil {
 		s.logger.Printf("AI question formatting failed for slug=%s, using raw prompt: %v", q.Slug, err)
@@ -363,25 +569,35 @@ func (s *Service) formatQuestionPrompt(ctx context.Context, q Question, prompt s
 		return prompt
 	}
 
+	s.storeFormattedPrompt(ctx, FormattedPrompt{Key: key, Slug: q.Slug, Model: model, Text: formatted})
 	return formatted
 }
 
-func (s *Service) persistCompletedQuestion(ctx context.Context, chatID int64, q Question) error {
-	if err := s.store.AddServedQuestion(ctx, chatID, q); err != nil {
+// persistCompletedQuestion saves q to the learner's history. score is the
+// passing evaluation, or zero when the question was closed with /done.
+func (s *Service) persistCompletedQuestion(ctx context.Context, key StateKey, q Question, score int) error {
+	if err := s.store.AddServedQuestion(ctx, key, q); err != nil {
 		return err
 	}
-	if err := s.store.MarkQuestionAnswered(ctx, chatID, q); err != nil {
+	if err := s.store.MarkQuestionAnswered(ctx, key, q); err != nil {
 		return err
 	}
-	if err := s.store.ClearCurrentQuestion(ctx, chatID); err != nil {
+	if err := s.store.ClearCurrentQuestion(ctx, key); err != nil {
 		return err
 	}
+	s.recordGroupSolve(ctx, key, q, score)
 	return nil
 }
 
-func (s *Service) reviewAnswer(ctx context.Context, q Question, answer string) (AnswerReview, bool) {
+// reviewAnswer grades answer with the coach, which also sees the statement
+// and transcript, and falls back to the heuristic grader.
+func (s *Service) reviewAnswer(ctx context.Context, q Question, answer string, transcript []TutorTurn) (AnswerReview, bool) {
 	if s.coach != nil {
-		review, err := s.coach.ReviewAnswer(ctx, q, answer)
+		review, err := s.coach.ReviewAnswer(ctx, q, ReviewRequest{
+			Answer:     answer,
+			Statement:  s.questionStatement(ctx, q),
+			Transcript: transcript,
+		})
 		if err == nil {
 			if review.Score == 0 {
 				review.Score = 5
@@ -392,7 +608,7 @@ func (s *Service) reviewAnswer(ctx context.Context, q Question, answer string) (
 		s.logger.Printf("AI review failed, falling back to heuristic grading: %v", err)
 	}
 
-	score, feedback := gradeAnswer(answer, q.Difficulty)
+	score, feedback := gradeAnswer(answer, q.Difficulty, s.questionDetail(ctx, q.Slug).TopicTags)
 	return AnswerReview{
 		Score:    score,
 		Feedback: feedback,
@@ -4,24 +4,31 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
 	"time"
+
+	"telegram-leetcode-bot/internal/telegram"
 )
 
 type fakeTelegramClient struct {
-	messages  map[int64][]string
-	richCount map[int64]int
+	messages        map[int64][]string
+	richCount       map[int64]int
+	keyboards       map[int64][]telegram.InlineKeyboardMarkup
+	callbackAnswers []string
 }
 
 func newFakeTelegramClient() *fakeTelegramClient {
 	return &fakeTelegramClient{
 		messages:  make(map[int64][]string),
 		richCount: make(map[int64]int),
+		keyboards: make(map[int64][]telegram.InlineKeyboardMarkup),
 	}
 }
 
@@ -36,8 +43,19 @@ func (f *fakeTelegramClient) SendRichMessage(_ context.Context, chatID int64, te
 	return nil
 }
 
+func (f *fakeTelegramClient) SendRichMessageWithKeyboard(ctx context.Context, chatID int64, text string, keyboard telegram.InlineKeyboardMarkup) error {
+	f.keyboards[chatID] = append(f.keyboards[chatID], keyboard)
+	return f.SendRichMessage(ctx, chatID, text)
+}
+
+func (f *fakeTelegramClient) AnswerCallbackQuery(_ context.Context, callbackQueryID, _ string) error {
+	f.callbackAnswers = append(f.callbackAnswers, callbackQueryID)
+	return nil
+}
+
 type fakeQuestionProvider struct {
 	questions []Question
+	details   map[string]QuestionDetail
 }
 
 func (f *fakeQuestionProvider) RandomQuestion(_ context.Context, seen map[string]struct{}) (Question, error) {
@@ -56,39 +74,60 @@ func (f *fakeQuestionProvider) AllQuestions(_ context.Context) ([]Question, erro
 	return out, nil
 }
 
-func (f *fakeQuestionProvider) QuestionPrompt(_ context.Context, slug string) (string, error) {
+func (f *fakeQuestionProvider) QuestionDetail(_ context.Context, slug string) (QuestionDetail, error) {
+	if detail, ok := f.details[slug]; ok {
+		return detail, nil
+	}
 	for _, q := range f.questions {
 		if q.Slug == slug {
-			return q.Title + " statement", nil
+			return QuestionDetail{Slug: slug, Content: q.Title + " statement"}, nil
 		}
 	}
-	return "Question statement unavailable.", nil
+	return QuestionDetail{Slug: slug, Content: "Question statement unavailable."}, nil
 }
 
 type fakeCoach struct {
 	review            AnswerReview
 	reviewErr         error
+	lastReviewRequest ReviewRequest
 	hint              string
 	hintErr           error
+	lastHintRequest   HintRequest
+	followUp          string
+	followUpErr       error
+	lastFollowUp      FollowUpRequest
 	questionPrompt    string
 	questionPromptErr error
+	formatModel       string
+	formatCalls       int
 }
 
-func (f *fakeCoach) ReviewAnswer(_ context.Context, _ Question, _ string) (AnswerReview, error) {
+func (f *fakeCoach) ReviewAnswer(_ context.Context, _ Question, req ReviewRequest) (AnswerReview, error) {
+	f.lastReviewRequest = req
 	if f.reviewErr != nil {
 		return AnswerReview{}, f.reviewErr
 	}
 	return f.review, nil
 }
 
-func (f *fakeCoach) GenerateHint(_ context.Context, _ Question, _ string) (string, error) {
+func (f *fakeCoach) GenerateHint(_ context.Context, _ Question, req HintRequest) (string, error) {
+	f.lastHintRequest = req
 	if f.hintErr != nil {
 		return "", f.hintErr
 	}
 	return f.hint, nil
 }
 
+func (f *fakeCoach) AnswerFollowUp(_ context.Context, _ Question, req FollowUpRequest) (string, error) {
+	f.lastFollowUp = req
+	if f.followUpErr != nil {
+		return "", f.followUpErr
+	}
+	return f.followUp, nil
+}
+
 func (f *fakeCoach) FormatQuestion(_ context.Context, _ Question, prompt string) (string, error) {
+	f.formatCalls++
 	if f.questionPromptErr != nil {
 		return "", f.questionPromptErr
 	}
@@ -98,137 +137,343 @@ func (f *fakeCoach) FormatQuestion(_ context.Context, _ Question, prompt string)
 	return f.questionPrompt, nil
 }
 
+func (f *fakeCoach) FormatModel() string {
+	return f.formatModel
+}
+
 type memoryStore struct {
-	chats    map[int64]ChatSettings
-	served   map[int64]map[string]Question
-	answered map[int64]map[string]AnsweredQuestion
+	chats    map[StateKey]ChatSettings
+	served   map[StateKey]map[string]Question
+	answered map[StateKey]map[string]AnsweredQuestion
+	attempts map[StateKey][]AnswerAttempt
+	solves   map[int64][]GroupSolve
+	sessions map[StateKey][]InterviewSession
+	lists    map[int64]map[string]QuestionList
+	prompts  map[string]FormattedPrompt
 }
 
 func newMemoryStore() *memoryStore {
 	return &memoryStore{
-		chats:    make(map[int64]ChatSettings),
-		served:   make(map[int64]map[string]Question),
-		answered: make(map[int64]map[string]AnsweredQuestion),
+		chats:    make(map[StateKey]ChatSettings),
+		served:   make(map[StateKey]map[string]Question),
+		answered: make(map[StateKey]map[string]AnsweredQuestion),
+		attempts: make(map[StateKey][]AnswerAttempt),
+		solves:   make(map[int64][]GroupSolve),
+		sessions: make(map[StateKey][]InterviewSession),
+		lists:    make(map[int64]map[string]QuestionList),
+		prompts:  make(map[string]FormattedPrompt),
 	}
 }
 
-func (m *memoryStore) GetChatSettings(_ context.Context, chatID int64) (ChatSettings, error) {
-	if item, ok := m.chats[chatID]; ok {
+func (m *memoryStore) GetChatSettings(_ context.Context, key StateKey) (ChatSettings, error) {
+	if item, ok := m.chats[key]; ok {
 		return cloneChat(item), nil
 	}
 	item := ChatSettings{
-		ChatID:       chatID,
+		ChatID:       key.ChatID,
+		UserID:       key.UserID,
 		DailyEnabled: false,
 		DailyTime:    "20:00",
 		Timezone:     "Asia/Singapore",
 	}
-	m.chats[chatID] = item
+	m.chats[key] = item
 	return cloneChat(item), nil
 }
 
-func (m *memoryStore) UpsertDailySettings(_ context.Context, chatID int64, enabled bool, hhmm, tz string) error {
-	item, _ := m.GetChatSettings(context.Background(), chatID)
+func (m *memoryStore) UpsertDailySettings(_ context.Context, key StateKey, enabled bool, hhmm, tz string) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
 	item.DailyEnabled = enabled
 	item.DailyTime = hhmm
 	item.Timezone = tz
-	m.chats[chatID] = item
+	m.chats[key] = item
 	return nil
 }
 
-func (m *memoryStore) SetCurrentQuestion(_ context.Context, chatID int64, q Question) error {
-	item, _ := m.GetChatSettings(context.Background(), chatID)
+func (m *memoryStore) SetCurrentQuestion(_ context.Context, key StateKey, q Question) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
 	qCopy := q
 	item.CurrentQuestion = &qCopy
-	m.chats[chatID] = item
+	item.HintLevel = 0
+	item.HintSlug = ""
+	item.Tutor = nil
+	m.chats[key] = item
 	return nil
 }
 
-func (m *memoryStore) ClearCurrentQuestion(_ context.Context, chatID int64) error {
-	item, _ := m.GetChatSettings(context.Background(), chatID)
+func (m *memoryStore) ClearCurrentQuestion(_ context.Context, key StateKey) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
 	item.CurrentQuestion = nil
-	m.chats[chatID] = item
+	item.HintLevel = 0
+	item.HintSlug = ""
+	item.Tutor = nil
+	m.chats[key] = item
+	return nil
+}
+
+func (m *memoryStore) SetHintLevel(_ context.Context, key StateKey, slug string, level int) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
+	item.HintLevel = level
+	item.HintSlug = slug
+	m.chats[key] = item
+	return nil
+}
+
+func (m *memoryStore) SetTutorThread(_ context.Context, key StateKey, thread TutorThread) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
+	item.Tutor = &TutorThread{Slug: thread.Slug, Turns: append([]TutorTurn(nil), thread.Turns...)}
+	m.chats[key] = item
+	return nil
+}
+
+func (m *memoryStore) SetStudyPlan(_ context.Context, key StateKey, name string) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
+	item.StudyPlan = name
+	m.chats[key] = item
+	return nil
+}
+
+func (m *memoryStore) DismissQuestion(_ context.Context, key StateKey, slug string) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
+	item.DismissedSlug = slug
+	m.chats[key] = item
 	return nil
 }
 
-func (m *memoryStore) MarkDailySent(_ context.Context, chatID int64, day string) error {
-	item, _ := m.GetChatSettings(context.Background(), chatID)
+func (m *memoryStore) MarkDailySent(_ context.Context, key StateKey, day string) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
 	item.LastDailySentOn = day
-	m.chats[chatID] = item
+	m.chats[key] = item
+	return nil
+}
+
+func (m *memoryStore) SetMockSession(_ context.Context, key StateKey, session MockSession) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
+	item.Mock = &session
+	m.chats[key] = item
+	return nil
+}
+
+func (m *memoryStore) ClearMockSession(_ context.Context, key StateKey) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
+	item.Mock = nil
+	m.chats[key] = item
+	return nil
+}
+
+func (m *memoryStore) ListMockSessions(_ context.Context) ([]ChatSettings, error) {
+	out := make([]ChatSettings, 0)
+	for _, item := range m.chats {
+		if item.Mock != nil {
+			out = append(out, cloneChat(item))
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryStore) SaveInterviewSession(_ context.Context, key StateKey, session InterviewSession) error {
+	session.Questions = append([]SessionQuestion(nil), session.Questions...)
+	for i, item := range m.sessions[key] {
+		if item.ID == session.ID {
+			m.sessions[key][i] = session
+			return nil
+		}
+	}
+	m.sessions[key] = append(m.sessions[key], session)
+	return nil
+}
+
+func (m *memoryStore) LatestInterviewSession(_ context.Context, key StateKey) (InterviewSession, error) {
+	items := m.sessions[key]
+	if len(items) == 0 {
+		return InterviewSession{}, ErrInterviewSessionNotFound
+	}
+	latest := items[len(items)-1]
+	latest.Questions = append([]SessionQuestion(nil), latest.Questions...)
+	return latest, nil
+}
+
+func (m *memoryStore) SaveQuestionList(_ context.Context, chatID int64, list QuestionList) error {
+	if m.lists[chatID] == nil {
+		m.lists[chatID] = make(map[string]QuestionList)
+	}
+	list.Questions = append([]Question(nil), list.Questions...)
+	m.lists[chatID][list.Name] = list
+	return nil
+}
+
+func (m *memoryStore) GetQuestionList(_ context.Context, chatID int64, name string) (QuestionList, error) {
+	list, ok := m.lists[chatID][name]
+	if !ok {
+		return QuestionList{}, ErrQuestionListNotFound
+	}
+	list.Questions = append([]Question(nil), list.Questions...)
+	return list, nil
+}
+
+func (m *memoryStore) ListQuestionLists(_ context.Context, chatID int64) ([]QuestionList, error) {
+	out := make([]QuestionList, 0, len(m.lists[chatID]))
+	for _, list := range m.lists[chatID] {
+		list.Questions = append([]Question(nil), list.Questions...)
+		out = append(out, list)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (m *memoryStore) SaveFormattedPrompt(_ context.Context, prompt FormattedPrompt) error {
+	m.prompts[prompt.Key] = prompt
+	return nil
+}
+
+func (m *memoryStore) GetFormattedPrompt(_ context.Context, key string) (FormattedPrompt, error) {
+	prompt, ok := m.prompts[key]
+	if !ok {
+		return FormattedPrompt{}, ErrFormattedPromptNotFound
+	}
+	return prompt, nil
+}
+
+func (m *memoryStore) MarkWeeklySummarySent(_ context.Context, key StateKey, week string) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
+	item.LastWeeklySummaryOn = week
+	m.chats[key] = item
+	return nil
+}
+
+func (m *memoryStore) RecordGroupSolve(_ context.Context, chatID int64, solve GroupSolve) error {
+	m.solves[chatID] = append(m.solves[chatID], solve)
+	return nil
+}
+
+func (m *memoryStore) ListGroupSolves(_ context.Context, chatID int64, since time.Time) ([]GroupSolve, error) {
+	out := make([]GroupSolve, 0)
+	for _, item := range m.solves[chatID] {
+		if !item.SolvedAt.Before(since) {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryStore) ListActiveGroups(ctx context.Context, since time.Time) ([]ChatSettings, error) {
+	out := make([]ChatSettings, 0)
+	for chatID, solves := range m.solves {
+		for _, item := range solves {
+			if !item.SolvedAt.Before(since) {
+				settings, _ := m.GetChatSettings(ctx, ChatKey(chatID))
+				out = append(out, settings)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryStore) SetDifficultyPreference(_ context.Context, key StateKey, difficulty string) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
+	item.Difficulty = difficulty
+	m.chats[key] = item
+	return nil
+}
+
+func (m *memoryStore) SetDailyMode(_ context.Context, key StateKey, mode string) error {
+	item, _ := m.GetChatSettings(context.Background(), key)
+	item.DailyMode = mode
+	m.chats[key] = item
 	return nil
 }
 
-func (m *memoryStore) MarkQuestionAnswered(_ context.Context, chatID int64, q Question) error {
-	if _, ok := m.answered[chatID]; !ok {
-		m.answered[chatID] = make(map[string]AnsweredQuestion)
+func (m *memoryStore) MarkQuestionAnswered(_ context.Context, key StateKey, q Question) error {
+	if _, ok := m.answered[key]; !ok {
+		m.answered[key] = make(map[string]AnsweredQuestion)
 	}
 	now := time.Now().UTC()
-	entry, exists := m.answered[chatID][q.Slug]
+	entry, exists := m.answered[key][q.Slug]
 	if !exists {
 		entry = AnsweredQuestion{
 			Question:        q,
 			FirstAnsweredAt: now,
 			LastAnsweredAt:  now,
 			Attempts:        1,
+			Review:          ReviewState{DueAt: now.Add(24 * time.Hour)},
 		}
 	} else {
 		entry.Question = q
 		entry.Attempts++
 		entry.LastAnsweredAt = now
 	}
-	m.answered[chatID][q.Slug] = entry
+	m.answered[key][q.Slug] = entry
 	return nil
 }
 
-func (m *memoryStore) DeleteAnsweredQuestion(_ context.Context, chatID int64, slug string) error {
-	if _, ok := m.answered[chatID][slug]; !ok {
+func (m *memoryStore) DeleteAnsweredQuestion(_ context.Context, key StateKey, slug string) error {
+	if _, ok := m.answered[key][slug]; !ok {
 		return ErrAnsweredQuestionNotFound
 	}
-	delete(m.answered[chatID], slug)
+	delete(m.answered[key], slug)
 	return nil
 }
 
-func (m *memoryStore) AddServedQuestion(_ context.Context, chatID int64, q Question) error {
-	if _, ok := m.served[chatID]; !ok {
-		m.served[chatID] = make(map[string]Question)
+func (m *memoryStore) RecordAnswerAttempt(_ context.Context, key StateKey, attempt AnswerAttempt) error {
+	m.attempts[key] = append(m.attempts[key], attempt)
+	return nil
+}
+
+func (m *memoryStore) ListAnswerAttempts(_ context.Context, key StateKey, slug string, limit int) ([]AnswerAttempt, error) {
+	out := make([]AnswerAttempt, 0)
+	for i := len(m.attempts[key]) - 1; i >= 0; i-- {
+		if item := m.attempts[key][i]; slug == "" || item.Slug == slug {
+			out = append(out, item)
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (m *memoryStore) AddServedQuestion(_ context.Context, key StateKey, q Question) error {
+	if _, ok := m.served[key]; !ok {
+		m.served[key] = make(map[string]Question)
 	}
-	m.served[chatID][q.Slug] = q
+	m.served[key][q.Slug] = q
 	return nil
 }
 
-func (m *memoryStore) RemoveServedQuestion(_ context.Context, chatID int64, slug string) error {
-	delete(m.served[chatID], slug)
+func (m *memoryStore) RemoveServedQuestion(_ context.Context, key StateKey, slug string) error {
+	delete(m.served[key], slug)
 	return nil
 }
 
-func (m *memoryStore) SeenQuestionSet(_ context.Context, chatID int64) (map[string]struct{}, error) {
+func (m *memoryStore) SeenQuestionSet(_ context.Context, key StateKey) (map[string]struct{}, error) {
 	seen := make(map[string]struct{})
-	for slug := range m.served[chatID] {
+	for slug := range m.served[key] {
 		seen[slug] = struct{}{}
 	}
 	return seen, nil
 }
 
-func (m *memoryStore) ResetServedQuestions(_ context.Context, chatID int64) error {
-	m.served[chatID] = make(map[string]Question)
+func (m *memoryStore) ResetServedQuestions(_ context.Context, key StateKey) error {
+	m.served[key] = make(map[string]Question)
 	return nil
 }
 
 func (m *memoryStore) ListDailyEnabledChats(_ context.Context) ([]ChatSettings, error) {
 	out := make([]ChatSettings, 0)
-	for _, item := range m.chats {
-		if item.DailyEnabled {
+	for key, item := range m.chats {
+		if item.DailyEnabled && key.UserID == 0 {
 			out = append(out, cloneChat(item))
 		}
 	}
 	return out, nil
 }
 
-func (m *memoryStore) ListAnsweredQuestions(_ context.Context, chatID int64, limit int) ([]AnsweredQuestion, error) {
+func (m *memoryStore) ListAnsweredQuestions(_ context.Context, key StateKey, limit int) ([]AnsweredQuestion, error) {
 	if limit <= 0 {
 		limit = 10
 	}
-	items := make([]AnsweredQuestion, 0, len(m.answered[chatID]))
-	for _, item := range m.answered[chatID] {
+	items := make([]AnsweredQuestion, 0, len(m.answered[key]))
+	for _, item := range m.answered[key] {
 		items = append(items, item)
 	}
 	sort.Slice(items, func(i, j int) bool {
@@ -240,20 +485,66 @@ func (m *memoryStore) ListAnsweredQuestions(_ context.Context, chatID int64, lim
 	return items, nil
 }
 
-func (m *memoryStore) GetAnsweredQuestion(_ context.Context, chatID int64, slug string) (Question, error) {
-	item, ok := m.answered[chatID][slug]
+func (m *memoryStore) GetAnsweredQuestion(_ context.Context, key StateKey, slug string) (Question, error) {
+	item, ok := m.answered[key][slug]
 	if !ok {
 		return Question{}, ErrAnsweredQuestionNotFound
 	}
 	return item.Question, nil
 }
 
+func (m *memoryStore) ListAllAnsweredQuestions(ctx context.Context, key StateKey) ([]AnsweredQuestion, error) {
+	return m.ListAnsweredQuestions(ctx, key, len(m.answered[key])+1)
+}
+
+func (m *memoryStore) ListReviewQueue(_ context.Context, key StateKey, limit int) ([]AnsweredQuestion, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	items := make([]AnsweredQuestion, 0, len(m.answered[key]))
+	for _, item := range m.answered[key] {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Review.DueAt.Before(items[j].Review.DueAt)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (m *memoryStore) GetReviewState(_ context.Context, key StateKey, slug string) (ReviewState, error) {
+	item, ok := m.answered[key][slug]
+	if !ok {
+		return ReviewState{}, ErrAnsweredQuestionNotFound
+	}
+	return item.Review, nil
+}
+
+func (m *memoryStore) UpdateReviewState(_ context.Context, key StateKey, slug string, state ReviewState) error {
+	item, ok := m.answered[key][slug]
+	if !ok {
+		return ErrAnsweredQuestionNotFound
+	}
+	item.Review = state
+	m.answered[key][slug] = item
+	return nil
+}
+
 func cloneChat(in ChatSettings) ChatSettings {
 	out := in
 	if in.CurrentQuestion != nil {
 		q := *in.CurrentQuestion
 		out.CurrentQuestion = &q
 	}
+	if in.Mock != nil {
+		mock := *in.Mock
+		out.Mock = &mock
+	}
+	if in.Tutor != nil {
+		out.Tutor = &TutorThread{Slug: in.Tutor.Slug, Turns: append([]TutorTurn(nil), in.Tutor.Turns...)}
+	}
 	return out
 }
 
@@ -306,17 +597,17 @@ func TestWebhookLCUniquenessAndGrading(t *testing.T) {
 		t.Fatalf("expected not-saved status for non-correct attempt, got: %s", messages[2])
 	}
 
-	settings, _ := store.GetChatSettings(context.Background(), chatID)
+	settings, _ := store.GetChatSettings(context.Background(), ChatKey(chatID))
 	if settings.CurrentQuestion == nil || settings.CurrentQuestion.Title != "Merge Intervals" {
 		t.Fatalf("expected current question to stay active for iterative coaching")
 	}
 
-	answered, _ := store.ListAnsweredQuestions(context.Background(), chatID, 10)
+	answered, _ := store.ListAnsweredQuestions(context.Background(), ChatKey(chatID), 10)
 	if len(answered) != 0 {
 		t.Fatalf("expected unanswered attempt to stay out of revised history")
 	}
 
-	seen, _ := store.SeenQuestionSet(context.Background(), chatID)
+	seen, _ := store.SeenQuestionSet(context.Background(), ChatKey(chatID))
 	if len(seen) != 0 {
 		t.Fatalf("expected unanswered attempt to stay out of seen history")
 	}
@@ -373,15 +664,15 @@ func TestGradingUsesCoachWhenConfigured(t *testing.T) {
 		t.Fatalf("expected correct status in evaluation output, got: %s", messages[1])
 	}
 
-	settings, _ := store.GetChatSettings(context.Background(), chatID)
+	settings, _ := store.GetChatSettings(context.Background(), ChatKey(chatID))
 	if settings.CurrentQuestion != nil {
 		t.Fatalf("expected correct answer to clear current question")
 	}
-	answered, _ := store.ListAnsweredQuestions(context.Background(), chatID, 10)
+	answered, _ := store.ListAnsweredQuestions(context.Background(), ChatKey(chatID), 10)
 	if len(answered) != 1 || answered[0].Slug != "two-sum" {
 		t.Fatalf("expected correct answer to save question in revised history")
 	}
-	seen, _ := store.SeenQuestionSet(context.Background(), chatID)
+	seen, _ := store.SeenQuestionSet(context.Background(), ChatKey(chatID))
 	if len(seen) != 1 {
 		t.Fatalf("expected correct answer to save question in seen history")
 	}
@@ -511,6 +802,66 @@ func TestHintRequestFromFreeTextUsesHintFlow(t *testing.T) {
 	}
 }
 
+func TestHintLadderRevealsOfficialHintsBeforeAI(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{
+		questions: []Question{
+			{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+			{Slug: "3sum", Title: "3Sum", Difficulty: "Medium", URL: "https://leetcode.com/problems/3sum/"},
+		},
+		details: map[string]QuestionDetail{
+			"two-sum": {Slug: "two-sum", Content: "Two Sum statement", Hints: []string{"Try brute force first.", "Use a hash map."}},
+			"3sum":    {Slug: "3sum", Content: "3Sum statement", Hints: []string{"Sort the array."}},
+		},
+	}
+	coach := &fakeCoach{hint: "Check the complement before inserting the current value."}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(169)
+	send := func(text string) string {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+		messages := tg.messages[chatID]
+		return messages[len(messages)-1]
+	}
+
+	send("/lc random")
+	for i, want := range []string{"*💡 Hint 1/4*", "*💡 Hint 2/4*"} {
+		hint := send("/hint")
+		if !strings.Contains(hint, want) || !strings.Contains(hint, "Source: LeetCode") {
+			t.Fatalf("hint %d: expected %q from LeetCode, got: %s", i+1, want, hint)
+		}
+	}
+	if hint := send("/hint"); !strings.Contains(hint, "*💡 Hint 3/4*") || !strings.Contains(hint, "Source: AI") {
+		t.Fatalf("expected the third hint to come from AI, got: %s", hint)
+	}
+	if got := coach.lastHintRequest; len(got.PreviousHints) != 2 || got.Level != 3 || got.Total != 4 {
+		t.Fatalf("expected AI hint to see both official hints, got %+v", got)
+	}
+	send("/hint")
+	if hint := send("/hint"); !strings.Contains(hint, "*💡 Hint 4/4*") {
+		t.Fatalf("expected the ladder to stop at its last rung, got: %s", hint)
+	}
+
+	send("/skip")
+	if hint := send("/hint"); !strings.Contains(hint, "*💡 Hint 1/3*") || !strings.Contains(hint, "Sort the array\\.") {
+		t.Fatalf("expected a new question to restart the ladder, got: %s", hint)
+	}
+}
+
 func TestHistoryAndReviseCommands(t *testing.T) {
 	tg := newFakeTelegramClient()
 	store := newMemoryStore()
@@ -578,7 +929,7 @@ func TestSkipDoesNotPersistSeenQuestion(t *testing.T) {
 	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc random"}})
 	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/skip"}})
 
-	seenAfterSkip, err := store.SeenQuestionSet(context.Background(), chatID)
+	seenAfterSkip, err := store.SeenQuestionSet(context.Background(), ChatKey(chatID))
 	if err != nil {
 		t.Fatalf("load seen set: %v", err)
 	}
@@ -626,7 +977,7 @@ func TestExitClearsActivePracticeMode(t *testing.T) {
 	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/exit"}})
 	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "my approach"}})
 
-	settings, err := store.GetChatSettings(context.Background(), chatID)
+	settings, err := store.GetChatSettings(context.Background(), ChatKey(chatID))
 	if err != nil {
 		t.Fatalf("load settings: %v", err)
 	}
@@ -645,11 +996,11 @@ func TestExitClearsActivePracticeMode(t *testing.T) {
 		t.Fatalf("expected no-active-question response after exit, got: %s", messages[2])
 	}
 
-	seen, _ := store.SeenQuestionSet(context.Background(), chatID)
+	seen, _ := store.SeenQuestionSet(context.Background(), ChatKey(chatID))
 	if len(seen) != 0 {
 		t.Fatalf("expected /exit flow not to persist question in seen history")
 	}
-	answered, _ := store.ListAnsweredQuestions(context.Background(), chatID, 10)
+	answered, _ := store.ListAnsweredQuestions(context.Background(), ChatKey(chatID), 10)
 	if len(answered) != 0 {
 		t.Fatalf("expected /exit flow not to persist question in revised history")
 	}
@@ -680,15 +1031,15 @@ func TestDoneSavesQuestionAndClearsCurrent(t *testing.T) {
 	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc random"}})
 	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/done"}})
 
-	settings, _ := store.GetChatSettings(context.Background(), chatID)
+	settings, _ := store.GetChatSettings(context.Background(), ChatKey(chatID))
 	if settings.CurrentQuestion != nil {
 		t.Fatalf("expected /done to clear current question")
 	}
-	answered, _ := store.ListAnsweredQuestions(context.Background(), chatID, 10)
+	answered, _ := store.ListAnsweredQuestions(context.Background(), ChatKey(chatID), 10)
 	if len(answered) != 1 || answered[0].Slug != "two-sum" {
 		t.Fatalf("expected /done to save question in revised history")
 	}
-	seen, _ := store.SeenQuestionSet(context.Background(), chatID)
+	seen, _ := store.SeenQuestionSet(context.Background(), ChatKey(chatID))
 	if len(seen) != 1 {
 		t.Fatalf("expected /done to save question in seen history")
 	}
@@ -721,11 +1072,11 @@ func TestDeleteRemovesQuestionFromRevisedAndSeen(t *testing.T) {
 	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/delete two-sum"}})
 	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/answered"}})
 
-	seen, _ := store.SeenQuestionSet(context.Background(), chatID)
+	seen, _ := store.SeenQuestionSet(context.Background(), ChatKey(chatID))
 	if len(seen) != 0 {
 		t.Fatalf("expected /delete to remove question from seen history")
 	}
-	answered, _ := store.ListAnsweredQuestions(context.Background(), chatID, 10)
+	answered, _ := store.ListAnsweredQuestions(context.Background(), ChatKey(chatID), 10)
 	if len(answered) != 0 {
 		t.Fatalf("expected /delete to remove question from revised history")
 	}
@@ -762,13 +1113,13 @@ func TestDailyOnKeepsExistingTimezone(t *testing.T) {
 	)
 
 	chatID := int64(125)
-	if err := store.UpsertDailySettings(context.Background(), chatID, false, "21:15", "America/New_York"); err != nil {
+	if err := store.UpsertDailySettings(context.Background(), ChatKey(chatID), false, "21:15", "America/New_York"); err != nil {
 		t.Fatalf("failed to configure chat settings: %v", err)
 	}
 
 	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/daily_on"}})
 
-	settings, _ := store.GetChatSettings(context.Background(), chatID)
+	settings, _ := store.GetChatSettings(context.Background(), ChatKey(chatID))
 	if settings.Timezone != "America/New_York" {
 		t.Fatalf("expected /daily_on to preserve existing timezone, got %q", settings.Timezone)
 	}
@@ -861,7 +1212,7 @@ func TestCronDailyDispatchRespectsTimeAndDedupesByDay(t *testing.T) {
 	svc.nowFn = func() time.Time { return time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC) }
 
 	chatID := int64(99)
-	if err := store.UpsertDailySettings(context.Background(), chatID, true, "20:00", "Asia/Singapore"); err != nil {
+	if err := store.UpsertDailySettings(context.Background(), ChatKey(chatID), true, "20:00", "Asia/Singapore"); err != nil {
 		t.Fatalf("failed to configure chat: %v", err)
 	}
 
@@ -947,7 +1298,7 @@ func TestCronDailyDispatchReturnsOffWhenFeatureDisabled(t *testing.T) {
 	svc.nowFn = func() time.Time { return time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC) }
 
 	chatID := int64(131)
-	if err := store.UpsertDailySettings(context.Background(), chatID, true, "20:00", "Asia/Singapore"); err != nil {
+	if err := store.UpsertDailySettings(context.Background(), ChatKey(chatID), true, "20:00", "Asia/Singapore"); err != nil {
 		t.Fatalf("failed to configure chat: %v", err)
 	}
 
@@ -996,17 +1347,22 @@ type webhookPayload struct {
 }
 
 type webhookMessage struct {
-	Chat webhookChat `json:"chat"`
-	From webhookUser `json:"from"`
-	Text string      `json:"text"`
+	Chat    webhookChat     `json:"chat"`
+	From    webhookUser     `json:"from"`
+	Text    string          `json:"text"`
+	ReplyTo *webhookMessage `json:"reply_to_message,omitempty"`
 }
 
 type webhookChat struct {
-	ID int64 `json:"id"`
+	ID   int64  `json:"id"`
+	Type string `json:"type,omitempty"`
 }
 
 type webhookUser struct {
-	Username string `json:"username"`
+	ID        int64  `json:"id,omitempty"`
+	IsBot     bool   `json:"is_bot,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	Username  string `json:"username"`
 }
 
 func callWebhook(t *testing.T, svc *Service, path string, payload webhookPayload) {
@@ -1063,3 +1419,1844 @@ func TestLCPromptsForTopicThenServesMatchingQuestion(t *testing.T) {
 		t.Fatalf("expected topic-matching question, got: %s", messages[1])
 	}
 }
+
+func TestInlineButtonsRouteThroughCommandHandler(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+	}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(160)
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc random"}})
+
+	if len(tg.keyboards[chatID]) != 1 {
+		t.Fatalf("expected question message to carry an inline keyboard")
+	}
+	var hintData string
+	for _, row := range tg.keyboards[chatID][0].InlineKeyboard {
+		for _, button := range row {
+			if strings.Contains(button.Text, "Hint") {
+				hintData = button.CallbackData
+			}
+		}
+	}
+	if hintData == "" {
+		t.Fatalf("expected a Hint button on the question keyboard: %+v", tg.keyboards[chatID][0])
+	}
+
+	message := &telegram.Message{Chat: telegram.Chat{ID: chatID}}
+	svc.HandleUpdate(context.Background(), telegram.Update{CallbackQuery: &telegram.CallbackQuery{ID: "cb-1", Message: message, Data: hintData}})
+	svc.HandleUpdate(context.Background(), telegram.Update{CallbackQuery: &telegram.CallbackQuery{ID: "cb-2", Message: message, Data: "cmd:skip"}})
+
+	messages := tg.messages[chatID]
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 outgoing messages, got %d", len(messages))
+	}
+	if !strings.Contains(messages[1], "*💡 Hint*") {
+		t.Fatalf("expected Hint button to produce a hint, got: %s", messages[1])
+	}
+	if !strings.Contains(messages[2], "Merge Intervals") {
+		t.Fatalf("expected Skip button to serve the next question, got: %s", messages[2])
+	}
+	if len(tg.callbackAnswers) != 2 || tg.callbackAnswers[0] != "cb-1" || tg.callbackAnswers[1] != "cb-2" {
+		t.Fatalf("expected every callback query to be answered, got %v", tg.callbackAnswers)
+	}
+}
+
+func TestEvaluationMessageCarriesInlineKeyboard(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+	}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(161)
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc random"}})
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "hash map"}})
+
+	keyboards := tg.keyboards[chatID]
+	if len(keyboards) != 2 {
+		t.Fatalf("expected question and evaluation keyboards, got %d", len(keyboards))
+	}
+	labels := make([]string, 0, 5)
+	for _, row := range keyboards[1].InlineKeyboard {
+		for _, button := range row {
+			labels = append(labels, button.Text)
+		}
+	}
+	joined := strings.Join(labels, ",")
+	for _, want := range []string{"Hint", "Skip", "Done", "Exit", "Revise"} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected evaluation keyboard to include %q, got %v", want, labels)
+		}
+	}
+}
+
+func TestEvaluationKeyboardAfterPassingAnswerOffersNext(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+	}}
+	coach := &fakeCoach{review: AnswerReview{Score: 9, Feedback: "Solid.", Guidance: "Keep going."}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	labels := func(keyboard telegram.InlineKeyboardMarkup) []string {
+		out := make([]string, 0, 5)
+		for _, row := range keyboard.InlineKeyboard {
+			for _, button := range row {
+				out = append(out, button.CallbackData)
+			}
+		}
+		return out
+	}
+
+	chatID := int64(182)
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc random"}})
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "hash map of complements, O(n) time"}})
+	keyboards := tg.keyboards[chatID]
+	if got := labels(keyboards[len(keyboards)-1]); !slices.Equal(got, []string{"cmd:next", "cmd:revise"}) {
+		t.Fatalf("expected Next and Revise after a passing answer, got %v", got)
+	}
+
+	// Inside a session the next question arrives on its own.
+	sessionChatID := int64(183)
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: sessionChatID}, Text: "/session start 2"}})
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: sessionChatID}, Text: "hash map of complements, O(n) time"}})
+	keyboards = tg.keyboards[sessionChatID]
+	if len(keyboards) != 3 {
+		t.Fatalf("expected question, evaluation and next session question keyboards, got %d", len(keyboards))
+	}
+	if got := labels(keyboards[1]); !slices.Equal(got, []string{"cmd:revise"}) {
+		t.Fatalf("expected only Revise after a passing session answer, got %v", got)
+	}
+}
+
+func TestLCTopicUsesTopicTagsAndAliases(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "dp-warmup", Title: "DP Warmup Without The Tag", Difficulty: "Easy", URL: "https://leetcode.com/problems/dp-warmup/", Tags: []string{"array"}},
+		{Slug: "climbing-stairs", Title: "Climbing Stairs", Difficulty: "Easy", URL: "https://leetcode.com/problems/climbing-stairs/", Tags: []string{"math", "dynamic-programming", "memoization"}},
+		{Slug: "number-of-islands", Title: "Number of Islands", Difficulty: "Medium", URL: "https://leetcode.com/problems/number-of-islands/", Tags: []string{"array", "breadth-first-search", "matrix"}},
+	}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(156)
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc dp"}})
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc bfs"}})
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc trie"}})
+
+	messages := tg.messages[chatID]
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 outgoing messages, got %d", len(messages))
+	}
+	if !strings.Contains(messages[0], "Climbing Stairs") {
+		t.Fatalf("expected dp alias to select the dynamic-programming tagged question, got: %s", messages[0])
+	}
+	if !strings.Contains(messages[1], "Number of Islands") {
+		t.Fatalf("expected bfs alias to select the breadth-first-search tagged question, got: %s", messages[1])
+	}
+	if !strings.Contains(messages[2], "No unseen questions found for that topic") {
+		t.Fatalf("expected no match for an unused tag, got: %s", messages[2])
+	}
+}
+
+func TestLCDifficultyFilter(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/", Tags: []string{"array", "hash-table"}},
+		{Slug: "course-schedule", Title: "Course Schedule", Difficulty: "Medium", URL: "https://leetcode.com/problems/course-schedule/", Tags: []string{"graph", "topological-sort"}},
+		{Slug: "word-ladder", Title: "Word Ladder", Difficulty: "Hard", URL: "https://leetcode.com/problems/word-ladder/", Tags: []string{"graph", "breadth-first-search"}},
+	}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(157)
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc medium"}})
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc hard graph"}})
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/lc easy graph"}})
+
+	messages := tg.messages[chatID]
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 outgoing messages, got %d", len(messages))
+	}
+	if !strings.Contains(messages[0], "Course Schedule") {
+		t.Fatalf("expected /lc medium to serve the Medium question, got: %s", messages[0])
+	}
+	if !strings.Contains(messages[1], "Word Ladder") {
+		t.Fatalf("expected /lc hard graph to serve the Hard graph question, got: %s", messages[1])
+	}
+	if !strings.Contains(messages[2], "No unseen Easy questions found for that topic") {
+		t.Fatalf("expected no match for easy graph, got: %s", messages[2])
+	}
+}
+
+func TestCronDailyDispatchRespectsDifficultyPreference(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "valid-parentheses", Title: "Valid Parentheses", Difficulty: "Easy", URL: "https://leetcode.com/problems/valid-parentheses/"},
+		{Slug: "word-ladder", Title: "Word Ladder", Difficulty: "Hard", URL: "https://leetcode.com/problems/word-ladder/"},
+	}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	// 2026-02-14 12:00 UTC == 20:00 SGT
+	svc.nowFn = func() time.Time { return time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC) }
+
+	chatID := int64(158)
+	if err := store.UpsertDailySettings(context.Background(), ChatKey(chatID), true, "20:00", "Asia/Singapore"); err != nil {
+		t.Fatalf("failed to configure chat: %v", err)
+	}
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/daily_difficulty hard"}})
+	if got := store.chats[ChatKey(chatID)].Difficulty; got != "Hard" {
+		t.Fatalf("expected difficulty preference to persist as Hard, got %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+	req.Header.Set("X-Cron-Secret", "cron-secret")
+	res := httptest.NewRecorder()
+	svc.CronHandler(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.Code)
+	}
+
+	messages := tg.messages[chatID]
+	if len(messages) != 2 {
+		t.Fatalf("expected confirmation and daily message, got %d", len(messages))
+	}
+	if !strings.Contains(messages[0], "Daily difficulty set to Hard") {
+		t.Fatalf("unexpected confirmation: %s", messages[0])
+	}
+	if !strings.Contains(messages[1], "Word Ladder") {
+		t.Fatalf("expected daily question to respect Hard preference, got: %s", messages[1])
+	}
+}
+
+func TestSpacedRepetitionSchedulesAndServesDueQuestions(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+	}}
+	coach := &fakeCoach{review: AnswerReview{Score: 9, Feedback: "Solid.", Guidance: "Keep going."}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc.nowFn = func() time.Time { return start }
+
+	chatID := int64(159)
+	send := func(text string) {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+	}
+
+	send("/lc random")
+	send("hash map lookup of the complement, O(n)")
+	send("/lc random")
+	send("sort by start and merge overlaps, O(n log n)")
+
+	for _, slug := range []string{"two-sum", "merge-intervals"} {
+		review := store.answered[ChatKey(chatID)][slug].Review
+		if review.Repetitions != 1 || review.IntervalDays != 1 || !review.DueAt.Equal(start.Add(24*time.Hour)) {
+			t.Fatalf("unexpected review state for %s after correct answer: %+v", slug, review)
+		}
+	}
+
+	send("/revise")
+	if last := tg.messages[chatID][len(tg.messages[chatID])-1]; !strings.Contains(last, "Nothing is due yet") {
+		t.Fatalf("expected /revise to note nothing is due, got: %s", last)
+	}
+	send("/exit")
+
+	overdue := store.answered[ChatKey(chatID)]["merge-intervals"]
+	overdue.Review.DueAt = start.Add(-time.Hour)
+	store.answered[ChatKey(chatID)]["merge-intervals"] = overdue
+	svc.nowFn = func() time.Time { return start.Add(2 * time.Hour) }
+
+	send("/due")
+	dueMsg := tg.messages[chatID][len(tg.messages[chatID])-1]
+	if !strings.Contains(dueMsg, "Merge Intervals") || strings.Contains(dueMsg, "Two Sum") {
+		t.Fatalf("expected only merge-intervals to be due, got: %s", dueMsg)
+	}
+
+	send("/revise")
+	reviseMsg := tg.messages[chatID][len(tg.messages[chatID])-1]
+	if !strings.Contains(reviseMsg, "Merge Intervals") || strings.Contains(reviseMsg, "Nothing is due") {
+		t.Fatalf("expected /revise to serve the overdue question, got: %s", reviseMsg)
+	}
+
+	coach.review = AnswerReview{Score: 4, Feedback: "Missed the sort.", Guidance: "Sort first."}
+	send("merge in input order")
+	lapsed := store.answered[ChatKey(chatID)]["merge-intervals"].Review
+	if lapsed.Repetitions != 0 || lapsed.IntervalDays != 1 {
+		t.Fatalf("expected failed revision to restart the schedule, got %+v", lapsed)
+	}
+	if lapsed.EaseFactor >= defaultEaseFactor {
+		t.Fatalf("expected failed revision to lower ease, got %v", lapsed.EaseFactor)
+	}
+}
+
+func TestDoneAdvancesRevisionSchedule(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+	}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc.nowFn = func() time.Time { return start }
+
+	chatID := int64(174)
+	send := func(text string) string {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+		messages := tg.messages[chatID]
+		return messages[len(messages)-1]
+	}
+
+	send("/lc random")
+	send("/done")
+	review := store.answered[ChatKey(chatID)]["two-sum"].Review
+	if review.Repetitions != 1 || !review.DueAt.Equal(start.Add(24*time.Hour)) {
+		t.Fatalf("expected /done to schedule the first review, got %+v", review)
+	}
+
+	now := start.Add(25 * time.Hour)
+	svc.nowFn = func() time.Time { return now }
+	if reply := send("/revise"); !strings.Contains(reply, "Two Sum") {
+		t.Fatalf("expected /revise to serve the due question, got: %s", reply)
+	}
+	send("/done")
+	review = store.answered[ChatKey(chatID)]["two-sum"].Review
+	if review.Repetitions != 2 || review.IntervalDays != 6 || !review.DueAt.Equal(now.Add(6*24*time.Hour)) {
+		t.Fatalf("expected /done on a due review to advance it like a pass, got %+v", review)
+	}
+	if reply := send("/revise"); !strings.Contains(reply, "Nothing is due yet") {
+		t.Fatalf("expected the reviewed question not to be due again, got: %s", reply)
+	}
+}
+
+func TestHintLadderRestartsOnNewGroupQuestion(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{
+		questions: []Question{
+			{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+			{Slug: "3sum", Title: "3Sum", Difficulty: "Medium", URL: "https://leetcode.com/problems/3sum/"},
+		},
+		details: map[string]QuestionDetail{
+			"two-sum": {Slug: "two-sum", Content: "Two Sum statement", Hints: []string{"Try brute force first.", "Use a hash map."}},
+			"3sum":    {Slug: "3sum", Content: "3Sum statement", Hints: []string{"Sort the array."}},
+		},
+	}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	groupID := int64(-100175)
+	group := webhookChat{ID: groupID, Type: "supergroup"}
+	alice := webhookUser{ID: 11, Username: "alice"}
+	send := func(text string) string {
+		t.Helper()
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: group, From: alice, Text: text}})
+		messages := tg.messages[groupID]
+		return messages[len(messages)-1]
+	}
+	runDaily := func(day int) string {
+		t.Helper()
+		// 12:00 UTC == 20:00 SGT
+		svc.nowFn = func() time.Time { return time.Date(2026, 2, day, 12, 0, 0, 0, time.UTC) }
+		req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+		req.Header.Set("X-Cron-Secret", "cron-secret")
+		svc.CronHandler(httptest.NewRecorder(), req)
+		messages := tg.messages[groupID]
+		return messages[len(messages)-1]
+	}
+	if err := store.UpsertDailySettings(context.Background(), ChatKey(groupID), true, "20:00", "Asia/Singapore"); err != nil {
+		t.Fatalf("failed to configure group: %v", err)
+	}
+
+	if daily := runDaily(14); !strings.Contains(daily, "Two Sum") {
+		t.Fatalf("expected the first daily to be Two Sum, got: %s", daily)
+	}
+	send("/hint")
+	if hint := send("/hint"); !strings.Contains(hint, "*💡 Hint 2/4*") {
+		t.Fatalf("expected the second hint on the group question, got: %s", hint)
+	}
+
+	if daily := runDaily(15); !strings.Contains(daily, "3Sum") {
+		t.Fatalf("expected the next daily to be 3Sum, got: %s", daily)
+	}
+	if hint := send("/hint"); !strings.Contains(hint, "*💡 Hint 1/3*") || !strings.Contains(hint, "Sort the array\\.") {
+		t.Fatalf("expected the ladder to restart on the new group question, got: %s", hint)
+	}
+}
+
+func TestCronDailyModes(t *testing.T) {
+	questions := []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+	}
+	// 2026-02-14 12:00 UTC == 20:00 SGT
+	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		mode         string
+		revisionDue  bool
+		wantMessages []string
+	}{
+		{name: "revise with due item", mode: "revise", revisionDue: true, wantMessages: []string{"Two Sum"}},
+		{name: "revise falls back to new", mode: "revise", revisionDue: false, wantMessages: []string{"Merge Intervals"}},
+		{name: "mixed sends new plus reminder", mode: "mixed", revisionDue: true, wantMessages: []string{"Merge Intervals", "Also due for revision today: Two Sum"}},
+		{name: "mixed without due item", mode: "mixed", revisionDue: false, wantMessages: []string{"Merge Intervals"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := newFakeTelegramClient()
+			store := newMemoryStore()
+			svc := NewService(
+				log.New(bytes.NewBuffer(nil), "", 0),
+				tg,
+				&fakeQuestionProvider{questions: questions},
+				nil,
+				store,
+				"webhook-secret",
+				"cron-secret",
+				"20:00",
+				"Asia/Singapore",
+				nil,
+				true,
+			)
+			svc.nowFn = func() time.Time { return now }
+
+			chatID := int64(160)
+			ctx := context.Background()
+			if err := store.UpsertDailySettings(ctx, ChatKey(chatID), true, "20:00", "Asia/Singapore"); err != nil {
+				t.Fatalf("failed to configure chat: %v", err)
+			}
+			if err := store.AddServedQuestion(ctx, ChatKey(chatID), questions[0]); err != nil {
+				t.Fatalf("failed to seed served question: %v", err)
+			}
+			if err := store.MarkQuestionAnswered(ctx, ChatKey(chatID), questions[0]); err != nil {
+				t.Fatalf("failed to seed answered question: %v", err)
+			}
+			dueAt := now.Add(24 * time.Hour)
+			if tc.revisionDue {
+				dueAt = now.Add(-time.Hour)
+			}
+			if err := store.UpdateReviewState(ctx, ChatKey(chatID), "two-sum", ReviewState{EaseFactor: 2.5, IntervalDays: 1, Repetitions: 1, DueAt: dueAt}); err != nil {
+				t.Fatalf("failed to seed review state: %v", err)
+			}
+
+			callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/daily_mode " + tc.mode}})
+			if got := store.chats[ChatKey(chatID)].DailyMode; got != tc.mode {
+				t.Fatalf("expected daily mode %q to persist, got %q", tc.mode, got)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+			req.Header.Set("X-Cron-Secret", "cron-secret")
+			res := httptest.NewRecorder()
+			svc.CronHandler(res, req)
+			if res.Code != http.StatusOK || res.Body.String() != "processed=1 sent=1" {
+				t.Fatalf("unexpected cron response: %d %q", res.Code, res.Body.String())
+			}
+
+			daily := tg.messages[chatID][1:]
+			if len(daily) != len(tc.wantMessages) {
+				t.Fatalf("expected %d daily messages, got %d: %v", len(tc.wantMessages), len(daily), daily)
+			}
+			for i, want := range tc.wantMessages {
+				if !strings.Contains(daily[i], want) {
+					t.Fatalf("daily message %d missing %q: %s", i, want, daily[i])
+				}
+			}
+
+			current := store.chats[ChatKey(chatID)].CurrentQuestion
+			if current == nil || current.Slug != questionSlugFor(tc.wantMessages[0], questions) {
+				t.Fatalf("unexpected active question after daily dispatch: %+v", current)
+			}
+		})
+	}
+}
+
+func TestGroupDailyModeRejectsRevisions(t *testing.T) {
+	questions := []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+	}
+	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		&fakeQuestionProvider{questions: questions},
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	svc.nowFn = func() time.Time { return now }
+
+	groupID := int64(-100180)
+	group := webhookChat{ID: groupID, Type: "supergroup"}
+	member := webhookUser{ID: 180, Username: "member180"}
+	for _, mode := range []string{"revise", "mixed"} {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: group, From: member, Text: "/daily_mode " + mode}})
+		reply := tg.messages[groupID][len(tg.messages[groupID])-1]
+		if !strings.Contains(reply, "Groups only support /daily_mode new") {
+			t.Fatalf("expected /daily_mode %s to be rejected in a group, got: %s", mode, reply)
+		}
+		if got := store.chats[ChatKey(groupID)].DailyMode; got != "" {
+			t.Fatalf("expected group daily mode to stay unset, got %q", got)
+		}
+	}
+
+	// A mode stored before groups were restricted still delivers a question.
+	ctx := context.Background()
+	if err := store.UpsertDailySettings(ctx, ChatKey(groupID), true, "20:00", "Asia/Singapore"); err != nil {
+		t.Fatalf("failed to configure group: %v", err)
+	}
+	if err := store.SetDailyMode(ctx, ChatKey(groupID), DailyModeRevise); err != nil {
+		t.Fatalf("failed to seed daily mode: %v", err)
+	}
+	sent := len(tg.messages[groupID])
+	req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+	req.Header.Set("X-Cron-Secret", "cron-secret")
+	res := httptest.NewRecorder()
+	svc.CronHandler(res, req)
+	if res.Code != http.StatusOK || res.Body.String() != "processed=1 sent=1" {
+		t.Fatalf("unexpected cron response: %d %q", res.Code, res.Body.String())
+	}
+	daily := tg.messages[groupID][sent:]
+	if len(daily) != 1 || !strings.Contains(daily[0], "Two Sum") {
+		t.Fatalf("expected the group to get a new question, got: %v", daily)
+	}
+}
+
+func questionSlugFor(title string, questions []Question) string {
+	for _, q := range questions {
+		if q.Title == title {
+			return q.Slug
+		}
+	}
+	return ""
+}
+
+func TestAnswerAttemptsAreLoggedWithSourceAndFeedback(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+	}}
+	coach := &fakeCoach{reviewErr: errors.New("AI unavailable")}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(161)
+	send := func(text string) {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+	}
+
+	send("/lc random")
+	send("loop")
+	coach.reviewErr = nil
+	coach.review = AnswerReview{Score: 9, Feedback: "Optimal hash map approach.", Guidance: "Mention the one-pass variant."}
+	send("Use a hash map from value to index; for each number check whether target-minus-number was seen. O(n) time.")
+
+	attempts := store.attempts[ChatKey(chatID)]
+	if len(attempts) != 2 {
+		t.Fatalf("expected both graded attempts to be logged, got %d", len(attempts))
+	}
+	if attempts[0].Source != "Heuristic" || attempts[0].Answer != "loop" || attempts[0].Slug != "two-sum" {
+		t.Fatalf("unexpected first attempt: %+v", attempts[0])
+	}
+	if attempts[1].Source != "AI" || attempts[1].Score != 9 || attempts[1].Feedback != "Optimal hash map approach." {
+		t.Fatalf("unexpected second attempt: %+v", attempts[1])
+	}
+
+	send("/attempts two-sum")
+	listing := tg.messages[chatID][len(tg.messages[chatID])-1]
+	if !strings.Contains(listing, "Score: 9/10 \\(AI\\)") || !strings.Contains(listing, "\\(Heuristic\\)") {
+		t.Fatalf("unexpected /attempts output: %s", listing)
+	}
+	if strings.Index(listing, "9/10") > strings.Index(listing, "Heuristic") {
+		t.Fatalf("expected newest attempt first: %s", listing)
+	}
+}
+
+func TestStatsCommandSummarizesHistory(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+	}}
+	coach := &fakeCoach{review: AnswerReview{Score: 6, Feedback: "Close.", Guidance: "Use a map."}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(162)
+	send := func(text string) {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+	}
+
+	send("/stats")
+	if first := tg.messages[chatID][0]; !strings.Contains(first, "Solved: *0*") || !strings.Contains(first, "No graded attempts yet") {
+		t.Fatalf("unexpected empty stats: %s", first)
+	}
+
+	send("/lc random")
+	send("brute force pairs")
+	coach.review = AnswerReview{Score: 10, Feedback: "Optimal.", Guidance: "Done."}
+	send("hash map of complements, O(n)")
+	send("/stats")
+
+	msg := tg.messages[chatID][len(tg.messages[chatID])-1]
+	for _, marker := range []string{"Solved: *1*", "• Easy: 1", "Current: *1 day*", "Average attempts per solved question: 2\\.0", "Average score: 8\\.0/10 across 2 graded attempts"} {
+		if !strings.Contains(msg, marker) {
+			t.Fatalf("expected stats to include %q: %s", marker, msg)
+		}
+	}
+}
+
+func TestGroupChatKeepsPerMemberState(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+	}}
+	coach := &fakeCoach{review: AnswerReview{Score: 9, Feedback: "Solid.", Guidance: "Keep going."}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	svc.SetBotUsername("@LeetBot")
+	// 2026-02-14 12:00 UTC == 20:00 SGT
+	svc.nowFn = func() time.Time { return time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC) }
+
+	groupID := int64(-100163)
+	group := webhookChat{ID: groupID, Type: "supergroup"}
+	alice := webhookUser{ID: 11, Username: "alice"}
+	bob := webhookUser{ID: 12, Username: "bob"}
+	carol := webhookUser{ID: 13, Username: "carol"}
+	botMessage := &webhookMessage{Chat: group, From: webhookUser{ID: 99, IsBot: true, Username: "LeetBot"}, Text: "Here is your random LeetCode question:"}
+	send := func(msg webhookMessage) {
+		t.Helper()
+		msg.Chat = group
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: msg})
+	}
+	aliceKey := StateKey{ChatID: groupID, UserID: alice.ID}
+	bobKey := StateKey{ChatID: groupID, UserID: bob.ID}
+	carolKey := StateKey{ChatID: groupID, UserID: carol.ID}
+
+	send(webhookMessage{From: alice, Text: "/lc@leetbot"})
+	send(webhookMessage{From: alice, Text: "random", ReplyTo: botMessage})
+	send(webhookMessage{From: bob, Text: "/lc@otherbot random"})
+	if got := len(tg.messages[groupID]); got != 2 {
+		t.Fatalf("expected only messages addressed to this bot to be answered, got %d messages", got)
+	}
+	send(webhookMessage{From: bob, Text: "/lc random"})
+	if store.chats[aliceKey].CurrentQuestion == nil || store.chats[bobKey].CurrentQuestion == nil {
+		t.Fatalf("expected each member to hold their own current question")
+	}
+	if store.chats[ChatKey(groupID)].CurrentQuestion != nil {
+		t.Fatalf("member questions must not be stored on the group")
+	}
+
+	send(webhookMessage{From: alice, Text: "anyone up for lunch?"})
+	if got := len(tg.messages[groupID]); got != 3 || len(store.attempts[aliceKey]) != 0 {
+		t.Fatalf("plain group chatter must be ignored, got %d messages and %d attempts", got, len(store.attempts[aliceKey]))
+	}
+
+	send(webhookMessage{From: alice, Text: "hash map of complements", ReplyTo: botMessage})
+	if _, ok := store.answered[aliceKey]["two-sum"]; !ok {
+		t.Fatalf("expected reply to the bot to be graded for alice")
+	}
+	if len(store.answered[bobKey]) != 0 || store.chats[bobKey].CurrentQuestion == nil {
+		t.Fatalf("alice's answer must not touch bob's state")
+	}
+
+	if err := store.UpsertDailySettings(context.Background(), ChatKey(groupID), true, "20:00", "Asia/Singapore"); err != nil {
+		t.Fatalf("failed to configure group: %v", err)
+	}
+	before := len(tg.messages[groupID])
+	req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+	req.Header.Set("X-Cron-Secret", "cron-secret")
+	res := httptest.NewRecorder()
+	svc.CronHandler(res, req)
+	if got := res.Body.String(); got != "processed=1 sent=1" {
+		t.Fatalf("expected the daily to be posted once to the group, got %q", got)
+	}
+	if got := len(tg.messages[groupID]) - before; got != 1 {
+		t.Fatalf("expected one daily message in the group, got %d", got)
+	}
+
+	send(webhookMessage{From: carol, Text: "@LeetBot use a hash map"})
+	attempts := store.attempts[carolKey]
+	if len(attempts) != 1 || attempts[0].Slug != "two-sum" || attempts[0].Answer != "use a hash map" {
+		t.Fatalf("expected carol's mention to answer the group daily, got %+v", attempts)
+	}
+	if _, ok := store.answered[carolKey]["two-sum"]; !ok {
+		t.Fatalf("expected the group daily to be saved to carol's history")
+	}
+
+	send(webhookMessage{From: alice, Text: "@leetbot one more try"})
+	messages := tg.messages[groupID]
+	if !strings.Contains(messages[len(messages)-1], "No active question") {
+		t.Fatalf("alice already solved the daily, got: %s", messages[len(messages)-1])
+	}
+}
+
+func TestExitDismissesInheritedGroupQuestion(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	twoSum := Question{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"}
+	provider := &fakeQuestionProvider{questions: []Question{twoSum}}
+	coach := &fakeCoach{review: AnswerReview{Score: 9, Feedback: "Solid.", Guidance: "Keep going."}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	svc.SetBotUsername("@LeetBot")
+
+	groupID := int64(-100179)
+	alice := webhookUser{ID: 178, Username: "alice"}
+	bob := webhookUser{ID: 179, Username: "bob"}
+	send := func(from webhookUser, text string) string {
+		t.Helper()
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: groupID, Type: "supergroup"}, From: from, Text: text}})
+		messages := tg.messages[groupID]
+		return messages[len(messages)-1]
+	}
+	if err := store.SetCurrentQuestion(context.Background(), ChatKey(groupID), twoSum); err != nil {
+		t.Fatalf("failed to set the group question: %v", err)
+	}
+
+	if got := send(alice, "/exit"); !strings.Contains(got, "Exited practice mode") {
+		t.Fatalf("expected /exit to leave the group question, got: %s", got)
+	}
+	if got := send(alice, "@LeetBot use a hash map"); !strings.Contains(got, "No active question") {
+		t.Fatalf("expected the dismissed group question not to be graded, got: %s", got)
+	}
+	if got := send(alice, "/exit"); !strings.Contains(got, "No active practice mode") {
+		t.Fatalf("expected nothing left to exit, got: %s", got)
+	}
+	if store.chats[ChatKey(groupID)].CurrentQuestion == nil {
+		t.Fatalf("a member's /exit must not clear the group question")
+	}
+
+	send(bob, "@LeetBot use a hash map")
+	if attempts := store.attempts[StateKey{ChatID: groupID, UserID: bob.ID}]; len(attempts) != 1 || attempts[0].Slug != "two-sum" {
+		t.Fatalf("expected other members to keep the group question, got %+v", attempts)
+	}
+}
+
+func TestGroupLeaderboardAndWeeklySummary(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+	}}
+	coach := &fakeCoach{review: AnswerReview{Score: 9, Feedback: "Solid.", Guidance: "Keep going."}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	// Saturday 2026-02-14 20:00 SGT
+	svc.nowFn = func() time.Time { return time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC) }
+
+	groupID := int64(-100213)
+	group := webhookChat{ID: groupID, Type: "group"}
+	alice := webhookUser{ID: 21, FirstName: "Alice", Username: "alice"}
+	bob := webhookUser{ID: 22, Username: "bob"}
+	botMessage := &webhookMessage{Chat: group, From: webhookUser{ID: 99, IsBot: true, Username: "LeetBot"}, Text: "Here is your random LeetCode question:"}
+	send := func(msg webhookMessage) {
+		t.Helper()
+		msg.Chat = group
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: msg})
+	}
+	lastMessage := func() string {
+		messages := tg.messages[groupID]
+		return messages[len(messages)-1]
+	}
+
+	send(webhookMessage{From: alice, Text: "/lc random"})
+	send(webhookMessage{From: alice, Text: "sort by start then merge", ReplyTo: botMessage})
+	send(webhookMessage{From: bob, Text: "/lc random"})
+	send(webhookMessage{From: bob, Text: "/done"})
+	if got := len(store.solves[groupID]); got != 2 {
+		t.Fatalf("expected graded and /done solves to be logged for the group, got %d", got)
+	}
+
+	send(webhookMessage{From: alice, Text: "/leaderboard"})
+	board := lastMessage()
+	// Medium weighs 2: alice scored 9, bob's /done counts at the pass mark of 8.
+	if !strings.Contains(board, "🥇 Alice: 1\\.8 pts") || !strings.Contains(board, "🥈 @bob: 1\\.6 pts") {
+		t.Fatalf("unexpected leaderboard: %s", board)
+	}
+
+	send(webhookMessage{From: bob, Text: "/leaderboard year"})
+	if !strings.Contains(lastMessage(), "Usage: /leaderboard") {
+		t.Fatalf("expected usage for an unknown period, got: %s", lastMessage())
+	}
+
+	callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: 21, Type: "private"}, From: alice, Text: "/leaderboard"}})
+	if got := tg.messages[21]; len(got) != 1 || !strings.Contains(got[0], "group chat") {
+		t.Fatalf("expected private chats to be pointed at groups, got %v", got)
+	}
+
+	if err := store.UpsertDailySettings(context.Background(), ChatKey(groupID), true, "20:00", "Asia/Singapore"); err != nil {
+		t.Fatalf("failed to configure group: %v", err)
+	}
+	// Monday 2026-02-16 20:00 SGT
+	svc.nowFn = func() time.Time { return time.Date(2026, 2, 16, 12, 0, 0, 0, time.UTC) }
+	send(webhookMessage{From: alice, Text: "/leaderboard week"})
+	if !strings.Contains(lastMessage(), "No solves yet") {
+		t.Fatalf("expected a fresh board on Monday, got: %s", lastMessage())
+	}
+
+	before := len(tg.messages[groupID])
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+		req.Header.Set("X-Cron-Secret", "cron-secret")
+		svc.CronHandler(httptest.NewRecorder(), req)
+	}
+	posted := tg.messages[groupID][before:]
+	summaries := 0
+	for _, msg := range posted {
+		if strings.Contains(msg, "Last week") {
+			summaries++
+			if !strings.Contains(msg, "Alice") || !strings.Contains(msg, "@bob") {
+				t.Fatalf("weekly summary missing members: %s", msg)
+			}
+		}
+	}
+	if summaries != 1 {
+		t.Fatalf("expected exactly one weekly summary, got %d in %v", summaries, posted)
+	}
+	if got := store.chats[ChatKey(groupID)].LastWeeklySummaryOn; got != "2026-W07" {
+		t.Fatalf("LastWeeklySummaryOn = %q, want 2026-W07", got)
+	}
+}
+
+func TestWeeklySummaryReachesGroupsWithoutDailyQuestions(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+	}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		&fakeCoach{},
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		false,
+	)
+	// Saturday 2026-02-14 20:00 SGT
+	svc.nowFn = func() time.Time { return time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC) }
+
+	groupID := int64(-100178)
+	alice := webhookUser{ID: 177, FirstName: "Alice", Username: "alice"}
+	for _, text := range []string{"/lc random", "/done"} {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: groupID, Type: "group"}, From: alice, Text: text}})
+	}
+
+	summaries := func() int {
+		count := 0
+		for _, msg := range tg.messages[groupID] {
+			if strings.Contains(msg, "Last week") {
+				count++
+			}
+		}
+		return count
+	}
+	tick := func(at time.Time) {
+		t.Helper()
+		svc.nowFn = func() time.Time { return at }
+		req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+		req.Header.Set("X-Cron-Secret", "cron-secret")
+		svc.CronHandler(httptest.NewRecorder(), req)
+	}
+
+	// Monday 2026-02-16 19:59 SGT, before the group's daily time.
+	tick(time.Date(2026, 2, 16, 11, 59, 0, 0, time.UTC))
+	if got := summaries(); got != 0 {
+		t.Fatalf("expected no summary before the daily time, got %d", got)
+	}
+	// 20:03 SGT: a missed 20:00 tick still gets the summary out.
+	tick(time.Date(2026, 2, 16, 12, 3, 0, 0, time.UTC))
+	tick(time.Date(2026, 2, 16, 12, 4, 0, 0, time.UTC))
+	if got := summaries(); got != 1 {
+		t.Fatalf("expected one weekly summary without /daily_on, got %d in %v", got, tg.messages[groupID])
+	}
+	if store.chats[ChatKey(groupID)].DailyEnabled {
+		t.Fatalf("the weekly summary must not enable daily questions")
+	}
+}
+
+func TestMockInterviewWarnsAndClosesOnCronTicks(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+	}}
+	coach := &fakeCoach{review: AnswerReview{Score: 5, Feedback: "Missing edge cases.", Guidance: "Handle duplicates."}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		false,
+	)
+	start := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) { svc.nowFn = func() time.Time { return start.Add(d) } }
+	tick := func() {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+		req.Header.Set("X-Cron-Secret", "cron-secret")
+		res := httptest.NewRecorder()
+		svc.CronHandler(res, req)
+		if res.Code != http.StatusOK {
+			t.Fatalf("cron returned %d", res.Code)
+		}
+	}
+	chatID := int64(5151)
+	key := ChatKey(chatID)
+	send := func(text string) {
+		t.Helper()
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+	}
+	lastMessage := func() string {
+		messages := tg.messages[chatID]
+		return messages[len(messages)-1]
+	}
+
+	at(0)
+	send("/mock 90 extreme")
+	if !strings.Contains(lastMessage(), "Usage: /mock") {
+		t.Fatalf("expected usage for bad arguments, got: %s", lastMessage())
+	}
+	send("/mock 10 medium")
+	mock := store.chats[key].Mock
+	if mock == nil || mock.Slug != "merge-intervals" || mock.Minutes != 10 || !mock.StartedAt.Equal(start) {
+		t.Fatalf("expected a 10 minute mock on merge-intervals, got %+v", mock)
+	}
+	if !strings.Contains(lastMessage(), "Mock interview started") {
+		t.Fatalf("expected mock intro, got: %s", lastMessage())
+	}
+
+	at(4 * time.Minute)
+	before := len(tg.messages[chatID])
+	tick()
+	if got := len(tg.messages[chatID]); got != before {
+		t.Fatalf("expected no warning before halfway, got %d new messages", got-before)
+	}
+
+	at(5 * time.Minute)
+	tick()
+	tick()
+	if got := len(tg.messages[chatID]) - before; got != 1 || !strings.Contains(lastMessage(), "Halfway") || !strings.Contains(lastMessage(), "5m left") {
+		t.Fatalf("expected one halfway warning, got %d: %s", got, lastMessage())
+	}
+
+	at(9*time.Minute + 30*time.Second)
+	send("sort intervals by start and merge overlaps")
+	if !strings.Contains(lastMessage(), "Elapsed: 9m 30s of 10m") {
+		t.Fatalf("expected elapsed time in the evaluation, got: %s", lastMessage())
+	}
+	tick()
+	if !strings.Contains(lastMessage(), "One minute left") || !store.chats[key].Mock.WarnedFinal {
+		t.Fatalf("expected the one minute warning, got: %s", lastMessage())
+	}
+
+	coach.review = AnswerReview{Score: 6, Feedback: "Still misses touching intervals.", Guidance: "Use <= when merging."}
+	at(10*time.Minute + 20*time.Second)
+	tick()
+	final := lastMessage()
+	if !strings.Contains(final, "Time's up") || !strings.Contains(final, "Score: *6/10*") || !strings.Contains(final, "Elapsed: 10m 20s of 10m") {
+		t.Fatalf("expected a final evaluation at timeout, got: %s", final)
+	}
+	if store.chats[key].Mock != nil || store.chats[key].CurrentQuestion != nil {
+		t.Fatalf("expected the timed-out mock and question to be cleared, got %+v", store.chats[key])
+	}
+	if _, saved := store.answered[key]["merge-intervals"]; saved {
+		t.Fatalf("a failing final answer must not be saved")
+	}
+
+	coach.review = AnswerReview{Score: 9, Feedback: "Solid.", Guidance: "Keep going."}
+	send("/mock 20")
+	send("hash map of complements")
+	if !strings.Contains(lastMessage(), "Mock interview complete") || store.chats[key].Mock != nil {
+		t.Fatalf("expected a passing answer to finish the mock, got: %s", lastMessage())
+	}
+
+	send("/mock 15")
+	send("/exit")
+	before = len(tg.messages[chatID])
+	at(30 * time.Minute)
+	tick()
+	if got := len(tg.messages[chatID]); got != before || store.chats[key].Mock != nil {
+		t.Fatalf("expected an abandoned mock to be dropped silently, got %d new messages", got-before)
+	}
+}
+
+func TestInterviewSessionsStartedInTheSameSecondKeepSeparateIDs(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+		{Slug: "lru-cache", Title: "LRU Cache", Difficulty: "Medium", URL: "https://leetcode.com/problems/lru-cache/"},
+	}}
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+	svc.nowFn = func() time.Time { return now }
+
+	chatID := int64(181)
+	for i := 0; i < 2; i++ {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: "/session start 1"}})
+	}
+
+	sessions := store.sessions[ChatKey(chatID)]
+	if len(sessions) != 2 {
+		t.Fatalf("expected both sessions to be kept, got %d: %+v", len(sessions), sessions)
+	}
+	if sessions[0].ID == sessions[1].ID {
+		t.Fatalf("expected distinct session IDs, both were %q", sessions[0].ID)
+	}
+	if sessions[0].FinishedAt.IsZero() {
+		t.Fatalf("expected the first session to be closed by the second start, got %+v", sessions[0])
+	}
+}
+
+func TestInterviewSessionWalksQuestionsAndKeepsScorecard(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+		{Slug: "lru-cache", Title: "LRU Cache", Difficulty: "Medium", URL: "https://leetcode.com/problems/lru-cache/"},
+		{Slug: "word-break", Title: "Word Break", Difficulty: "Medium", URL: "https://leetcode.com/problems/word-break/"},
+	}}
+	coach := &fakeCoach{review: AnswerReview{Score: 5, Feedback: "Incomplete.", Guidance: "State complexity."}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	start := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+	svc.nowFn = func() time.Time { return start }
+
+	chatID := int64(6161)
+	key := ChatKey(chatID)
+	send := func(text string) {
+		t.Helper()
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+	}
+	lastMessage := func() string {
+		messages := tg.messages[chatID]
+		return messages[len(messages)-1]
+	}
+	session := func() InterviewSession {
+		t.Helper()
+		got, err := store.LatestInterviewSession(context.Background(), key)
+		if err != nil {
+			t.Fatalf("load session: %v", err)
+		}
+		return got
+	}
+	currentSlug := func() string {
+		if q := store.chats[key].CurrentQuestion; q != nil {
+			return q.Slug
+		}
+		return ""
+	}
+
+	send("/session report")
+	if !strings.Contains(lastMessage(), "No interview sessions yet") {
+		t.Fatalf("expected an empty report, got: %s", lastMessage())
+	}
+
+	send("/session start 3 medium")
+	picked := session()
+	if len(picked.Questions) != 3 || picked.Current != 0 || !picked.StartedAt.Equal(start) {
+		t.Fatalf("expected three questions ready to go, got %+v", picked)
+	}
+	for _, item := range picked.Questions {
+		if item.Difficulty != "Medium" {
+			t.Fatalf("expected only Medium questions, got %+v", item)
+		}
+	}
+	if currentSlug() != picked.Questions[0].Slug || !strings.Contains(lastMessage(), "Question 1 of 3") {
+		t.Fatalf("expected the first session question to be served, got: %s", lastMessage())
+	}
+
+	send("brute force every pair")
+	coach.review = AnswerReview{Score: 9, Feedback: "Solid.", Guidance: "Keep going."}
+	send("optimal approach with the right data structure")
+	if currentSlug() != picked.Questions[1].Slug || !strings.Contains(lastMessage(), "Question 2 of 3") {
+		t.Fatalf("expected a correct answer to move to question 2, got: %s", lastMessage())
+	}
+	if got := session().Questions[0]; got.Outcome != SessionOutcomeSolved || got.BestScore != 9 || got.Attempts != 2 {
+		t.Fatalf("unexpected scorecard entry for question 1: %+v", got)
+	}
+
+	send("/skip")
+	if currentSlug() != picked.Questions[2].Slug || !strings.Contains(lastMessage(), "Skipped") || !strings.Contains(lastMessage(), "Question 3 of 3") {
+		t.Fatalf("expected /skip to move to question 3, got: %s", lastMessage())
+	}
+
+	send("/session report")
+	report := lastMessage()
+	for _, marker := range []string{"in progress", "✅ solved, best 9/10 in 2 attempts", "⏭ skipped", "▶️ in progress"} {
+		if !strings.Contains(report, marker) {
+			t.Fatalf("expected report to include %q: %s", marker, report)
+		}
+	}
+
+	svc.nowFn = func() time.Time { return start.Add(25 * time.Minute) }
+	send("/done")
+	finished := session()
+	if finished.FinishedAt.IsZero() || finished.Questions[2].Outcome != SessionOutcomeDone || currentSlug() != "" {
+		t.Fatalf("expected /done on the last question to finish the session, got %+v", finished)
+	}
+	scorecard := lastMessage()
+	if !strings.Contains(scorecard, "Interview scorecard") || !strings.Contains(scorecard, "Completed 2/3") || !strings.Contains(scorecard, "took 25m") {
+		t.Fatalf("unexpected scorecard: %s", scorecard)
+	}
+	if _, ok := store.answered[key][picked.Questions[2].Slug]; !ok {
+		t.Fatalf("expected /done to save the question as usual")
+	}
+
+	send("/session end")
+	if !strings.Contains(lastMessage(), "No interview session is running") {
+		t.Fatalf("expected nothing to end, got: %s", lastMessage())
+	}
+
+	send("/session start 2")
+	send("/exit")
+	ended := session()
+	if ended.FinishedAt.IsZero() || ended.Questions[0].Outcome != SessionOutcomeUnanswered || currentSlug() != "" {
+		t.Fatalf("expected /exit to close the session, got %+v", ended)
+	}
+	if !strings.Contains(lastMessage(), "Completed 0/") {
+		t.Fatalf("expected the scorecard after /exit, got: %s", lastMessage())
+	}
+}
+
+type fakeCodeRunner struct {
+	calls    int
+	language string
+	code     string
+	results  []ExampleResult
+	err      error
+}
+
+func (f *fakeCodeRunner) RunExamples(_ context.Context, _ Question, language, code string) ([]ExampleResult, error) {
+	f.calls++
+	f.language, f.code = language, code
+	return f.results, f.err
+}
+
+func TestFencedCodeAnswerRunsAgainstExamples(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+	}}
+	coach := &fakeCoach{review: AnswerReview{Score: 6, Feedback: "Hash map approach.", Guidance: "State complexity."}}
+	runner := &fakeCodeRunner{results: []ExampleResult{
+		{Input: "nums = [2,7,11,15], target = 9", Expected: "[0,1]", Got: "[0,1]", Passed: true},
+		{Input: "nums = [3,2,4], target = 6", Expected: "[1,2]", Got: "[1,2]", Passed: true},
+	}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	svc.SetCodeRunner(runner)
+
+	chatID := int64(167)
+	send := func(text string) {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+	}
+
+	send("/lc random")
+	send("I keep a map of complements.")
+	if runner.calls != 0 {
+		t.Fatalf("expected prose answers not to run code, got %d runs", runner.calls)
+	}
+	if msg := tg.messages[chatID][len(tg.messages[chatID])-1]; strings.Contains(msg, "Examples") {
+		t.Fatalf("expected no examples section without code: %s", msg)
+	}
+
+	send("Map of complements:\n```python\nclass Solution:\n    def twoSum(self, nums, target):\n        return [0, 1]\n```")
+	if runner.calls != 1 || runner.language != CodeLanguagePython || !strings.HasPrefix(runner.code, "class Solution:") {
+		t.Fatalf("unexpected code run: calls=%d language=%q code=%q", runner.calls, runner.language, runner.code)
+	}
+	msg := tg.messages[chatID][len(tg.messages[chatID])-1]
+	for _, marker := range []string{"Score: *6/10*", "__*Examples*__", "✅ Example 2 passed", "_Python: 2/2 examples passed_"} {
+		if !strings.Contains(msg, marker) {
+			t.Fatalf("expected evaluation to include %q: %s", marker, msg)
+		}
+	}
+
+	runner.err = ErrCodeRunUnsupported
+	send("```go\nfunc twoSum(nums []int, target int) []int { return nil }\n```")
+	if msg := tg.messages[chatID][len(tg.messages[chatID])-1]; !strings.Contains(msg, "Running code isn't supported for this question yet") {
+		t.Fatalf("expected unsupported run to be explained: %s", msg)
+	}
+}
+
+func TestQuestionDetailFeedsMessageHintsAndGrading(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{
+		questions: []Question{
+			{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		},
+		details: map[string]QuestionDetail{
+			"two-sum": {
+				Slug:           "two-sum",
+				Content:        "Given an array of integers nums and an integer target, return indices of the two numbers.",
+				Hints:          []string{"Try a second pass with a lookup table."},
+				TopicTags:      []string{"Array", "Hash Table"},
+				AcceptanceRate: 53.9,
+			},
+		},
+	}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(168)
+	send := func(text string) {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+	}
+
+	send("/lc random")
+	question := tg.messages[chatID][0]
+	for _, marker := range []string{"Acceptance 53\\.9% • Array, Hash Table", "return indices of the two numbers\\."} {
+		if !strings.Contains(question, marker) {
+			t.Fatalf("expected question message to include %q: %s", marker, question)
+		}
+	}
+
+	send("/hint")
+	if hint := tg.messages[chatID][1]; !strings.Contains(hint, "Try a second pass with a lookup table\\.") {
+		t.Fatalf("expected the first hint to be LeetCode's own: %s", hint)
+	}
+
+	send("Keep a hash map from value to index and check the complement on each step.")
+	if review := tg.messages[chatID][2]; !strings.Contains(review, "The technique fits how this problem is usually solved") {
+		t.Fatalf("expected heuristic grading to credit the tagged technique: %s", review)
+	}
+}
+
+// planCatalog returns the bundled plan's questions minus the skipped slugs,
+// after the given leading questions, as a free catalog would list them.
+func planCatalog(t *testing.T, name string, skip []string, leading ...Question) []Question {
+	t.Helper()
+	plan, ok := lookupStudyPlan(name)
+	if !ok {
+		t.Fatalf("missing bundled plan %s", name)
+	}
+	omit := toSlugSet(skip)
+	for _, q := range leading {
+		omit[q.Slug] = struct{}{}
+	}
+	out := append([]Question(nil), leading...)
+	for _, q := range plan.Questions {
+		if _, ok := omit[q.Slug]; !ok {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+func TestPlanServesStudyListInOrder(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: planCatalog(t, "grind75", nil,
+		Question{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+	)}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(170)
+	send := func(text string) string {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+		messages := tg.messages[chatID]
+		return messages[len(messages)-1]
+	}
+
+	if reply := send("/plan leetcode 500"); !strings.Contains(reply, "Unknown study plan") || !strings.Contains(reply, "grind75 - Grind 75 (75 questions)") {
+		t.Fatalf("expected unknown plan to list the bundled plans, got: %s", reply)
+	}
+	if reply := send("/plan Grind 75"); !strings.Contains(reply, "Study plan set to Grind 75 (75 questions)") {
+		t.Fatalf("expected plan to be activated, got: %s", reply)
+	}
+
+	if q := send("/lc random"); !strings.Contains(q, "Two Sum") {
+		t.Fatalf("expected the first Grind 75 question, got: %s", q)
+	}
+	send("/done")
+	if q := send("/lc random"); !strings.Contains(q, "Valid Parentheses") {
+		t.Fatalf("expected the plan to move past the solved question, got: %s", q)
+	}
+	if q := send("/skip"); !strings.Contains(q, "Merge Two Sorted Lists") {
+		t.Fatalf("expected /skip to move to the next plan question, got: %s", q)
+	}
+
+	progress := send("/plan progress")
+	for _, marker := range []string{"*📚 Grind 75*", "Solved 1/75 \\(1%\\)", "• Easy: 1/", "Next: [Valid Parentheses]"} {
+		if !strings.Contains(progress, marker) {
+			t.Fatalf("expected plan progress to include %q: %s", marker, progress)
+		}
+	}
+
+	send("/plan off")
+	if q := send("/lc random"); !strings.Contains(q, "Merge Intervals") {
+		t.Fatalf("expected random questions once the plan is off, got: %s", q)
+	}
+	if reply := send("/plan progress"); !strings.Contains(reply, "No study plan is active") {
+		t.Fatalf("expected progress to require a plan, got: %s", reply)
+	}
+}
+
+func TestPlanSkipsQuestionsMissingFromCatalog(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	paidOnly := []string{"alien-dictionary", "graph-valid-tree", "number-of-connected-components-in-an-undirected-graph", "meeting-rooms", "meeting-rooms-ii", "encode-and-decode-strings"}
+	provider := &fakeQuestionProvider{questions: planCatalog(t, "blind75", paidOnly)}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(176)
+	send := func(text string) string {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+		messages := tg.messages[chatID]
+		return messages[len(messages)-1]
+	}
+
+	send("/plan blind75")
+	plan, _ := lookupStudyPlan("blind75")
+	for _, q := range plan.Questions {
+		if q.Slug == "alien-dictionary" {
+			break
+		}
+		if err := store.AddServedQuestion(context.Background(), ChatKey(chatID), q); err != nil {
+			t.Fatalf("AddServedQuestion: %v", err)
+		}
+	}
+
+	if q := send("/lc random"); !strings.Contains(q, plan.Questions[34].Title) {
+		t.Fatalf("expected the paid-only questions to be skipped, got: %s", q)
+	}
+	progress := send("/plan progress")
+	for _, marker := range []string{"Solved 0/69", "Skipped 6 paid\\-only or unavailable question\\(s\\)\\."} {
+		if !strings.Contains(progress, marker) {
+			t.Fatalf("expected plan progress to include %q: %s", marker, progress)
+		}
+	}
+}
+
+func TestQuestionListServesGroupInOrder(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+		{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"},
+		{Slug: "word-ladder", Title: "Word Ladder", Difficulty: "Hard", URL: "https://leetcode.com/problems/word-ladder/"},
+	}}
+
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		nil,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+	// 2026-02-14 12:00 UTC == 20:00 SGT
+	svc.nowFn = func() time.Time { return time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC) }
+
+	groupID := int64(-100171)
+	group := webhookChat{ID: groupID, Type: "supergroup"}
+	alice := webhookUser{ID: 11, Username: "alice"}
+	bob := webhookUser{ID: 12, Username: "bob"}
+	send := func(from webhookUser, text string) string {
+		t.Helper()
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: group, From: from, Text: text}})
+		messages := tg.messages[groupID]
+		return messages[len(messages)-1]
+	}
+
+	if reply := send(alice, "/list create Week-1"); !strings.Contains(reply, "Created list week-1") {
+		t.Fatalf("expected list to be created, got: %s", reply)
+	}
+	if reply := send(bob, "/list create week-1"); !strings.Contains(reply, "already exists") {
+		t.Fatalf("expected lists to be shared by the group, got: %s", reply)
+	}
+	if reply := send(alice, "/list use week-1"); !strings.Contains(reply, "is empty") {
+		t.Fatalf("expected empty list to be rejected, got: %s", reply)
+	}
+	reply := send(bob, "/list add week-1 word-ladder https://leetcode.com/problems/two-sum/description/ not-a-question word-ladder")
+	for _, marker := range []string{"Added 2 question(s) to week-1 (2 total)", "Already in the list: 1", "Unknown questions skipped: not-a-question"} {
+		if !strings.Contains(reply, marker) {
+			t.Fatalf("expected /list add reply to include %q: %s", marker, reply)
+		}
+	}
+	if reply := send(alice, "/list show week-1"); !strings.Contains(reply, "1. Word Ladder (Hard) - word-ladder\n2. Two Sum (Easy) - two-sum") {
+		t.Fatalf("expected list questions in insertion order, got: %s", reply)
+	}
+
+	send(alice, "/list use week-1")
+	if got := store.chats[ChatKey(groupID)].StudyPlan; got != "list:week-1" {
+		t.Fatalf("expected the list to be set for the whole group, got %q", got)
+	}
+	if reply := send(bob, "/list show"); !strings.Contains(reply, "week-1 - 2 questions (in use)") {
+		t.Fatalf("expected the active list to be marked, got: %s", reply)
+	}
+
+	if err := store.UpsertDailySettings(context.Background(), ChatKey(groupID), true, "20:00", "Asia/Singapore"); err != nil {
+		t.Fatalf("failed to configure group: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+	req.Header.Set("X-Cron-Secret", "cron-secret")
+	svc.CronHandler(httptest.NewRecorder(), req)
+	messages := tg.messages[groupID]
+	if daily := messages[len(messages)-1]; !strings.Contains(daily, "Word Ladder") {
+		t.Fatalf("expected the daily question to come from the list, got: %s", daily)
+	}
+
+	// Later dailies walk the list even though members solve under their own
+	// keys, and the daily difficulty narrows the list instead of bypassing it.
+	send(alice, "/list add week-1 merge-intervals")
+	runDaily := func(day int, difficulty string) string {
+		t.Helper()
+		if err := store.SetDifficultyPreference(context.Background(), ChatKey(groupID), difficulty); err != nil {
+			t.Fatalf("failed to set difficulty: %v", err)
+		}
+		svc.nowFn = func() time.Time { return time.Date(2026, 2, day, 12, 0, 0, 0, time.UTC) }
+		req := httptest.NewRequest(http.MethodPost, "/cron/daily", nil)
+		req.Header.Set("X-Cron-Secret", "cron-secret")
+		svc.CronHandler(httptest.NewRecorder(), req)
+		messages := tg.messages[groupID]
+		return messages[len(messages)-1]
+	}
+	if daily := runDaily(15, "Medium"); !strings.Contains(daily, "Merge Intervals") {
+		t.Fatalf("expected day two to serve the Medium list item, got: %s", daily)
+	}
+	if daily := runDaily(16, ""); !strings.Contains(daily, "Two Sum") {
+		t.Fatalf("expected day three to serve the remaining list item, got: %s", daily)
+	}
+	if daily := runDaily(17, "Hard"); !strings.Contains(daily, "Nothing unseen in week\\-1 matches") {
+		t.Fatalf("expected a finished list to fall back to the catalog, got: %s", daily)
+	}
+	svc.nowFn = func() time.Time { return time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC) }
+
+	progress := send(bob, "/plan progress")
+	if !strings.Contains(progress, "*📚 week\\-1*") || !strings.Contains(progress, "Solved 0/3") {
+		t.Fatalf("expected members to see progress on the group list, got: %s", progress)
+	}
+
+	send(alice, "/list use off")
+	if got := store.chats[ChatKey(groupID)].StudyPlan; got != "" {
+		t.Fatalf("expected /list use off to clear the group list, got %q", got)
+	}
+	if reply := send(alice, "/list use missing"); !strings.Contains(reply, "No list named missing") {
+		t.Fatalf("expected unknown list to be reported, got: %s", reply)
+	}
+}
+
+func TestFormattedQuestionIsCachedPerModel(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+	}}
+	coach := &fakeCoach{questionPrompt: "## Task\nFind two numbers.", formatModel: "openai/gpt-4o-mini"}
+
+	newSvc := func() *Service {
+		svc := NewService(
+			log.New(bytes.NewBuffer(nil), "", 0),
+			tg,
+			provider,
+			coach,
+			store,
+			"webhook-secret",
+			"cron-secret",
+			"20:00",
+			"Asia/Singapore",
+			nil,
+			true,
+		)
+		svc.SetFormatCache(8, true)
+		return svc
+	}
+	svc := newSvc()
+
+	chatID := int64(172)
+	send := func(svc *Service, text string) string {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+		messages := tg.messages[chatID]
+		return messages[len(messages)-1]
+	}
+
+	if q := send(svc, "/lc random"); !strings.Contains(q, "Find two numbers") {
+		t.Fatalf("expected the formatted statement, got: %s", q)
+	}
+	send(svc, "/exit")
+	if q := send(svc, "/lc random"); !strings.Contains(q, "Find two numbers") || coach.formatCalls != 1 {
+		t.Fatalf("expected the repeat question from cache (calls=%d), got: %s", coach.formatCalls, q)
+	}
+	if len(store.prompts) != 1 {
+		t.Fatalf("expected the formatted statement to be persisted, got %d entries", len(store.prompts))
+	}
+
+	send(svc, "/exit")
+	if send(newSvc(), "/lc random"); coach.formatCalls != 1 {
+		t.Fatalf("expected a restarted service to reuse the persisted statement, calls=%d", coach.formatCalls)
+	}
+
+	coach.formatModel = "anthropic/claude-3-5-haiku-latest"
+	send(svc, "/exit")
+	if send(svc, "/lc random"); coach.formatCalls != 2 {
+		t.Fatalf("expected a new model to format again, calls=%d", coach.formatCalls)
+	}
+}
+
+func TestFollowUpQuestionIsAnsweredInContext(t *testing.T) {
+	tg := newFakeTelegramClient()
+	store := newMemoryStore()
+	provider := &fakeQuestionProvider{questions: []Question{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"},
+	}}
+	coach := &fakeCoach{
+		review:   AnswerReview{Score: 5, Feedback: "Explain the lookup order.", Guidance: "1. Walk an example."},
+		hint:     "Think about complements.",
+		followUp: "Check the map before inserting, so a value never pairs with itself.",
+	}
+	svc := NewService(
+		log.New(bytes.NewBuffer(nil), "", 0),
+		tg,
+		provider,
+		coach,
+		store,
+		"webhook-secret",
+		"cron-secret",
+		"20:00",
+		"Asia/Singapore",
+		nil,
+		true,
+	)
+
+	chatID := int64(173)
+	key := ChatKey(chatID)
+	send := func(text string) string {
+		callWebhook(t, svc, "/webhook/webhook-secret", webhookPayload{Message: webhookMessage{Chat: webhookChat{ID: chatID}, Text: text}})
+		messages := tg.messages[chatID]
+		return messages[len(messages)-1]
+	}
+
+	send("/lc random")
+	send("Store each number's index in a hash map.")
+	send("hint")
+	if got := coach.lastHintRequest; len(got.Transcript) != 2 || got.Statement == "" {
+		t.Fatalf("expected the hint to see the statement and the first review, got %+v", got)
+	}
+
+	reply := send("what about the duplicate case?")
+	if !strings.Contains(reply, "so a value never pairs with itself") {
+		t.Fatalf("expected the follow-up to be answered, got: %s", reply)
+	}
+	if got := coach.lastFollowUp.Transcript; len(got) != 3 || got[0].Kind != TutorTurnAnswer || got[2].Kind != TutorTurnHint {
+		t.Fatalf("expected the follow-up to see the answer, review and hint, got %+v", got)
+	}
+	if attempts := store.attempts[key]; len(attempts) != 1 {
+		t.Fatalf("expected the follow-up not to be graded, got %d attempts", len(attempts))
+	}
+
+	send("Check the map before inserting each number, O(n) time.")
+	if got := coach.lastReviewRequest.Transcript; len(got) != 5 || got[3].Kind != TutorTurnQuestion || got[4].Kind != TutorTurnReply {
+		t.Fatalf("expected the second review to see the follow-up, got %+v", got)
+	}
+
+	coach.followUpErr = errors.New("provider down")
+	if reply := send("is sorting faster?"); !strings.Contains(reply, "couldn't answer that") {
+		t.Fatalf("expected a fallback reply when the coach fails, got: %s", reply)
+	}
+	if attempts := store.attempts[key]; len(attempts) != 2 {
+		t.Fatalf("expected a failed follow-up not to be graded, got %d attempts", len(attempts))
+	}
+
+	send("/exit")
+	if thread := store.chats[key].Tutor; thread != nil {
+		t.Fatalf("expected /exit to drop the tutoring thread, got %+v", thread)
+	}
+}
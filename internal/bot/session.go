@@ -0,0 +1,274 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// startSession pre-selects count unseen questions and serves the first one.
+// A session that is still running is closed first.
+func (s *Service) startSession(ctx context.Context, key StateKey, count int, difficulty string) error {
+	picks, err := s.pickSessionQuestions(ctx, key, count, difficulty)
+	if err != nil {
+		return err
+	}
+	if len(picks) == 0 {
+		return s.tgClient.SendMessage(ctx, key.ChatID, "No unseen questions match that session. Try another difficulty or /session start 3.")
+	}
+
+	if previous, err := s.activeSession(ctx, key); err != nil {
+		return err
+	} else if previous != nil {
+		if err := s.finishSession(ctx, key, previous); err != nil {
+			return err
+		}
+	}
+
+	now := s.nowFn().UTC()
+	session := InterviewSession{
+		ID:         newSessionID(now),
+		Difficulty: difficulty,
+		Questions:  make([]SessionQuestion, 0, len(picks)),
+		StartedAt:  now,
+	}
+	for _, q := range picks {
+		session.Questions = append(session.Questions, SessionQuestion{Question: q})
+	}
+	if err := s.store.SaveInterviewSession(ctx, key, session); err != nil {
+		return err
+	}
+
+	note := fmt.Sprintf("Interview session started with %d questions.", len(picks))
+	if len(picks) < count {
+		note = fmt.Sprintf("Only %d unseen questions matched, so the session has %d.", len(picks), len(picks))
+	}
+	return s.serveSessionQuestion(ctx, key, session, note)
+}
+
+// newSessionID keys a session by its start time, with a random suffix so two
+// sessions started within the same second don't overwrite each other.
+func newSessionID(now time.Time) string {
+	return fmt.Sprintf("%s-%08x", now.Format("20060102T150405Z"), rand.Uint32())
+}
+
+func (s *Service) pickSessionQuestions(ctx context.Context, key StateKey, count int, difficulty string) ([]Question, error) {
+	all, err := s.questions.AllQuestions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen, err := s.store.SeenQuestionSet(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]Question, 0)
+	for _, q := range all {
+		if difficulty != "" && !strings.EqualFold(q.Difficulty, difficulty) {
+			continue
+		}
+		if _, exists := seen[q.Slug]; exists {
+			continue
+		}
+		candidates = append(candidates, q)
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+	return candidates, nil
+}
+
+// activeSession returns the running session, or nil when the latest one has
+// finished or none was ever started.
+func (s *Service) activeSession(ctx context.Context, key StateKey) (*InterviewSession, error) {
+	session, err := s.store.LatestInterviewSession(ctx, key)
+	if errors.Is(err, ErrInterviewSessionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !session.FinishedAt.IsZero() || session.Current >= len(session.Questions) {
+		return nil, nil
+	}
+	return &session, nil
+}
+
+// sessionQuestion returns the running session when slug is the question it
+// is waiting on. Questions served outside the session do not count toward it.
+func (s *Service) sessionQuestion(ctx context.Context, key StateKey, slug string) (*InterviewSession, error) {
+	session, err := s.activeSession(ctx, key)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	if session.Questions[session.Current].Slug != slug {
+		return nil, nil
+	}
+	return session, nil
+}
+
+// recordSessionAttempt adds a graded attempt to the session scorecard.
+func (s *Service) recordSessionAttempt(ctx context.Context, key StateKey, slug string, score int) {
+	session, err := s.sessionQuestion(ctx, key, slug)
+	if err != nil {
+		s.logger.Printf("load interview session failed for chat %s: %v", key, err)
+		return
+	}
+	if session == nil {
+		return
+	}
+	item := &session.Questions[session.Current]
+	item.Attempts++
+	item.BestScore = max(item.BestScore, score)
+	if err := s.store.SaveInterviewSession(ctx, key, *session); err != nil {
+		s.logger.Printf("save interview session failed for chat %s: %v", key, err)
+	}
+}
+
+// advanceSession records how the learner left slug and moves on to the next
+// session question, or posts the scorecard after the last one. It reports
+// false when slug is not part of a running session.
+func (s *Service) advanceSession(ctx context.Context, key StateKey, slug, outcome string) (bool, error) {
+	session, err := s.sessionQuestion(ctx, key, slug)
+	if err != nil || session == nil {
+		return false, err
+	}
+
+	session.Questions[session.Current].Outcome = outcome
+	session.Current++
+	if session.Current >= len(session.Questions) {
+		return true, s.finishSession(ctx, key, session)
+	}
+	if err := s.store.SaveInterviewSession(ctx, key, *session); err != nil {
+		return true, err
+	}
+
+	note := ""
+	switch outcome {
+	case SessionOutcomeDone:
+		note = "Marked as done."
+	case SessionOutcomeSkipped:
+		note = "Skipped."
+	}
+	return true, s.serveSessionQuestion(ctx, key, *session, note)
+}
+
+// endSession closes the running session early and posts its scorecard. It
+// reports false when no session is running.
+func (s *Service) endSession(ctx context.Context, key StateKey) (bool, error) {
+	session, err := s.activeSession(ctx, key)
+	if err != nil || session == nil {
+		return false, err
+	}
+	settings, err := s.store.GetChatSettings(ctx, key)
+	if err != nil {
+		return true, err
+	}
+	if settings.CurrentQuestion != nil && settings.CurrentQuestion.Slug == session.Questions[session.Current].Slug {
+		if err := s.store.ClearCurrentQuestion(ctx, key); err != nil {
+			return true, err
+		}
+	}
+	return true, s.finishSession(ctx, key, session)
+}
+
+// finishSession marks unreached questions, stamps the finish time and posts
+// the scorecard.
+func (s *Service) finishSession(ctx context.Context, key StateKey, session *InterviewSession) error {
+	for i := range session.Questions {
+		if session.Questions[i].Outcome == "" {
+			session.Questions[i].Outcome = SessionOutcomeUnanswered
+		}
+	}
+	session.Current = len(session.Questions)
+	session.FinishedAt = s.nowFn().UTC()
+	if err := s.store.SaveInterviewSession(ctx, key, *session); err != nil {
+		return err
+	}
+	return s.tgClient.SendRichMessage(ctx, key.ChatID, formatSessionReport(*session))
+}
+
+func (s *Service) sendSessionReport(ctx context.Context, key StateKey) error {
+	session, err := s.store.LatestInterviewSession(ctx, key)
+	if errors.Is(err, ErrInterviewSessionNotFound) {
+		return s.tgClient.SendMessage(ctx, key.ChatID, "No interview sessions yet. Start one with /session start 3 medium.")
+	}
+	if err != nil {
+		return err
+	}
+	return s.tgClient.SendRichMessage(ctx, key.ChatID, formatSessionReport(session))
+}
+
+func (s *Service) serveSessionQuestion(ctx context.Context, key StateKey, session InterviewSession, note string) error {
+	q := session.Questions[session.Current].Question
+	if err := s.store.SetCurrentQuestion(ctx, key, q); err != nil {
+		return err
+	}
+
+	note = strings.TrimSpace(note + fmt.Sprintf(" Question %d of %d.", session.Current+1, len(session.Questions)))
+	msg := s.questionMessage(ctx, "Interview session question:", note, q)
+	return s.sendQuestionMessage(ctx, key, msg)
+}
+
+func formatSessionReport(session InterviewSession) string {
+	title := "*📋 Interview scorecard*"
+	if session.FinishedAt.IsZero() {
+		title = "*📋 Interview session in progress*"
+	}
+	difficulty := session.Difficulty
+	if difficulty == "" {
+		difficulty = "Any difficulty"
+	}
+	summary := fmt.Sprintf("%d questions • %s • started %s UTC", len(session.Questions), difficulty, session.StartedAt.UTC().Format("2006-01-02 15:04"))
+	if !session.FinishedAt.IsZero() {
+		summary += " • took " + formatMockDuration(session.FinishedAt.Sub(session.StartedAt))
+	}
+	lines := []string{title, escapeMarkdownV2(summary), ""}
+
+	solved, graded, total := 0, 0, 0
+	for i, item := range session.Questions {
+		status := "⬜ unanswered"
+		switch {
+		case item.Outcome == SessionOutcomeSolved:
+			status = "✅ solved"
+			solved++
+		case item.Outcome == SessionOutcomeDone:
+			status = "☑️ marked done"
+			solved++
+		case item.Outcome == SessionOutcomeSkipped:
+			status = "⏭ skipped"
+		case item.Outcome == "" && i == session.Current:
+			status = "▶️ in progress"
+		}
+		if item.Attempts > 0 {
+			status += fmt.Sprintf(", best %d/10 in %d attempt%s", item.BestScore, item.Attempts, plural(item.Attempts))
+			graded++
+			total += item.BestScore
+		}
+		line := fmt.Sprintf("%d. %s (%s): %s", i+1, item.Title, item.Difficulty, status)
+		lines = append(lines, escapeMarkdownV2(line))
+	}
+
+	result := fmt.Sprintf("Completed %d/%d", solved, len(session.Questions))
+	if graded > 0 {
+		result += fmt.Sprintf(" • average best score %.1f/10", float64(total)/float64(graded))
+	}
+	lines = append(lines, "", "*"+escapeMarkdownV2(result)+"*")
+	if session.FinishedAt.IsZero() {
+		lines = append(lines, escapeMarkdownV2("Answer the current question to continue, or send /session end to finish now."))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
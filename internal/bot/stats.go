@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type chatStats struct {
+	Solved        int
+	ByDifficulty  map[string]int
+	CurrentStreak int
+	LongestStreak int
+	// AvgAttempts is graded attempts per solved question; questions closed
+	// with /done and never graded count as one attempt.
+	AvgAttempts float64
+	AvgScore    float64
+	Graded      int
+}
+
+var statsDifficulties = []string{"Easy", "Medium", "Hard"}
+
+func (s *Service) sendStats(ctx context.Context, key StateKey) error {
+	settings, err := s.chatSettings(ctx, key)
+	if err != nil {
+		return err
+	}
+	answered, err := s.store.ListAllAnsweredQuestions(ctx, key)
+	if err != nil {
+		return err
+	}
+	attempts, err := s.store.ListAnswerAttempts(ctx, key, "", 0)
+	if err != nil {
+		return err
+	}
+
+	loc := s.resolveLocation(settings.Timezone)
+	stats := computeStats(answered, attempts, s.nowFn().In(loc))
+	return s.tgClient.SendRichMessage(ctx, key.ChatID, formatStatsMessage(stats))
+}
+
+// computeStats derives chat statistics. Streak days come from answered
+// timestamps plus passing graded attempts, evaluated in now's location.
+func computeStats(answered []AnsweredQuestion, attempts []AnswerAttempt, now time.Time) chatStats {
+	loc := now.Location()
+	stats := chatStats{
+		Solved:       len(answered),
+		ByDifficulty: make(map[string]int, len(statsDifficulties)),
+	}
+
+	solvedDays := make(map[string]struct{})
+	for _, item := range answered {
+		stats.ByDifficulty[normalizeDifficultyLabel(item.Difficulty)]++
+		for _, ts := range []time.Time{item.FirstAnsweredAt, item.LastAnsweredAt} {
+			if !ts.IsZero() {
+				solvedDays[ts.In(loc).Format("2006-01-02")] = struct{}{}
+			}
+		}
+	}
+
+	gradedBySlug := make(map[string]int)
+	scoreTotal := 0
+	for _, attempt := range attempts {
+		gradedBySlug[attempt.Slug]++
+		scoreTotal += attempt.Score
+		if attempt.Score >= correctAnswerScoreThreshold && !attempt.CreatedAt.IsZero() {
+			solvedDays[attempt.CreatedAt.In(loc).Format("2006-01-02")] = struct{}{}
+		}
+	}
+	stats.Graded = len(attempts)
+	if stats.Graded > 0 {
+		stats.AvgScore = float64(scoreTotal) / float64(stats.Graded)
+	}
+
+	if stats.Solved > 0 {
+		total := 0
+		for _, item := range answered {
+			total += max(gradedBySlug[item.Slug], 1)
+		}
+		stats.AvgAttempts = float64(total) / float64(stats.Solved)
+	}
+
+	stats.CurrentStreak, stats.LongestStreak = streaks(solvedDays, now)
+	return stats
+}
+
+// streaks returns the current run of consecutive solve days (alive if it
+// ends today or yesterday) and the longest run overall.
+func streaks(days map[string]struct{}, now time.Time) (current, longest int) {
+	if len(days) == 0 {
+		return 0, 0
+	}
+
+	has := func(t time.Time) bool {
+		_, ok := days[t.Format("2006-01-02")]
+		return ok
+	}
+
+	for day := range days {
+		start, err := time.ParseInLocation("2006-01-02", day, now.Location())
+		if err != nil {
+			continue
+		}
+		if has(start.AddDate(0, 0, -1)) {
+			continue
+		}
+		run := 1
+		for has(start.AddDate(0, 0, run)) {
+			run++
+		}
+		longest = max(longest, run)
+	}
+
+	cursor := now
+	if !has(cursor) {
+		cursor = cursor.AddDate(0, 0, -1)
+	}
+	for has(cursor) {
+		current++
+		cursor = cursor.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+func normalizeDifficultyLabel(difficulty string) string {
+	for _, d := range statsDifficulties {
+		if strings.EqualFold(difficulty, d) {
+			return d
+		}
+	}
+	return "Other"
+}
+
+func formatStatsMessage(stats chatStats) string {
+	lines := []string{
+		"*📊 Your Stats*",
+		"",
+		fmt.Sprintf("Solved: *%d*", stats.Solved),
+	}
+	for _, d := range statsDifficulties {
+		lines = append(lines, fmt.Sprintf("• %s: %d", d, stats.ByDifficulty[d]))
+	}
+	if other := stats.ByDifficulty["Other"]; other > 0 {
+		lines = append(lines, fmt.Sprintf("• Other: %d", other))
+	}
+
+	lines = append(lines,
+		"",
+		"__*Streaks*__",
+		"",
+		fmt.Sprintf("Current: *%s*", escapeMarkdownV2(pluralDays(stats.CurrentStreak))),
+		fmt.Sprintf("Longest: *%s*", escapeMarkdownV2(pluralDays(stats.LongestStreak))),
+		"",
+		"__*Attempts*__",
+		"",
+	)
+
+	if stats.Solved > 0 {
+		lines = append(lines, escapeMarkdownV2(fmt.Sprintf("Average attempts per solved question: %.1f", stats.AvgAttempts)))
+	}
+	if stats.Graded > 0 {
+		lines = append(lines, escapeMarkdownV2(fmt.Sprintf("Average score: %.1f/10 across %d graded attempts", stats.AvgScore, stats.Graded)))
+	} else {
+		lines = append(lines, escapeMarkdownV2("No graded attempts yet. Send your approach after /lc to get scored."))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
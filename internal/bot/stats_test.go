@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeStatsCountsStreaksAndAverages(t *testing.T) {
+	loc := time.FixedZone("SGT", 8*3600)
+	day := func(d, hour int) time.Time { return time.Date(2026, 3, d, hour, 0, 0, 0, loc) }
+	now := day(10, 21)
+
+	answered := []AnsweredQuestion{
+		{Question: Question{Slug: "two-sum", Difficulty: "Easy"}, FirstAnsweredAt: day(1, 9), LastAnsweredAt: day(1, 9)},
+		{Question: Question{Slug: "valid-parentheses", Difficulty: "Easy"}, FirstAnsweredAt: day(2, 9), LastAnsweredAt: day(2, 9)},
+		{Question: Question{Slug: "merge-intervals", Difficulty: "Medium"}, FirstAnsweredAt: day(3, 9), LastAnsweredAt: day(8, 9)},
+		{Question: Question{Slug: "word-ladder", Difficulty: "Hard"}, FirstAnsweredAt: day(9, 9), LastAnsweredAt: day(9, 9)},
+	}
+	attempts := []AnswerAttempt{
+		{Slug: "merge-intervals", Score: 4, CreatedAt: day(3, 8)},
+		{Slug: "merge-intervals", Score: 9, CreatedAt: day(3, 9)},
+		{Slug: "word-ladder", Score: 3, CreatedAt: day(9, 8)},
+		{Slug: "word-ladder", Score: 6, CreatedAt: day(9, 8)},
+		{Slug: "word-ladder", Score: 8, CreatedAt: day(10, 1)},
+	}
+
+	stats := computeStats(answered, attempts, now)
+
+	if stats.Solved != 4 || stats.ByDifficulty["Easy"] != 2 || stats.ByDifficulty["Medium"] != 1 || stats.ByDifficulty["Hard"] != 1 {
+		t.Fatalf("unexpected solved counts: %+v", stats)
+	}
+	// Solve days: 1, 2, 3, 8, 9, 10.
+	if stats.LongestStreak != 3 || stats.CurrentStreak != 3 {
+		t.Fatalf("streaks = (current %d, longest %d), want (3, 3)", stats.CurrentStreak, stats.LongestStreak)
+	}
+	// two-sum 1 + valid-parentheses 1 + merge-intervals 2 + word-ladder 3.
+	if stats.AvgAttempts != 7.0/4.0 {
+		t.Fatalf("AvgAttempts = %v, want 1.75", stats.AvgAttempts)
+	}
+	if stats.Graded != 5 || stats.AvgScore != 6.0 {
+		t.Fatalf("AvgScore = %v over %d, want 6.0 over 5", stats.AvgScore, stats.Graded)
+	}
+}
+
+func TestStreakSurvivesUntilEndOfNextDay(t *testing.T) {
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	days := map[string]struct{}{"2026-03-08": {}, "2026-03-09": {}}
+	if current, longest := streaks(days, now); current != 2 || longest != 2 {
+		t.Fatalf("streaks = (%d, %d), want (2, 2) before today's solve", current, longest)
+	}
+
+	days = map[string]struct{}{"2026-03-07": {}, "2026-03-08": {}}
+	if current, _ := streaks(days, now); current != 0 {
+		t.Fatalf("current streak = %d, want 0 after a missed day", current)
+	}
+}
+
+func TestFormatStatsMessageEscapesMarkdown(t *testing.T) {
+	msg := formatStatsMessage(chatStats{
+		Solved:        3,
+		ByDifficulty:  map[string]int{"Easy": 2, "Hard": 1},
+		CurrentStreak: 1,
+		LongestStreak: 4,
+		AvgAttempts:   1.5,
+		AvgScore:      7.25,
+		Graded:        4,
+	})
+
+	for _, marker := range []string{"*📊 Your Stats*", "Solved: *3*", "• Medium: 0", "Current: *1 day*", "Longest: *4 days*", "1\\.5", "7\\.2/10"} {
+		if !strings.Contains(msg, marker) {
+			t.Fatalf("expected stats message to include %q: %s", marker, msg)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package bot
+
+import "strings"
+
+// topicAliases maps shorthand a learner might type onto LeetCode topic tag
+// slugs. Keys are normalized with normalizeTopic.
+var topicAliases = map[string]string{
+	"dp":             "dynamic-programming",
+	"memo":           "memoization",
+	"bfs":            "breadth-first-search",
+	"dfs":            "depth-first-search",
+	"graphs":         "graph",
+	"trees":          "tree",
+	"bst":            "binary-search-tree",
+	"ll":             "linked-list",
+	"linked-lists":   "linked-list",
+	"heap":           "heap-priority-queue",
+	"heaps":          "heap-priority-queue",
+	"priority-queue": "heap-priority-queue",
+	"pq":             "heap-priority-queue",
+	"hash":           "hash-table",
+	"hashmap":        "hash-table",
+	"hash-map":       "hash-table",
+	"two-pointer":    "two-pointers",
+	"pointers":       "two-pointers",
+	"window":         "sliding-window",
+	"bits":           "bit-manipulation",
+	"bit":            "bit-manipulation",
+	"dsu":            "union-find",
+	"disjoint-set":   "union-find",
+	"topo":           "topological-sort",
+	"topological":    "topological-sort",
+	"prefix":         "prefix-sum",
+	"arrays":         "array",
+	"strings":        "string",
+	"stacks":         "stack",
+	"monotonic":      "monotonic-stack",
+	"sort":           "sorting",
+	"bs":             "binary-search",
+	"tries":          "trie",
+	"prefix-tree":    "trie",
+}
+
+// resolveTopicTag turns free-form topic input ("dp", "Sliding Window") into
+// a LeetCode topic tag slug.
+func resolveTopicTag(topic string) string {
+	normalized := normalizeTopic(topic)
+	if tag, ok := topicAliases[normalized]; ok {
+		return tag
+	}
+	return normalized
+}
+
+func normalizeTopic(topic string) string {
+	topic = strings.ToLower(strings.TrimSpace(topic))
+	topic = strings.NewReplacer("_", " ", "/", " ", "&", " ").Replace(topic)
+	return strings.Join(strings.Fields(topic), "-")
+}
+
+func questionHasTag(q Question, tag string) bool {
+	for _, t := range q.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// questionMatchesTopic matches q's topic tags, falling back to its title and
+// slug when it has none, such as when the tag lookup failed or missed it.
+func questionMatchesTopic(q Question, topic, tag string) bool {
+	if len(q.Tags) > 0 {
+		return questionHasTag(q, tag)
+	}
+	haystack := strings.ToLower(q.Title + " " + q.Slug + " " + q.Difficulty)
+	return strings.Contains(haystack, topic)
+}
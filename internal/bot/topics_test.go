@@ -0,0 +1,40 @@
+package bot
+
+import "testing"
+
+func TestResolveTopicTag(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "dp", want: "dynamic-programming"},
+		{input: "BFS", want: "breadth-first-search"},
+		{input: "Sliding Window", want: "sliding-window"},
+		{input: "two_pointers", want: "two-pointers"},
+		{input: "graph", want: "graph"},
+		{input: "  hash map ", want: "hash-table"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			if got := resolveTopicTag(tc.input); got != tc.want {
+				t.Fatalf("resolveTopicTag(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQuestionMatchesTopicFallsBackToTitle(t *testing.T) {
+	tagged := Question{Slug: "two-sum", Title: "Two Sum", Tags: []string{"array", "hash-table"}}
+	untagged := Question{Slug: "sliding-window-maximum", Title: "Sliding Window Maximum"}
+
+	if !questionMatchesTopic(tagged, "hash map", "hash-table") {
+		t.Fatalf("expected a tagged question to match its tag")
+	}
+	if questionMatchesTopic(tagged, "two", "two") {
+		t.Fatalf("expected a tagged question to match on tags only")
+	}
+	if !questionMatchesTopic(untagged, "sliding window", "sliding-window") {
+		t.Fatalf("expected a question without tags to match on its title")
+	}
+}
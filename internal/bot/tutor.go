@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxTutorTurns bounds the stored transcript; older turns are dropped.
+	maxTutorTurns = 12
+	// maxTutorTurnRunes truncates each stored turn.
+	maxTutorTurnRunes = 1500
+	// maxFollowUpRunes is the longest message treated as a follow-up question
+	// rather than an answer.
+	maxFollowUpRunes = 300
+)
+
+// tutorTranscript returns the transcript for the current question. A thread
+// left over from another question, such as a member's own thread under a
+// newer group question, is ignored.
+func tutorTranscript(settings ChatSettings) []TutorTurn {
+	if settings.Tutor == nil || settings.CurrentQuestion == nil || settings.Tutor.Slug != settings.CurrentQuestion.Slug {
+		return nil
+	}
+	return settings.Tutor.Turns
+}
+
+// recordTutorTurns appends turns to the current question's transcript,
+// keeping the last maxTutorTurns. Failures are logged: losing context must
+// not fail the reply.
+func (s *Service) recordTutorTurns(ctx context.Context, key StateKey, settings ChatSettings, turns ...TutorTurn) {
+	if settings.CurrentQuestion == nil {
+		return
+	}
+	history := tutorTranscript(settings)
+	all := make([]TutorTurn, 0, len(history)+len(turns))
+	all = append(all, history...)
+	now := s.nowFn().UTC()
+	for _, turn := range turns {
+		turn.Text = truncateRunes(strings.TrimSpace(turn.Text), maxTutorTurnRunes)
+		if turn.At.IsZero() {
+			turn.At = now
+		}
+		all = append(all, turn)
+	}
+	if len(all) > maxTutorTurns {
+		all = all[len(all)-maxTutorTurns:]
+	}
+
+	thread := TutorThread{Slug: settings.CurrentQuestion.Slug, Turns: all}
+	if err := s.store.SetTutorThread(ctx, key, thread); err != nil {
+		s.logger.Printf("save tutor thread failed for chat %s slug=%s: %v", key, thread.Slug, err)
+	}
+}
+
+// followUpOpeners are the first words of a message that asks rather than
+// proposes.
+var followUpOpeners = []string{
+	"what", "why", "how", "when", "where", "which", "who",
+	"can", "could", "should", "would", "is", "are", "does", "do", "did", "will",
+}
+
+// approachWords, like complexity notation and the topic keywords, mark a
+// message as a tentative answer, such as "Is it a hash map, O(n)?", even
+// when it ends in a question mark.
+var approachWords = []string{"binary search", "greedy", "backtracking", "trie", "time complexity", "space complexity"}
+
+// followUpQuestion reports a short question about the problem, such as
+// "what about duplicates?", as opposed to an answer to be graded, and returns
+// it without the marker. A leading "?" always asks; otherwise the message
+// must open like a question, end in "?" and name no technique or complexity.
+func followUpQuestion(text string) (string, bool) {
+	trimmed := strings.TrimSpace(text)
+	if strings.Contains(trimmed, "```") || utf8.RuneCountInString(trimmed) > maxFollowUpRunes {
+		return "", false
+	}
+	if rest, ok := strings.CutPrefix(trimmed, "?"); ok {
+		rest = strings.TrimSpace(rest)
+		return rest, rest != ""
+	}
+	if !strings.HasSuffix(trimmed, "?") {
+		return "", false
+	}
+
+	lower := strings.ToLower(trimmed)
+	opener, _, _ := strings.Cut(lower, " ")
+	if !slices.Contains(followUpOpeners, strings.Trim(opener, ",?")) || strings.Contains(lower, "o(") {
+		return "", false
+	}
+	for _, word := range approachWords {
+		if containsWord(lower, word) {
+			return "", false
+		}
+	}
+	for _, keywords := range topicKeywords {
+		for _, keyword := range keywords {
+			if containsWord(lower, keyword) {
+				return "", false
+			}
+		}
+	}
+	return trimmed, true
+}
+
+// answerFollowUp replies to a follow-up question in the context of the
+// tutoring thread. It reports false when the coach cannot hold a
+// conversation, so the message is graded as an answer instead.
+func (s *Service) answerFollowUp(ctx context.Context, key StateKey, settings ChatSettings, message string) (bool, error) {
+	tutor, ok := s.coach.(Tutor)
+	if !ok {
+		return false, nil
+	}
+
+	q := *settings.CurrentQuestion
+	reply, err := tutor.AnswerFollowUp(ctx, q, FollowUpRequest{
+		Message:    message,
+		Statement:  s.questionStatement(ctx, q),
+		Transcript: tutorTranscript(settings),
+	})
+	reply = strings.TrimSpace(reply)
+	if err != nil || reply == "" {
+		if err != nil {
+			s.logger.Printf("AI follow-up failed for chat %s slug=%s: %v", key, q.Slug, err)
+		}
+		return true, s.tgClient.SendMessage(ctx, key.ChatID, "I couldn't answer that right now. Send \"hint\" for a nudge, or send your approach to get it graded.")
+	}
+
+	s.recordTutorTurns(ctx, key, settings,
+		TutorTurn{Kind: TutorTurnQuestion, Text: message},
+		TutorTurn{Kind: TutorTurnReply, Text: reply},
+	)
+	return true, s.tgClient.SendRichMessage(ctx, key.ChatID, formatTutorReplyMessage(q, reply))
+}
+
+// reviewTurnText summarises an evaluation for the transcript.
+func reviewTurnText(score int, source, feedback string) string {
+	return fmt.Sprintf("Score %d/10 (%s). %s", score, source, feedback)
+}
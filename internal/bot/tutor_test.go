@@ -0,0 +1,31 @@
+package bot
+
+import "testing"
+
+func TestFollowUpQuestion(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{text: "what about the duplicate case?", want: "what about the duplicate case?", ok: true},
+		{text: "Why?", want: "Why?", ok: true},
+		{text: "? two pointers or a hash map", want: "two pointers or a hash map", ok: true},
+		{text: "Two pointers, O(n)?", ok: false},
+		{text: "Is a hash map enough here?", ok: false},
+		{text: "Can I do it with binary search?", ok: false},
+		{text: "what is the time complexity of sorting?", ok: false},
+		{text: "what about duplicates", ok: false},
+		{text: "?", ok: false},
+		{text: "how about this?\n```python\nreturn []\n```", ok: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.text, func(t *testing.T) {
+			got, ok := followUpQuestion(tc.text)
+			if ok != tc.ok || got != tc.want {
+				t.Fatalf("followUpQuestion(%q) = %q, %v; want %q, %v", tc.text, got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
@@ -3,28 +3,183 @@ package bot
 import (
 	"context"
 	"errors"
+	"strconv"
 	"time"
+
+	"telegram-leetcode-bot/internal/telegram"
 )
 
 var ErrNoUnseenQuestions = errors.New("no unseen questions available")
 var ErrAnsweredQuestionNotFound = errors.New("answered question not found")
+var ErrInterviewSessionNotFound = errors.New("interview session not found")
+var ErrQuestionListNotFound = errors.New("question list not found")
+var ErrFormattedPromptNotFound = errors.New("formatted prompt not found")
+var ErrCodeRunUnsupported = errors.New("code execution is not supported for this question")
 
 type Question struct {
 	Slug       string
 	Title      string
 	Difficulty string
 	URL        string
+	// Tags holds LeetCode topic tag slugs (e.g. "dynamic-programming").
+	// It is populated by the question provider and not persisted.
+	Tags []string
+}
+
+// QuestionDetail is a question's page: plain-text statement and official
+// hints plus the metadata shown alongside it.
+type QuestionDetail struct {
+	Slug        string
+	Content     string
+	Constraints []string
+	// Snippets maps a language slug ("python3", "golang") to starter code.
+	Snippets         map[string]string
+	ExampleTestcases string
+	Hints            []string
+	SimilarQuestions []Question
+	// TopicTags are display names such as "Hash Table".
+	TopicTags      []string
+	AcceptanceRate float64
+	Likes          int
+	Dislikes       int
+}
+
+// StateKey identifies whose learning state a store call reads or writes.
+// UserID is zero for private chats and for a group as a whole; each member of
+// a group chat gets their own key so their questions and history stay apart.
+type StateKey struct {
+	ChatID int64
+	UserID int64
+}
+
+// ChatKey returns the chat-wide key for chatID.
+func ChatKey(chatID int64) StateKey {
+	return StateKey{ChatID: chatID}
+}
+
+func (k StateKey) String() string {
+	id := strconv.FormatInt(k.ChatID, 10)
+	if k.UserID == 0 {
+		return id
+	}
+	return id + ":" + strconv.FormatInt(k.UserID, 10)
 }
 
 type ChatSettings struct {
-	ChatID          int64
+	ChatID int64
+	// UserID is set on a group member's own settings and zero otherwise.
+	UserID          int64
 	DailyEnabled    bool
 	DailyTime       string
 	Timezone        string
 	CurrentQuestion *Question
 	LastDailySentOn string
+	// Difficulty is the preferred difficulty for daily questions ("Easy",
+	// "Medium", "Hard"); empty means any.
+	Difficulty string
+	// DailyMode selects what the daily dispatch sends; empty means DailyModeNew.
+	DailyMode string
+	// LastWeeklySummaryOn is the ISO week ("2026-W07") of the last posted
+	// group leaderboard summary.
+	LastWeeklySummaryOn string
+	// Mock is the running timed mock interview, if any.
+	Mock *MockSession
+	// HintLevel counts hints shown for the question HintSlug; setting or
+	// clearing the current question resets it. A level for another slug,
+	// such as a member's level from an earlier group question, is stale.
+	HintLevel int
+	HintSlug  string
+	// Tutor is the tutoring transcript for CurrentQuestion. It is reset with
+	// HintLevel, and ignored when its Slug is another question's.
+	Tutor *TutorThread
+	// StudyPlan names the active /plan study list; empty means questions come
+	// from the whole catalog.
+	StudyPlan string
+	// DismissedSlug is the group question a member left with /exit, which
+	// they no longer inherit.
+	DismissedSlug string
+}
+
+// Key returns the state key the settings were loaded for.
+func (c ChatSettings) Key() StateKey {
+	return StateKey{ChatID: c.ChatID, UserID: c.UserID}
+}
+
+// QuestionList is a custom question list shared by a chat's members and
+// served in order like a study plan.
+type QuestionList struct {
+	Name      string
+	Questions []Question
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// InterviewSession is a multi-question interview loop started with /session.
+// Current indexes the question being worked on; FinishedAt is zero while the
+// session is running.
+type InterviewSession struct {
+	ID         string
+	Difficulty string
+	Questions  []SessionQuestion
+	Current    int
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// SessionQuestion is one interview question and how it went. Outcome is
+// empty until the learner moves past the question.
+type SessionQuestion struct {
+	Question
+	Outcome   string
+	BestScore int
+	Attempts  int
+}
+
+const (
+	SessionOutcomeSolved     = "solved"
+	SessionOutcomeDone       = "done"
+	SessionOutcomeSkipped    = "skipped"
+	SessionOutcomeUnanswered = "unanswered"
+)
+
+// TutorThread is the bounded transcript of the learner's answers, the
+// coach's reviews and hints, and follow-up questions for the question Slug.
+type TutorThread struct {
+	Slug  string
+	Turns []TutorTurn
+}
+
+// TutorTurn is one transcript entry; Kind is one of the TutorTurn* values.
+type TutorTurn struct {
+	Kind string
+	Text string
+	At   time.Time
+}
+
+const (
+	TutorTurnAnswer   = "answer"
+	TutorTurnReview   = "review"
+	TutorTurnHint     = "hint"
+	TutorTurnQuestion = "question"
+	TutorTurnReply    = "reply"
+)
+
+// MockSession is a timed mock interview on the current question. The warning
+// flags stop later cron ticks from repeating a reminder.
+type MockSession struct {
+	Slug          string
+	StartedAt     time.Time
+	Minutes       int
+	WarnedHalfway bool
+	WarnedFinal   bool
 }
 
+const (
+	DailyModeNew    = "new"
+	DailyModeRevise = "revise"
+	DailyModeMixed  = "mixed"
+)
+
 type AnswerReview struct {
 	Score    int
 	Feedback string
@@ -36,22 +191,91 @@ type AnsweredQuestion struct {
 	FirstAnsweredAt time.Time
 	LastAnsweredAt  time.Time
 	Attempts        int
+	Review          ReviewState
+}
+
+// ReviewState is the SM-2 spaced-repetition schedule for an answered
+// question. Stores report DueAt even for questions that were never graded.
+type ReviewState struct {
+	EaseFactor   float64
+	IntervalDays int
+	Repetitions  int
+	DueAt        time.Time
+}
+
+// AnswerAttempt is one graded submission for a question, kept whether or not
+// it passed. Source is "AI" or "Heuristic".
+type AnswerAttempt struct {
+	Slug      string
+	Answer    string
+	Score     int
+	Source    string
+	Feedback  string
+	CreatedAt time.Time
+}
+
+// GroupSolve records a group member completing a question, feeding the
+// group leaderboard. Score is zero when the question was closed with /done.
+type GroupSolve struct {
+	UserID     int64
+	Name       string
+	Slug       string
+	Difficulty string
+	Score      int
+	SolvedAt   time.Time
 }
 
 type TelegramSender interface {
 	SendMessage(ctx context.Context, chatID int64, text string) error
 	SendRichMessage(ctx context.Context, chatID int64, text string) error
+	SendRichMessageWithKeyboard(ctx context.Context, chatID int64, text string, keyboard telegram.InlineKeyboardMarkup) error
+	AnswerCallbackQuery(ctx context.Context, callbackQueryID, text string) error
 }
 
 type QuestionProvider interface {
 	RandomQuestion(ctx context.Context, seen map[string]struct{}) (Question, error)
 	AllQuestions(ctx context.Context) ([]Question, error)
-	QuestionPrompt(ctx context.Context, slug string) (string, error)
+	// QuestionDetail returns the statement and page metadata for slug.
+	QuestionDetail(ctx context.Context, slug string) (QuestionDetail, error)
 }
 
 type Coach interface {
-	ReviewAnswer(ctx context.Context, question Question, answer string) (AnswerReview, error)
-	GenerateHint(ctx context.Context, question Question, learnerContext string) (string, error)
+	ReviewAnswer(ctx context.Context, question Question, req ReviewRequest) (AnswerReview, error)
+	GenerateHint(ctx context.Context, question Question, req HintRequest) (string, error)
+}
+
+// ReviewRequest is what the coach knows when grading an answer. Statement
+// is the problem text, empty when it could not be fetched, and Transcript
+// is the tutoring thread so far, oldest first.
+type ReviewRequest struct {
+	Answer     string
+	Statement  string
+	Transcript []TutorTurn
+}
+
+// HintRequest is what the coach knows when asked for a hint. PreviousHints
+// are LeetCode's official hints the learner has already seen, so the AI hint
+// should go further than them. Total is zero when the ladder is open-ended.
+type HintRequest struct {
+	LearnerContext string
+	PreviousHints  []string
+	Level          int
+	Total          int
+	Statement      string
+	Transcript     []TutorTurn
+}
+
+// Tutor is an optional Coach extension that answers a learner's question
+// about the current problem in the context of the tutoring thread, instead
+// of grading it as an answer.
+type Tutor interface {
+	AnswerFollowUp(ctx context.Context, question Question, req FollowUpRequest) (string, error)
+}
+
+type FollowUpRequest struct {
+	Message    string
+	Statement  string
+	Transcript []TutorTurn
 }
 
 // QuestionFormatter is an optional extension that allows AI-driven
@@ -60,19 +284,98 @@ type QuestionFormatter interface {
 	FormatQuestion(ctx context.Context, question Question, prompt string) (string, error)
 }
 
+// FormattedPrompt is an AI-formatted question statement. Key is derived
+// from the slug, formatting model and raw statement.
+type FormattedPrompt struct {
+	Key       string
+	Slug      string
+	Model     string
+	Text      string
+	CreatedAt time.Time
+}
+
+// FormattedPromptStore is an optional StateStore extension that keeps
+// formatted statements across restarts.
+type FormattedPromptStore interface {
+	SaveFormattedPrompt(ctx context.Context, prompt FormattedPrompt) error
+	// GetFormattedPrompt returns the prompt or ErrFormattedPromptNotFound.
+	GetFormattedPrompt(ctx context.Context, key string) (FormattedPrompt, error)
+}
+
+// FormatModelNamer is an optional QuestionFormatter extension naming the
+// model behind FormatQuestion, so cached output is not reused after the
+// model changes.
+type FormatModelNamer interface {
+	FormatModel() string
+}
+
+// CodeRunner is an optional dependency that runs code from an answer against
+// the question's example cases. language is "python" or "go".
+type CodeRunner interface {
+	RunExamples(ctx context.Context, question Question, language, code string) ([]ExampleResult, error)
+}
+
+// ExampleResult is how an answer's code fared on one example. Err is set when
+// the code failed instead of returning, e.g. an exception or time limit.
+type ExampleResult struct {
+	Input    string
+	Expected string
+	Got      string
+	Passed   bool
+	Err      string
+}
+
 type StateStore interface {
-	GetChatSettings(ctx context.Context, chatID int64) (ChatSettings, error)
-	UpsertDailySettings(ctx context.Context, chatID int64, enabled bool, hhmm, tz string) error
-	SetCurrentQuestion(ctx context.Context, chatID int64, q Question) error
-	ClearCurrentQuestion(ctx context.Context, chatID int64) error
-	MarkDailySent(ctx context.Context, chatID int64, day string) error
-	MarkQuestionAnswered(ctx context.Context, chatID int64, q Question) error
-	DeleteAnsweredQuestion(ctx context.Context, chatID int64, slug string) error
-	AddServedQuestion(ctx context.Context, chatID int64, q Question) error
-	RemoveServedQuestion(ctx context.Context, chatID int64, slug string) error
-	SeenQuestionSet(ctx context.Context, chatID int64) (map[string]struct{}, error)
-	ResetServedQuestions(ctx context.Context, chatID int64) error
+	GetChatSettings(ctx context.Context, key StateKey) (ChatSettings, error)
+	UpsertDailySettings(ctx context.Context, key StateKey, enabled bool, hhmm, tz string) error
+	SetCurrentQuestion(ctx context.Context, key StateKey, q Question) error
+	ClearCurrentQuestion(ctx context.Context, key StateKey) error
+	SetHintLevel(ctx context.Context, key StateKey, slug string, level int) error
+	// SetTutorThread replaces the tutoring transcript; setting or clearing
+	// the current question drops it.
+	SetTutorThread(ctx context.Context, key StateKey, thread TutorThread) error
+	SetMockSession(ctx context.Context, key StateKey, session MockSession) error
+	ClearMockSession(ctx context.Context, key StateKey) error
+	// ListMockSessions returns every chat or member with a running mock.
+	ListMockSessions(ctx context.Context) ([]ChatSettings, error)
+	MarkDailySent(ctx context.Context, key StateKey, day string) error
+	MarkWeeklySummarySent(ctx context.Context, key StateKey, week string) error
+	SetDifficultyPreference(ctx context.Context, key StateKey, difficulty string) error
+	SetDailyMode(ctx context.Context, key StateKey, mode string) error
+	SetStudyPlan(ctx context.Context, key StateKey, name string) error
+	// DismissQuestion stops a group member inheriting the group question slug.
+	DismissQuestion(ctx context.Context, key StateKey, slug string) error
+	MarkQuestionAnswered(ctx context.Context, key StateKey, q Question) error
+	DeleteAnsweredQuestion(ctx context.Context, key StateKey, slug string) error
+	RecordAnswerAttempt(ctx context.Context, key StateKey, attempt AnswerAttempt) error
+	// ListAnswerAttempts returns attempts newest first, optionally for one
+	// slug. A non-positive limit returns every attempt.
+	ListAnswerAttempts(ctx context.Context, key StateKey, slug string, limit int) ([]AnswerAttempt, error)
+	RecordGroupSolve(ctx context.Context, chatID int64, solve GroupSolve) error
+	// ListGroupSolves returns a group's solves at or after since, oldest first.
+	ListGroupSolves(ctx context.Context, chatID int64, since time.Time) ([]GroupSolve, error)
+	// ListActiveGroups returns the groups with a solve at or after since.
+	ListActiveGroups(ctx context.Context, since time.Time) ([]ChatSettings, error)
+	// SaveInterviewSession creates or replaces the session with the same ID.
+	SaveInterviewSession(ctx context.Context, key StateKey, session InterviewSession) error
+	// LatestInterviewSession returns the most recently started session or
+	// ErrInterviewSessionNotFound.
+	LatestInterviewSession(ctx context.Context, key StateKey) (InterviewSession, error)
+	// SaveQuestionList creates or replaces the chat's list with the same name.
+	SaveQuestionList(ctx context.Context, chatID int64, list QuestionList) error
+	// GetQuestionList returns the named list or ErrQuestionListNotFound.
+	GetQuestionList(ctx context.Context, chatID int64, name string) (QuestionList, error)
+	// ListQuestionLists returns the chat's lists ordered by name.
+	ListQuestionLists(ctx context.Context, chatID int64) ([]QuestionList, error)
+	AddServedQuestion(ctx context.Context, key StateKey, q Question) error
+	RemoveServedQuestion(ctx context.Context, key StateKey, slug string) error
+	SeenQuestionSet(ctx context.Context, key StateKey) (map[string]struct{}, error)
+	ResetServedQuestions(ctx context.Context, key StateKey) error
 	ListDailyEnabledChats(ctx context.Context) ([]ChatSettings, error)
-	ListAnsweredQuestions(ctx context.Context, chatID int64, limit int) ([]AnsweredQuestion, error)
-	GetAnsweredQuestion(ctx context.Context, chatID int64, slug string) (Question, error)
+	ListAnsweredQuestions(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error)
+	ListAllAnsweredQuestions(ctx context.Context, key StateKey) ([]AnsweredQuestion, error)
+	GetAnsweredQuestion(ctx context.Context, key StateKey, slug string) (Question, error)
+	ListReviewQueue(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error)
+	GetReviewState(ctx context.Context, key StateKey, slug string) (ReviewState, error)
+	UpdateReviewState(ctx context.Context, key StateKey, slug string, state ReviewState) error
 }
@@ -8,25 +8,82 @@ import (
 	"time"
 )
 
+const (
+	StorageBackendFirestore = "firestore"
+	StorageBackendMemory    = "memory"
+	StorageBackendBolt      = "bolt"
+)
+
+const (
+	UpdateModeWebhook = "webhook"
+	UpdateModePolling = "polling"
+)
+
+const (
+	QuestionSourceLive    = "live"
+	QuestionSourceCatalog = "catalog"
+)
+
+const (
+	AIProviderOpenAI           = "openai"
+	AIProviderAnthropic        = "anthropic"
+	AIProviderOllama           = "ollama"
+	AIProviderOpenAICompatible = "openai-compatible"
+)
+
+// AIRoute is an AI_FALLBACKS entry with its provider's key and base URL
+// resolved.
+type AIRoute struct {
+	Provider string
+	Model    string
+	APIKey   string
+	BaseURL  string
+}
+
 type Config struct {
 	Port             string
 	TelegramBotToken string
+	BotUsername      string
 	WebhookSecret    string
 	CronSecret       string
 	FirestoreProject string
+	StorageBackend   string
+	BoltDBPath       string
 	AllowedUsernames []string
 
+	UpdateMode     string
+	PollTimeoutSec int
+
 	DefaultDailyTime       string
 	DefaultTimezone        string
 	DailySchedulingEnabled bool
 	AutoSetWebhook         bool
 	BotBaseURL             string
 	QuestionCacheSec       int
+	QuestionSource         string
+	QuestionCatalogPath    string
 
 	AIEnabled    bool
-	OpenAIAPIKey string
-	OpenAIModel  string
+	AIProvider   string
+	AIAPIKey     string
+	AIModel      string
+	AIBaseURL    string
 	AITimeoutSec int
+	// AIReviewModel, AIHintModel and AIFormatModel override AIModel per
+	// coach call; empty means AIModel.
+	AIReviewModel string
+	AIHintModel   string
+	AIFormatModel string
+	AIFallbacks   []AIRoute
+	AIMaxAttempts int
+	// AIFormatCacheSize bounds the in-process cache of formatted statements;
+	// AIFormatCachePersist also keeps them in the state store.
+	AIFormatCacheSize    int
+	AIFormatCachePersist bool
+
+	CodeExecutionEnabled    bool
+	CodeExecutionTimeoutSec int
+	CodeExecutionMemoryMB   int
 }
 
 func Load() (Config, error) {
@@ -51,37 +108,119 @@ func Load() (Config, error) {
 	if err != nil {
 		return Config{}, err
 	}
+	aiMaxAttempts, err := parseIntEnv("AI_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return Config{}, err
+	}
+	aiFormatCacheSize, err := parseIntEnv("AI_FORMAT_CACHE_SIZE", 256)
+	if err != nil {
+		return Config{}, err
+	}
+	aiFormatCachePersist, err := parseBoolEnv("AI_FORMAT_CACHE_PERSIST", false)
+	if err != nil {
+		return Config{}, err
+	}
+	pollTimeoutSec, err := parseIntEnv("POLL_TIMEOUT_SEC", 30)
+	if err != nil {
+		return Config{}, err
+	}
+	codeExecutionEnabled, err := parseBoolEnv("CODE_EXECUTION_ENABLED", false)
+	if err != nil {
+		return Config{}, err
+	}
+	codeExecutionTimeoutSec, err := parseIntEnv("CODE_EXECUTION_TIMEOUT_SEC", 5)
+	if err != nil {
+		return Config{}, err
+	}
+	codeExecutionMemoryMB, err := parseIntEnv("CODE_EXECUTION_MEMORY_MB", 256)
+	if err != nil {
+		return Config{}, err
+	}
 
 	cfg := Config{
 		Port:                   getEnv("PORT", "8080"),
 		TelegramBotToken:       os.Getenv("TELEGRAM_BOT_TOKEN"),
+		BotUsername:            normalizeUsername(os.Getenv("TELEGRAM_BOT_USERNAME")),
 		WebhookSecret:          os.Getenv("WEBHOOK_SECRET"),
 		CronSecret:             os.Getenv("CRON_SECRET"),
 		FirestoreProject:       os.Getenv("FIRESTORE_PROJECT_ID"),
+		StorageBackend:         strings.ToLower(getEnv("STORAGE_BACKEND", StorageBackendFirestore)),
+		BoltDBPath:             getEnv("BOLT_DB_PATH", "data/bot.db"),
 		AllowedUsernames:       parseAllowedUsernamesEnv("ALLOWED_TELEGRAM_USERNAMES"),
+		UpdateMode:             strings.ToLower(getEnv("UPDATE_MODE", UpdateModeWebhook)),
+		PollTimeoutSec:         pollTimeoutSec,
 		DefaultDailyTime:       getEnv("DAILY_DEFAULT_TIME", "20:00"),
 		DefaultTimezone:        getEnv("DAILY_TIMEZONE", "Asia/Singapore"),
 		DailySchedulingEnabled: dailySchedulingEnabled,
 		AutoSetWebhook:         autoSetWebhook,
 		BotBaseURL:             getEnv("BOT_BASE_URL", ""),
 		QuestionCacheSec:       cacheSec,
+		QuestionSource:         strings.ToLower(getEnv("QUESTION_SOURCE", QuestionSourceLive)),
+		QuestionCatalogPath:    getEnv("QUESTION_CATALOG_PATH", ""),
 		AIEnabled:              aiEnabled,
-		OpenAIAPIKey:           strings.TrimSpace(os.Getenv("OPENAI_API_KEY")),
-		OpenAIModel:            getEnv("OPENAI_MODEL", "gpt-4o-mini"),
+		AIProvider:             strings.ToLower(getEnv("AI_PROVIDER", AIProviderOpenAI)),
 		AITimeoutSec:           aiTimeoutSec,
+		AIReviewModel:          getEnv("AI_REVIEW_MODEL", ""),
+		AIHintModel:            getEnv("AI_HINT_MODEL", ""),
+		AIFormatModel:          getEnv("AI_FORMAT_MODEL", ""),
+		AIMaxAttempts:          aiMaxAttempts,
+		AIFormatCacheSize:      aiFormatCacheSize,
+		AIFormatCachePersist:   aiFormatCachePersist,
+
+		CodeExecutionEnabled:    codeExecutionEnabled,
+		CodeExecutionTimeoutSec: codeExecutionTimeoutSec,
+		CodeExecutionMemoryMB:   codeExecutionMemoryMB,
 	}
 
 	if cfg.TelegramBotToken == "" {
 		return Config{}, fmt.Errorf("TELEGRAM_BOT_TOKEN is required")
 	}
-	if cfg.WebhookSecret == "" {
-		return Config{}, fmt.Errorf("WEBHOOK_SECRET is required")
+	switch cfg.UpdateMode {
+	case UpdateModeWebhook:
+		if cfg.WebhookSecret == "" {
+			return Config{}, fmt.Errorf("WEBHOOK_SECRET is required")
+		}
+	case UpdateModePolling:
+	default:
+		return Config{}, fmt.Errorf("invalid UPDATE_MODE %q: expected webhook or polling", cfg.UpdateMode)
 	}
 	if cfg.CronSecret == "" {
 		return Config{}, fmt.Errorf("CRON_SECRET is required")
 	}
-	if cfg.FirestoreProject == "" {
-		return Config{}, fmt.Errorf("FIRESTORE_PROJECT_ID is required")
+	switch cfg.StorageBackend {
+	case StorageBackendFirestore:
+		if cfg.FirestoreProject == "" {
+			return Config{}, fmt.Errorf("FIRESTORE_PROJECT_ID is required")
+		}
+	case StorageBackendBolt:
+		if cfg.BoltDBPath == "" {
+			return Config{}, fmt.Errorf("BOLT_DB_PATH is required when STORAGE_BACKEND=bolt")
+		}
+	case StorageBackendMemory:
+	default:
+		return Config{}, fmt.Errorf("invalid STORAGE_BACKEND %q: expected firestore, bolt, or memory", cfg.StorageBackend)
+	}
+	switch cfg.QuestionSource {
+	case QuestionSourceLive:
+	case QuestionSourceCatalog:
+		if cfg.QuestionCatalogPath == "" {
+			return Config{}, fmt.Errorf("QUESTION_CATALOG_PATH is required when QUESTION_SOURCE=catalog")
+		}
+	default:
+		return Config{}, fmt.Errorf("invalid QUESTION_SOURCE %q: expected live or catalog", cfg.QuestionSource)
+	}
+	keyEnv, defaultModel, defaultBaseURL, ok := aiProviderDefaults(cfg.AIProvider)
+	if !ok {
+		return Config{}, fmt.Errorf("invalid AI_PROVIDER %q: expected openai, anthropic, ollama, or openai-compatible", cfg.AIProvider)
+	}
+	cfg.AIAPIKey = firstNonEmpty(getEnv("AI_API_KEY", ""), getEnv(keyEnv, ""))
+	cfg.AIModel = firstNonEmpty(getEnv("AI_MODEL", ""), defaultModel)
+	cfg.AIBaseURL = firstNonEmpty(getEnv("AI_BASE_URL", ""), defaultBaseURL)
+	if cfg.AIEnabled && cfg.AIProvider == AIProviderOpenAICompatible && (cfg.AIBaseURL == "" || cfg.AIModel == "") {
+		return Config{}, fmt.Errorf("AI_BASE_URL and AI_MODEL are required when AI_PROVIDER=openai-compatible")
+	}
+	if cfg.AIFallbacks, err = parseAIFallbacks(getEnv("AI_FALLBACKS", ""), cfg); err != nil {
+		return Config{}, err
 	}
 	if _, err := time.Parse("15:04", cfg.DefaultDailyTime); err != nil {
 		return Config{}, fmt.Errorf("invalid DAILY_DEFAULT_TIME %q: expected HH:MM", cfg.DefaultDailyTime)
@@ -90,6 +229,67 @@ func Load() (Config, error) {
 	return cfg, nil
 }
 
+// aiProviderDefaults returns the provider's own API key variable and the
+// model and base URL used when AI_MODEL or AI_BASE_URL are unset.
+func aiProviderDefaults(provider string) (keyEnv, model, baseURL string, ok bool) {
+	switch provider {
+	case AIProviderOpenAI:
+		return "OPENAI_API_KEY", getEnv("OPENAI_MODEL", "gpt-4o-mini"), "", true
+	case AIProviderAnthropic:
+		return "ANTHROPIC_API_KEY", "claude-3-5-haiku-latest", "", true
+	case AIProviderOllama:
+		return "", "llama3.1", "http://localhost:11434/v1", true
+	case AIProviderOpenAICompatible:
+		return "", "", "", true
+	default:
+		return "", "", "", false
+	}
+}
+
+// parseAIFallbacks reads a comma-separated list of [provider:]model entries.
+// Entries without a known provider prefix use the primary provider, so model
+// tags such as llama3.1:8b stay intact. A fallback provider other than the
+// primary one uses its own API key variable and public endpoint.
+func parseAIFallbacks(raw string, cfg Config) ([]AIRoute, error) {
+	var routes []AIRoute
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route := AIRoute{Provider: cfg.AIProvider, Model: entry}
+		if provider, model, found := strings.Cut(entry, ":"); found {
+			if _, _, _, ok := aiProviderDefaults(strings.ToLower(provider)); ok {
+				route.Provider, route.Model = strings.ToLower(provider), strings.TrimSpace(model)
+			}
+		}
+		if route.Model == "" {
+			return nil, fmt.Errorf("invalid AI_FALLBACKS entry %q: model is empty", entry)
+		}
+
+		if route.Provider == cfg.AIProvider {
+			route.APIKey, route.BaseURL = cfg.AIAPIKey, cfg.AIBaseURL
+		} else {
+			keyEnv, _, baseURL, _ := aiProviderDefaults(route.Provider)
+			route.APIKey, route.BaseURL = getEnv(keyEnv, ""), baseURL
+			if route.Provider == AIProviderOpenAICompatible {
+				return nil, fmt.Errorf("invalid AI_FALLBACKS entry %q: openai-compatible is only supported as AI_PROVIDER", entry)
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 func getEnv(key, fallback string) string {
 	if v, ok := os.LookupEnv(key); ok {
 		return strings.TrimSpace(v)
@@ -0,0 +1,83 @@
+package leetcode
+
+import (
+	"container/list"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// detailCache is a size-bounded LRU of question details whose entries also
+// expire after ttl. Details are copied in and out, so callers may modify
+// what they get.
+type detailCache struct {
+	capacity int
+	ttl      time.Duration
+	nowFn    func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type detailEntry struct {
+	slug     string
+	detail   QuestionDetail
+	storedAt time.Time
+}
+
+func newDetailCache(capacity int, ttl time.Duration) *detailCache {
+	return &detailCache{
+		capacity: capacity,
+		ttl:      ttl,
+		nowFn:    time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *detailCache) get(slug string) (QuestionDetail, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[slug]
+	if !ok {
+		return QuestionDetail{}, false
+	}
+	entry := elem.Value.(*detailEntry)
+	if c.nowFn().Sub(entry.storedAt) >= c.ttl {
+		c.order.Remove(elem)
+		delete(c.entries, slug)
+		return QuestionDetail{}, false
+	}
+	c.order.MoveToFront(elem)
+	return cloneDetail(entry.detail), true
+}
+
+func (c *detailCache) put(slug string, detail QuestionDetail) {
+	detail = cloneDetail(detail)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[slug]; ok {
+		elem.Value = &detailEntry{slug: slug, detail: detail, storedAt: c.nowFn()}
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[slug] = c.order.PushFront(&detailEntry{slug: slug, detail: detail, storedAt: c.nowFn()})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*detailEntry).slug)
+	}
+}
+
+func cloneDetail(detail QuestionDetail) QuestionDetail {
+	detail.Constraints = slices.Clone(detail.Constraints)
+	detail.Snippets = maps.Clone(detail.Snippets)
+	detail.Hints = slices.Clone(detail.Hints)
+	detail.SimilarQuestions = slices.Clone(detail.SimilarQuestions)
+	detail.TopicTags = slices.Clone(detail.TopicTags)
+	return detail
+}
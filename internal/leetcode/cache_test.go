@@ -0,0 +1,85 @@
+package leetcode
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetailCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newDetailCache(2, time.Hour)
+	cache.put("two-sum", QuestionDetail{Slug: "two-sum"})
+	cache.put("three-sum", QuestionDetail{Slug: "three-sum"})
+	if _, ok := cache.get("two-sum"); !ok {
+		t.Fatalf("expected two-sum to be cached")
+	}
+	cache.put("four-sum", QuestionDetail{Slug: "four-sum"})
+
+	if _, ok := cache.get("three-sum"); ok {
+		t.Fatalf("expected least recently used three-sum to be evicted")
+	}
+	for _, slug := range []string{"two-sum", "four-sum"} {
+		if detail, ok := cache.get(slug); !ok || detail.Slug != slug {
+			t.Fatalf("get(%q) = %+v, %v; want cached", slug, detail, ok)
+		}
+	}
+}
+
+func TestDetailCacheCopiesDetails(t *testing.T) {
+	cache := newDetailCache(2, time.Hour)
+	detail := QuestionDetail{
+		Slug:      "two-sum",
+		Hints:     []string{"Use a map."},
+		Snippets:  map[string]string{"python3": "class Solution:"},
+		TopicTags: []string{"Array"},
+	}
+	cache.put("two-sum", detail)
+	detail.Hints[0] = "changed after put"
+
+	got, _ := cache.get("two-sum")
+	got.Snippets["python3"] = "changed after get"
+	got.TopicTags[0] = "changed after get"
+
+	again, _ := cache.get("two-sum")
+	if again.Hints[0] != "Use a map." || again.Snippets["python3"] != "class Solution:" || again.TopicTags[0] != "Array" {
+		t.Fatalf("cached detail was modified through a caller's copy: %+v", again)
+	}
+}
+
+func TestDetailCacheExpiresEntries(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache := newDetailCache(4, time.Minute)
+	cache.nowFn = func() time.Time { return now }
+	cache.put("two-sum", QuestionDetail{Slug: "two-sum"})
+
+	now = now.Add(59 * time.Second)
+	if _, ok := cache.get("two-sum"); !ok {
+		t.Fatalf("expected entry to be fresh before the TTL")
+	}
+	now = now.Add(time.Second)
+	if _, ok := cache.get("two-sum"); ok {
+		t.Fatalf("expected entry to expire after the TTL")
+	}
+	if cache.order.Len() != 0 {
+		t.Fatalf("expected expired entry to be dropped, %d left", cache.order.Len())
+	}
+}
+
+func TestParseDetailFields(t *testing.T) {
+	content := "Given an array...\n\nConstraints:\n\n- 2 <= nums.length <= 10^4\n- -10^9 <= nums[i] <= 10^9\n\nFollow-up: Can you do better?"
+	constraints := parseConstraints(content)
+	if len(constraints) != 2 || constraints[0] != "2 <= nums.length <= 10^4" {
+		t.Fatalf("parseConstraints() = %q", constraints)
+	}
+
+	similar := parseSimilarQuestions(`[{"title": "3Sum", "titleSlug": "3sum", "difficulty": "Medium", "translatedTitle": null}]`)
+	if len(similar) != 1 || similar[0] != (SimilarQuestion{Slug: "3sum", Title: "3Sum", Difficulty: "Medium"}) {
+		t.Fatalf("parseSimilarQuestions() = %+v", similar)
+	}
+
+	if rate := parseAcceptanceRate(`{"totalAccepted": "14M", "acRate": "53.9%"}`); rate != 53.9 {
+		t.Fatalf("parseAcceptanceRate() = %v, want 53.9", rate)
+	}
+	if rate := parseAcceptanceRate("not json"); rate != 0 {
+		t.Fatalf("parseAcceptanceRate() = %v, want 0 for bad input", rate)
+	}
+}
@@ -0,0 +1,212 @@
+package leetcode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog is an offline snapshot of the question list. It is loaded from a
+// JSON or YAML file (chosen by extension) so the bot can keep serving
+// questions while leetcode.com is unreachable.
+type Catalog struct {
+	GeneratedAt time.Time      `json:"generated_at" yaml:"generated_at"`
+	Questions   []CatalogEntry `json:"questions" yaml:"questions"`
+
+	bySlug map[string]int
+}
+
+// CatalogEntry is one question of a snapshot. Tags are topic tag slugs;
+// Statement is the plain-text problem statement and may be empty.
+type CatalogEntry struct {
+	Slug       string   `json:"slug" yaml:"slug"`
+	Title      string   `json:"title" yaml:"title"`
+	Difficulty string   `json:"difficulty" yaml:"difficulty"`
+	Tags       []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Statement  string   `json:"statement,omitempty" yaml:"statement,omitempty"`
+}
+
+// LoadCatalog reads and validates a snapshot file.
+func LoadCatalog(path string) (*Catalog, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read question catalog: %w", err)
+	}
+
+	var catalog Catalog
+	if isYAMLPath(path) {
+		err = yaml.Unmarshal(raw, &catalog)
+	} else {
+		err = json.Unmarshal(raw, &catalog)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode question catalog %s: %w", path, err)
+	}
+	if err := catalog.index(); err != nil {
+		return nil, fmt.Errorf("question catalog %s: %w", path, err)
+	}
+	return &catalog, nil
+}
+
+// WriteCatalog stores a snapshot at path, replacing any previous file only
+// once the new one is fully written.
+func WriteCatalog(path string, catalog *Catalog) error {
+	var (
+		raw []byte
+		err error
+	)
+	if isYAMLPath(path) {
+		raw, err = yaml.Marshal(catalog)
+	} else {
+		raw, err = json.MarshalIndent(catalog, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("encode question catalog: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create question catalog dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create question catalog: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write question catalog: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write question catalog: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace question catalog: %w", err)
+	}
+	return nil
+}
+
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// index validates the entries, normalizes difficulty labels and builds the
+// slug lookup.
+func (c *Catalog) index() error {
+	c.bySlug = make(map[string]int, len(c.Questions))
+	for i := range c.Questions {
+		entry := &c.Questions[i]
+		entry.Slug = strings.TrimSpace(entry.Slug)
+		entry.Title = strings.TrimSpace(entry.Title)
+		if entry.Slug == "" || entry.Title == "" {
+			return fmt.Errorf("entry %d needs a slug and a title", i+1)
+		}
+		if _, dup := c.bySlug[entry.Slug]; dup {
+			return fmt.Errorf("duplicate slug %q", entry.Slug)
+		}
+		switch strings.ToLower(strings.TrimSpace(entry.Difficulty)) {
+		case "easy":
+			entry.Difficulty = "Easy"
+		case "medium":
+			entry.Difficulty = "Medium"
+		case "hard":
+			entry.Difficulty = "Hard"
+		default:
+			return fmt.Errorf("question %q has unknown difficulty %q", entry.Slug, entry.Difficulty)
+		}
+		c.bySlug[entry.Slug] = i
+	}
+	if len(c.Questions) == 0 {
+		return fmt.Errorf("no questions")
+	}
+	return nil
+}
+
+func (c *Catalog) AllQuestions() []Question {
+	out := make([]Question, 0, len(c.Questions))
+	for _, entry := range c.Questions {
+		out = append(out, entry.question())
+	}
+	return out
+}
+
+func (c *Catalog) RandomQuestion(seen map[string]struct{}) (Question, error) {
+	candidates := make([]int, 0, len(c.Questions))
+	for i, entry := range c.Questions {
+		if _, exists := seen[entry.Slug]; !exists {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return Question{}, ErrNoUnseenQuestions
+	}
+	return c.Questions[candidates[rand.Intn(len(candidates))]].question(), nil
+}
+
+// Entry returns the snapshot entry for slug.
+func (c *Catalog) Entry(slug string) (CatalogEntry, bool) {
+	i, ok := c.bySlug[strings.TrimSpace(slug)]
+	if !ok {
+		return CatalogEntry{}, false
+	}
+	return c.Questions[i], true
+}
+
+func (e CatalogEntry) question() Question {
+	return Question{
+		Slug:       e.Slug,
+		Title:      e.Title,
+		Difficulty: e.Difficulty,
+		URL:        "https://leetcode.com/problems/" + e.Slug + "/",
+		Tags:       slices.Clone(e.Tags),
+	}
+}
+
+// ExportCatalog snapshots the live question list. With statements set it also
+// fetches every question page, which takes a while; questions whose page
+// cannot be fetched are kept without a statement. Unlike AllQuestions it
+// waits for the tag index, so the snapshot is never written untagged.
+func (c *Client) ExportCatalog(ctx context.Context, statements bool) (*Catalog, error) {
+	tags, err := c.fetchTopicTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.tagsMu.Lock()
+	c.tags = tags
+	c.tagsCachedAt = time.Now()
+	c.tagsMu.Unlock()
+
+	all, err := c.AllQuestions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &Catalog{GeneratedAt: time.Now().UTC(), Questions: make([]CatalogEntry, 0, len(all))}
+	for _, q := range all {
+		if q.Difficulty == difficultyLabel(0) {
+			continue
+		}
+		entry := CatalogEntry{Slug: q.Slug, Title: q.Title, Difficulty: q.Difficulty, Tags: q.Tags}
+		if statements {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if detail, err := c.QuestionDetail(ctx, q.Slug); err == nil {
+				entry.Statement = detail.Content
+			}
+		}
+		catalog.Questions = append(catalog.Questions, entry)
+	}
+	if err := catalog.index(); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
@@ -0,0 +1,88 @@
+package leetcode
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCatalogReadsJSONAndYAML(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"questions.json": `{"questions": [{"slug": "two-sum", "title": "Two Sum", "difficulty": "easy", "tags": ["array"], "statement": "Find two numbers."}]}`,
+		"questions.yaml": "questions:\n  - slug: two-sum\n    title: Two Sum\n    difficulty: Easy\n    tags: [array]\n    statement: Find two numbers.\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		catalog, err := LoadCatalog(path)
+		if err != nil {
+			t.Fatalf("LoadCatalog(%s): %v", name, err)
+		}
+		all := catalog.AllQuestions()
+		if len(all) != 1 || all[0].Difficulty != "Easy" || all[0].URL != "https://leetcode.com/problems/two-sum/" || len(all[0].Tags) != 1 {
+			t.Fatalf("LoadCatalog(%s) questions = %+v", name, all)
+		}
+		if entry, ok := catalog.Entry("two-sum"); !ok || entry.Statement != "Find two numbers." {
+			t.Fatalf("Entry(two-sum) from %s = %+v, %v", name, entry, ok)
+		}
+	}
+}
+
+func TestLoadCatalogRejectsInvalidEntries(t *testing.T) {
+	tests := map[string]string{
+		"missing title":  `{"questions": [{"slug": "two-sum", "difficulty": "Easy"}]}`,
+		"bad difficulty": `{"questions": [{"slug": "two-sum", "title": "Two Sum", "difficulty": "trivial"}]}`,
+		"duplicate slug": `{"questions": [{"slug": "a", "title": "A", "difficulty": "Easy"}, {"slug": "a", "title": "A", "difficulty": "Easy"}]}`,
+		"empty":          `{"questions": []}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "questions.json")
+			if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+				t.Fatalf("write catalog: %v", err)
+			}
+			if _, err := LoadCatalog(path); err == nil {
+				t.Fatalf("expected LoadCatalog to reject %s", name)
+			}
+		})
+	}
+}
+
+func TestWriteCatalogRoundTripsAndSkipsSeen(t *testing.T) {
+	catalog := &Catalog{Questions: []CatalogEntry{
+		{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy"},
+		{Slug: "3sum", Title: "3Sum", Difficulty: "Medium", Tags: []string{"two-pointers"}},
+	}}
+	for _, name := range []string{"questions.json", "nested/questions.yml"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := WriteCatalog(path, catalog); err != nil {
+			t.Fatalf("WriteCatalog(%s): %v", name, err)
+		}
+		loaded, err := LoadCatalog(path)
+		if err != nil {
+			t.Fatalf("LoadCatalog(%s): %v", name, err)
+		}
+		if len(loaded.Questions) != 2 || loaded.Questions[1].Tags[0] != "two-pointers" {
+			t.Fatalf("round trip through %s = %+v", name, loaded.Questions)
+		}
+		if strings.HasSuffix(name, ".yml") {
+			raw, _ := os.ReadFile(path)
+			if !strings.Contains(string(raw), "slug: two-sum") {
+				t.Fatalf("expected YAML output, got: %s", raw)
+			}
+		}
+
+		q, err := loaded.RandomQuestion(map[string]struct{}{"two-sum": {}})
+		if err != nil || q.Slug != "3sum" {
+			t.Fatalf("RandomQuestion() = %+v, %v; want 3sum", q, err)
+		}
+		if _, err := loaded.RandomQuestion(map[string]struct{}{"two-sum": {}, "3sum": {}}); !errors.Is(err, ErrNoUnseenQuestions) {
+			t.Fatalf("RandomQuestion() error = %v, want ErrNoUnseenQuestions", err)
+		}
+	}
+}
@@ -17,13 +17,26 @@ import (
 const problemsEndpoint = "https://leetcode.com/api/problems/all/"
 const graphqlEndpoint = "https://leetcode.com/graphql"
 
-const questionPromptQuery = `
-query questionPrompt($titleSlug: String!) {
-  question(titleSlug: $titleSlug) {
-    content
+const questionTagsQuery = `
+query problemsetQuestionList($categorySlug: String, $limit: Int, $skip: Int, $filters: QuestionListFilterInput) {
+  problemsetQuestionList: questionList(categorySlug: $categorySlug, limit: $limit, skip: $skip, filters: $filters) {
+    total: totalNum
+    questions: data {
+      titleSlug
+      topicTags {
+        slug
+      }
+    }
   }
 }`
 
+const (
+	questionTagsPageSize   = 100
+	questionTagsRetryAfter = 5 * time.Minute
+	// questionTagsFetchTimeout bounds one background refresh of every page.
+	questionTagsFetchTimeout = 2 * time.Minute
+)
+
 var ErrNoUnseenQuestions = errors.New("no unseen questions available")
 
 type Client struct {
@@ -33,12 +46,21 @@ type Client struct {
 	mu       sync.RWMutex
 	cachedAt time.Time
 	cached   []Question
+
+	tagsMu       sync.Mutex
+	tagsCachedAt time.Time
+	tagsFailedAt time.Time
+	tagsFetching bool
+	tags         map[string][]string
+
+	details *detailCache
 }
 
 func NewClient(cacheTTL time.Duration) *Client {
 	return &Client{
 		httpClient: &http.Client{Timeout: 20 * time.Second},
 		cacheTTL:   cacheTTL,
+		details:    newDetailCache(questionDetailCacheSize, cacheTTL),
 	}
 }
 
@@ -47,6 +69,7 @@ type Question struct {
 	Title      string
 	Difficulty string
 	URL        string
+	Tags       []string
 }
 
 type problemResponse struct {
@@ -88,7 +111,7 @@ func (c *Client) AllQuestions(ctx context.Context) ([]Question, error) {
 	if len(c.cached) > 0 && time.Since(c.cachedAt) < c.cacheTTL {
 		out := slices.Clone(c.cached)
 		c.mu.RUnlock()
-		return out, nil
+		return c.withTags(out), nil
 	}
 	c.mu.RUnlock()
 
@@ -139,66 +162,152 @@ func (c *Client) AllQuestions(ctx context.Context) ([]Question, error) {
 	c.cachedAt = time.Now()
 	c.mu.Unlock()
 
-	return slices.Clone(questions), nil
+	return c.withTags(slices.Clone(questions)), nil
+}
+
+// withTags attaches the cached topic tags to questions in place. It never
+// waits on LeetCode: a missing or expired tag index is refreshed in the
+// background, and questions go out untagged until the first one lands.
+func (c *Client) withTags(questions []Question) []Question {
+	tags := c.topicTags()
+	if len(tags) == 0 {
+		return questions
+	}
+	for i := range questions {
+		questions[i].Tags = slices.Clone(tags[questions[i].Slug])
+	}
+	return questions
+}
+
+// topicTags returns the tag snapshot keyed by question slug, starting a
+// background refresh when it is missing or older than the TTL. After a
+// failed refresh the last good snapshot (or nil) is kept for
+// questionTagsRetryAfter.
+func (c *Client) topicTags() map[string][]string {
+	c.tagsMu.Lock()
+	defer c.tagsMu.Unlock()
+
+	expired := c.tags == nil || time.Since(c.tagsCachedAt) >= c.cacheTTL
+	retryLater := !c.tagsFailedAt.IsZero() && time.Since(c.tagsFailedAt) < questionTagsRetryAfter
+	if expired && !retryLater && !c.tagsFetching {
+		c.tagsFetching = true
+		go c.refreshTopicTags()
+	}
+	return c.tags
+}
+
+// refreshTopicTags fetches the paginated tag index under its own deadline,
+// detached from the request that found it stale, so a cancelled or timed-out
+// update neither aborts the fetch nor counts as a failure.
+func (c *Client) refreshTopicTags() {
+	ctx, cancel := context.WithTimeout(context.Background(), questionTagsFetchTimeout)
+	defer cancel()
+	tags, err := c.fetchTopicTags(ctx)
+
+	c.tagsMu.Lock()
+	defer c.tagsMu.Unlock()
+	c.tagsFetching = false
+	if err != nil {
+		c.tagsFailedAt = time.Now()
+		return
+	}
+	c.tags = tags
+	c.tagsCachedAt = time.Now()
+	c.tagsFailedAt = time.Time{}
 }
 
-func (c *Client) QuestionPrompt(ctx context.Context, slug string) (string, error) {
-	slug = strings.TrimSpace(slug)
-	if slug == "" {
-		return "", fmt.Errorf("slug is empty")
+func (c *Client) fetchTopicTags(ctx context.Context) (map[string][]string, error) {
+	out := make(map[string][]string)
+	for skip := 0; ; skip += questionTagsPageSize {
+		var data struct {
+			ProblemsetQuestionList struct {
+				Total     int `json:"total"`
+				Questions []struct {
+					TitleSlug string `json:"titleSlug"`
+					TopicTags []struct {
+						Slug string `json:"slug"`
+					} `json:"topicTags"`
+				} `json:"questions"`
+			} `json:"problemsetQuestionList"`
+		}
+		variables := map[string]any{
+			"categorySlug": "",
+			"limit":        questionTagsPageSize,
+			"skip":         skip,
+			"filters":      map[string]any{},
+		}
+		if err := c.graphql(ctx, "problemsetQuestionList", questionTagsQuery, variables, "https://leetcode.com/problemset/", &data); err != nil {
+			return nil, fmt.Errorf("fetch question tags: %w", err)
+		}
+
+		page := data.ProblemsetQuestionList
+		for _, q := range page.Questions {
+			tags := make([]string, 0, len(q.TopicTags))
+			for _, tag := range q.TopicTags {
+				if slug := strings.TrimSpace(tag.Slug); slug != "" {
+					tags = append(tags, slug)
+				}
+			}
+			out[q.TitleSlug] = tags
+		}
+		if len(page.Questions) == 0 || skip+len(page.Questions) >= page.Total {
+			break
+		}
 	}
 
+	if len(out) == 0 {
+		return nil, fmt.Errorf("leetcode returned no question tags")
+	}
+	return out, nil
+}
+
+func (c *Client) graphql(ctx context.Context, operationName, query string, variables map[string]any, referer string, out any) error {
 	payload := map[string]any{
-		"operationName": "questionPrompt",
-		"query":         questionPromptQuery,
-		"variables": map[string]string{
-			"titleSlug": slug,
-		},
+		"operationName": operationName,
+		"query":         query,
+		"variables":     variables,
 	}
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("marshal graphql payload: %w", err)
+		return fmt.Errorf("marshal graphql payload: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, graphqlEndpoint, bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("create graphql request: %w", err)
+		return fmt.Errorf("create graphql request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Referer", "https://leetcode.com/problems/"+slug+"/")
+	req.Header.Set("Referer", referer)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("fetch question prompt: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("leetcode graphql status %d", resp.StatusCode)
+		return fmt.Errorf("leetcode graphql status %d", resp.StatusCode)
 	}
 
 	var parsed struct {
-		Data struct {
-			Question struct {
-				Content string `json:"content"`
-			} `json:"question"`
-		} `json:"data"`
+		Data   json.RawMessage `json:"data"`
 		Errors []struct {
 			Message string `json:"message"`
 		} `json:"errors"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		return "", fmt.Errorf("decode question prompt response: %w", err)
+		return fmt.Errorf("decode graphql response: %w", err)
 	}
 	if len(parsed.Errors) > 0 {
-		return "", fmt.Errorf("leetcode graphql error: %s", parsed.Errors[0].Message)
+		return fmt.Errorf("leetcode graphql error: %s", parsed.Errors[0].Message)
 	}
-
-	prompt := htmlToText(parsed.Data.Question.Content)
-	if prompt == "" {
-		return "", fmt.Errorf("question prompt is empty")
+	if len(parsed.Data) == 0 {
+		return fmt.Errorf("leetcode graphql response has no data")
+	}
+	if err := json.Unmarshal(parsed.Data, out); err != nil {
+		return fmt.Errorf("decode graphql data: %w", err)
 	}
-	return prompt, nil
+	return nil
 }
 
 func difficultyLabel(level int) string {
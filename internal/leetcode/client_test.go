@@ -0,0 +1,91 @@
+package leetcode
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestAllQuestionsDoesNotWaitForTopicTags(t *testing.T) {
+	release := make(chan struct{})
+	var tagFetches atomic.Int32
+	client := NewClient(time.Hour)
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == problemsEndpoint {
+			return jsonResponse(`{"stat_status_pairs":[{"stat":{"question__title":"Two Sum","question__title_slug":"two-sum"},"difficulty":{"level":1}}]}`), nil
+		}
+		tagFetches.Add(1)
+		select {
+		case <-release:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		return jsonResponse(`{"data":{"problemsetQuestionList":{"total":1,"questions":[{"titleSlug":"two-sum","topicTags":[{"slug":"hash-table"}]}]}}}`), nil
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	questions, err := client.AllQuestions(ctx)
+	if err != nil {
+		t.Fatalf("AllQuestions() error = %v", err)
+	}
+	if len(questions) != 1 || questions[0].Tags != nil {
+		t.Fatalf("questions = %+v, want the catalog without waiting for tags", questions)
+	}
+	// The request that started the refresh going away must not abort it.
+	cancel()
+	if _, err := client.AllQuestions(context.Background()); err != nil {
+		t.Fatalf("AllQuestions() error = %v", err)
+	}
+	close(release)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		questions, err = client.AllQuestions(context.Background())
+		if err == nil && len(questions[0].Tags) == 1 && questions[0].Tags[0] == "hash-table" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("questions = %+v, %v; want tags from the background refresh", questions, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := tagFetches.Load(); got != 1 {
+		t.Fatalf("tag index fetched %d times, want one refresh", got)
+	}
+}
+
+func TestExportCatalogWaitsForTopicTags(t *testing.T) {
+	client := NewClient(time.Hour)
+	client.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == problemsEndpoint {
+			return jsonResponse(`{"stat_status_pairs":[{"stat":{"question__title":"Two Sum","question__title_slug":"two-sum"},"difficulty":{"level":1}}]}`), nil
+		}
+		return jsonResponse(`{"data":{"problemsetQuestionList":{"total":1,"questions":[{"titleSlug":"two-sum","topicTags":[{"slug":"hash-table"}]}]}}}`), nil
+	})}
+
+	catalog, err := client.ExportCatalog(context.Background(), false)
+	if err != nil {
+		t.Fatalf("ExportCatalog() error = %v", err)
+	}
+	if len(catalog.Questions) != 1 || len(catalog.Questions[0].Tags) != 1 || catalog.Questions[0].Tags[0] != "hash-table" {
+		t.Fatalf("catalog questions = %+v, want two-sum tagged hash-table", catalog.Questions)
+	}
+}
@@ -0,0 +1,191 @@
+package leetcode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const questionDetailQuery = `
+query questionDetail($titleSlug: String!) {
+  question(titleSlug: $titleSlug) {
+    content
+    exampleTestcases
+    metaData
+    hints
+    similarQuestions
+    stats
+    likes
+    dislikes
+    topicTags {
+      name
+      slug
+    }
+    codeSnippets {
+      langSlug
+      code
+    }
+  }
+}`
+
+const questionDetailCacheSize = 256
+
+// QuestionDetail is everything the bot uses from a question page. Content,
+// Constraints and Hints are plain text.
+type QuestionDetail struct {
+	Slug        string
+	Content     string
+	Constraints []string
+	// Snippets maps a language slug ("python3", "golang") to starter code.
+	Snippets map[string]string
+	// ExampleTestcases holds the example inputs, one parameter per line.
+	ExampleTestcases string
+	// MetaData is the raw JSON describing the entry point and its types.
+	MetaData         string
+	Hints            []string
+	SimilarQuestions []SimilarQuestion
+	// TopicTags are display names such as "Hash Table".
+	TopicTags []string
+	// AcceptanceRate is a percentage; zero when LeetCode did not report it.
+	AcceptanceRate float64
+	Likes          int
+	Dislikes       int
+}
+
+type SimilarQuestion struct {
+	Slug       string
+	Title      string
+	Difficulty string
+}
+
+// QuestionDetail returns the question page for slug, served from a bounded
+// cache for the client's TTL. Failed lookups are not cached.
+func (c *Client) QuestionDetail(ctx context.Context, slug string) (QuestionDetail, error) {
+	slug = strings.TrimSpace(slug)
+	if slug == "" {
+		return QuestionDetail{}, fmt.Errorf("slug is empty")
+	}
+	if detail, ok := c.details.get(slug); ok {
+		return detail, nil
+	}
+
+	var data struct {
+		Question *struct {
+			Content          string   `json:"content"`
+			ExampleTestcases string   `json:"exampleTestcases"`
+			MetaData         string   `json:"metaData"`
+			Hints            []string `json:"hints"`
+			SimilarQuestions string   `json:"similarQuestions"`
+			Stats            string   `json:"stats"`
+			Likes            int      `json:"likes"`
+			Dislikes         int      `json:"dislikes"`
+			TopicTags        []struct {
+				Name string `json:"name"`
+			} `json:"topicTags"`
+			CodeSnippets []struct {
+				LangSlug string `json:"langSlug"`
+				Code     string `json:"code"`
+			} `json:"codeSnippets"`
+		} `json:"question"`
+	}
+	variables := map[string]any{"titleSlug": slug}
+	if err := c.graphql(ctx, "questionDetail", questionDetailQuery, variables, "https://leetcode.com/problems/"+slug+"/", &data); err != nil {
+		return QuestionDetail{}, fmt.Errorf("fetch question detail: %w", err)
+	}
+	q := data.Question
+	if q == nil {
+		return QuestionDetail{}, fmt.Errorf("question %q not found", slug)
+	}
+
+	detail := QuestionDetail{
+		Slug:             slug,
+		Content:          htmlToText(q.Content),
+		Snippets:         make(map[string]string, len(q.CodeSnippets)),
+		ExampleTestcases: q.ExampleTestcases,
+		MetaData:         q.MetaData,
+		Likes:            q.Likes,
+		Dislikes:         q.Dislikes,
+	}
+	detail.Constraints = parseConstraints(detail.Content)
+	for _, snippet := range q.CodeSnippets {
+		detail.Snippets[snippet.LangSlug] = snippet.Code
+	}
+	for _, hint := range q.Hints {
+		if text := htmlToText(hint); text != "" {
+			detail.Hints = append(detail.Hints, text)
+		}
+	}
+	for _, tag := range q.TopicTags {
+		if name := strings.TrimSpace(tag.Name); name != "" {
+			detail.TopicTags = append(detail.TopicTags, name)
+		}
+	}
+	detail.SimilarQuestions = parseSimilarQuestions(q.SimilarQuestions)
+	detail.AcceptanceRate = parseAcceptanceRate(q.Stats)
+
+	c.details.put(slug, detail)
+	return detail, nil
+}
+
+// parseConstraints returns the bullet lines under the statement's
+// "Constraints:" heading.
+func parseConstraints(content string) []string {
+	_, section, found := strings.Cut(content, "Constraints:")
+	if !found {
+		return nil
+	}
+	out := make([]string, 0)
+	for _, line := range strings.Split(section, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		item, ok := strings.CutPrefix(line, "- ")
+		if !ok {
+			if len(out) > 0 {
+				break
+			}
+			continue
+		}
+		out = append(out, strings.TrimSpace(item))
+	}
+	return out
+}
+
+// parseSimilarQuestions decodes the JSON-encoded similarQuestions field.
+func parseSimilarQuestions(raw string) []SimilarQuestion {
+	var items []struct {
+		TitleSlug  string `json:"titleSlug"`
+		Title      string `json:"title"`
+		Difficulty string `json:"difficulty"`
+	}
+	if json.Unmarshal([]byte(raw), &items) != nil {
+		return nil
+	}
+	out := make([]SimilarQuestion, 0, len(items))
+	for _, item := range items {
+		if item.TitleSlug == "" {
+			continue
+		}
+		out = append(out, SimilarQuestion{Slug: item.TitleSlug, Title: item.Title, Difficulty: item.Difficulty})
+	}
+	return out
+}
+
+// parseAcceptanceRate reads acRate ("52.3%") from the JSON-encoded stats
+// field.
+func parseAcceptanceRate(raw string) float64 {
+	var stats struct {
+		ACRate string `json:"acRate"`
+	}
+	if json.Unmarshal([]byte(raw), &stats) != nil {
+		return 0
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(stats.ACRate), "%"), 64)
+	if err != nil {
+		return 0
+	}
+	return rate
+}
@@ -0,0 +1,98 @@
+package leetcode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrExamplesUnavailable is returned for questions whose examples cannot be
+// run as a single function call, such as design problems.
+var ErrExamplesUnavailable = errors.New("question examples are not runnable")
+
+// Examples is what a solution needs to be run against a question's sample
+// cases. Inputs and outputs are JSON literals as LeetCode shows them.
+type Examples struct {
+	Function string
+	Params   []Param
+	Return   string
+	Cases    []Example
+	// Snippets maps a language slug ("python3", "golang") to starter code.
+	Snippets map[string]string
+}
+
+type Param struct {
+	Name string
+	Type string
+}
+
+type Example struct {
+	Args     []string
+	Expected string
+}
+
+var exampleOutputPattern = regexp.MustCompile(`(?m)^\s*Output:\s*(.+?)\s*$`)
+
+// QuestionExamples pairs the question's example inputs with their expected
+// outputs, reusing the cached question detail.
+func (c *Client) QuestionExamples(ctx context.Context, slug string) (Examples, error) {
+	detail, err := c.QuestionDetail(ctx, slug)
+	if err != nil {
+		return Examples{}, err
+	}
+	out, err := parseExamples(detail.MetaData, detail.ExampleTestcases, detail.Content)
+	if err != nil {
+		return Examples{}, err
+	}
+	out.Snippets = detail.Snippets
+	return out, nil
+}
+
+// parseExamples pairs the newline-separated example inputs (one line per
+// parameter) with the "Output:" lines of the statement.
+func parseExamples(metaData, testcases, statement string) (Examples, error) {
+	var meta struct {
+		Name   string `json:"name"`
+		Params []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"params"`
+		Return struct {
+			Type string `json:"type"`
+		} `json:"return"`
+		Classname string `json:"classname"`
+	}
+	if err := json.Unmarshal([]byte(metaData), &meta); err != nil {
+		return Examples{}, fmt.Errorf("decode question metadata: %w", err)
+	}
+	if meta.Classname != "" || meta.Name == "" || len(meta.Params) == 0 {
+		return Examples{}, ErrExamplesUnavailable
+	}
+
+	out := Examples{Function: meta.Name, Return: meta.Return.Type}
+	for _, p := range meta.Params {
+		out.Params = append(out.Params, Param{Name: p.Name, Type: p.Type})
+	}
+
+	inputs := make([]string, 0)
+	for _, line := range strings.Split(testcases, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			inputs = append(inputs, line)
+		}
+	}
+	outputs := exampleOutputPattern.FindAllStringSubmatch(statement, -1)
+
+	for i := 0; (i+1)*len(out.Params) <= len(inputs) && i < len(outputs); i++ {
+		out.Cases = append(out.Cases, Example{
+			Args:     inputs[i*len(out.Params) : (i+1)*len(out.Params)],
+			Expected: outputs[i][1],
+		})
+	}
+	if len(out.Cases) == 0 {
+		return Examples{}, ErrExamplesUnavailable
+	}
+	return out, nil
+}
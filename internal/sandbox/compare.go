@@ -0,0 +1,53 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+)
+
+// floatTolerance matches LeetCode's usual 1e-5 acceptance for real answers.
+const floatTolerance = 1e-5
+
+// OutputsMatch compares an expected and an actual output. Both are decoded as
+// JSON when possible so spacing and float formatting ("2.00000" vs 2) do not
+// matter; otherwise they are compared with whitespace removed.
+func OutputsMatch(expected, got string) bool {
+	var want, have any
+	if json.Unmarshal([]byte(expected), &want) == nil && json.Unmarshal([]byte(got), &have) == nil {
+		return valuesMatch(want, have)
+	}
+	return strings.Join(strings.Fields(expected), "") == strings.Join(strings.Fields(got), "")
+}
+
+func valuesMatch(want, have any) bool {
+	switch w := want.(type) {
+	case float64:
+		h, ok := have.(float64)
+		return ok && math.Abs(w-h) <= floatTolerance*max(1, math.Abs(w))
+	case []any:
+		h, ok := have.([]any)
+		if !ok || len(w) != len(h) {
+			return false
+		}
+		for i := range w {
+			if !valuesMatch(w[i], h[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		h, ok := have.(map[string]any)
+		if !ok || len(w) != len(h) {
+			return false
+		}
+		for k, v := range w {
+			if !valuesMatch(v, h[k]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return want == have
+	}
+}
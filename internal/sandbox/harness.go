@@ -0,0 +1,207 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pythonPrelude takes the run nonce off argv before the solution loads.
+const pythonPrelude = `import sys as __sandbox_sys
+__sandbox_prefix = "__SANDBOX_RESULT_" + __sandbox_sys.argv.pop() + "__ "
+from typing import *
+import bisect, collections, functools, heapq, itertools, math, string
+from collections import Counter, defaultdict, deque
+`
+
+const pythonHarness = `
+
+def __sandbox_main():
+    import json as __json, sys as __sys
+    __fn = getattr(Solution(), %[1]q) if "Solution" in globals() else globals()[%[1]q]
+    for __line in __sys.stdin:
+        if not __line.strip():
+            continue
+        try:
+            __args = __json.loads(__line)
+            __got = __fn(*__args)
+            if %[2]s:
+                __got = __args[0]
+            __out = {"ok": __got}
+        except Exception as __err:
+            __out = {"error": type(__err).__name__ + ": " + str(__err)}
+        try:
+            __text = __json.dumps(__out, separators=(",", ":"))
+        except (TypeError, ValueError) as __err:
+            __text = __json.dumps({"error": "result is not JSON: " + str(__err)})
+        print(__sandbox_prefix + __text, flush=True)
+
+__sandbox_main()
+`
+
+func (r *Runner) preparePython(ctx context.Context, dir, code string, fn Function) ([]string, error) {
+	python, err := r.pythonPath()
+	if err != nil {
+		return nil, err
+	}
+	void := "False"
+	if fn.Return == "void" {
+		void = "True"
+	}
+	source := pythonPrelude + code + fmt.Sprintf(pythonHarness, fn.Name, void)
+	if err := os.WriteFile(filepath.Join(dir, "main.py"), []byte(source), 0o600); err != nil {
+		return nil, fmt.Errorf("write python harness: %w", err)
+	}
+	return []string{python, "-I", "-B", "main.py"}, nil
+}
+
+// pythonPath resolves the system interpreter once. Version-manager installs
+// under a home directory are neither on the sandbox PATH nor readable by
+// nobody.
+func (r *Runner) pythonPath() (string, error) {
+	r.pythonOnce.Do(func() {
+		r.python, r.pythonErr = lookSandboxPath("python3")
+		if r.pythonErr != nil {
+			r.pythonErr = fmt.Errorf("python3 is not available: %w", r.pythonErr)
+		}
+	})
+	return r.python, r.pythonErr
+}
+
+const goModule = "module solution\n\ngo 1.21\n"
+
+const goHarness = `package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+func main() {
+	prefix := "__SANDBOX_RESULT_" + os.Args[len(os.Args)-1] + "__ "
+	os.Args = os.Args[:len(os.Args)-1]
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1<<20), 1<<26)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		fmt.Println(prefix + sandboxCase(scanner.Bytes()))
+	}
+}
+
+func sandboxCase(line []byte) (out string) {
+	defer func() {
+		if r := recover(); r != nil {
+			out = sandboxResult(nil, fmt.Sprint("panic: ", r))
+		}
+	}()
+	var raw []json.RawMessage
+	if err := json.Unmarshal(line, &raw); err != nil || len(raw) != %[1]d {
+		return sandboxResult(nil, "malformed input")
+	}
+%[2]s}
+
+func sandboxResult(v any, errMsg string) string {
+	payload := map[string]any{"ok": v}
+	if errMsg != "" {
+		payload = map[string]any{"error": errMsg}
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		b, _ = json.Marshal(map[string]any{"error": "result is not JSON: " + err.Error()})
+	}
+	return string(b)
+}
+`
+
+var goPackageClause = regexp.MustCompile(`(?m)^\s*package\s+\w+\s*$`)
+
+func (r *Runner) prepareGo(ctx context.Context, dir, code string, fn Function) ([]string, error) {
+	types, err := goParamTypes(fn.GoStub, fn.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(types) != len(fn.Params) {
+		return nil, ErrUnsupported
+	}
+	for _, t := range types {
+		// LeetCode feeds characters as JSON strings, which do not decode
+		// into byte slices.
+		if strings.Contains(t, "byte") || strings.Contains(t, "rune") {
+			return nil, ErrUnsupported
+		}
+	}
+
+	if loc := goPackageClause.FindStringIndex(code); loc != nil {
+		code = code[:loc[0]] + "package main" + code[loc[1]:]
+	} else {
+		code = "package main\n\n" + code
+	}
+
+	var body strings.Builder
+	args := make([]string, len(types))
+	for i, t := range types {
+		args[i] = fmt.Sprintf("a%d", i)
+		fmt.Fprintf(&body, "\tvar a%d %s\n", i, t)
+		fmt.Fprintf(&body, "\tif err := json.Unmarshal(raw[%d], &a%d); err != nil {\n\t\treturn sandboxResult(nil, \"decode argument %d: \"+err.Error())\n\t}\n", i, i, i+1)
+	}
+	call := fmt.Sprintf("%s(%s)", fn.Name, strings.Join(args, ", "))
+	if fn.Return == "void" {
+		fmt.Fprintf(&body, "\t%s\n\treturn sandboxResult(a0, \"\")\n", call)
+	} else {
+		fmt.Fprintf(&body, "\treturn sandboxResult(%s, \"\")\n", call)
+	}
+
+	files := map[string]string{
+		"go.mod":      goModule,
+		"solution.go": code,
+		"main.go":     fmt.Sprintf(goHarness, len(types), body.String()),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			return nil, fmt.Errorf("write go harness: %w", err)
+		}
+	}
+	if err := r.build(ctx, dir, "go", "build", "-o", "solution", "."); err != nil {
+		return nil, err
+	}
+	return []string{filepath.Join(dir, "solution")}, nil
+}
+
+// goParamTypes reads the parameter types of name from LeetCode's golang
+// starter snippet, e.g. "func twoSum(nums []int, target int) []int".
+func goParamTypes(stub, name string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "stub.go", "package main\n"+stub, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name != name {
+			continue
+		}
+		out := make([]string, 0, fn.Type.Params.NumFields())
+		for _, field := range fn.Type.Params.List {
+			var buf bytes.Buffer
+			if err := printer.Fprint(&buf, token.NewFileSet(), field.Type); err != nil {
+				return nil, ErrUnsupported
+			}
+			for range max(len(field.Names), 1) {
+				out = append(out, buf.String())
+			}
+		}
+		return out, nil
+	}
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,177 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// ErrNoIsolation is returned when neither bubblewrap nor the root-only
+// unshare/setpriv pair can isolate a run on this host.
+var ErrNoIsolation = errors.New("sandbox: no isolation available; install bubblewrap, or run as root with unshare, setpriv and prlimit")
+
+// sandboxPath is the only PATH inside the sandbox, so interpreters must be
+// installed system-wide under it.
+const sandboxPath = "/usr/local/bin:/usr/bin:/bin"
+
+const (
+	// nobodyID is the uid and gid submitted code runs as.
+	nobodyID = 65534
+	// maxProcesses caps processes and threads, which stops fork bombs.
+	maxProcesses = 128
+	// maxFileBytes caps any file the code writes into its work dir.
+	maxFileBytes = 1 << 20
+	maxOpenFiles = 64
+)
+
+type isolation int
+
+const (
+	isolationBwrap isolation = iota + 1
+	isolationUnshare
+)
+
+// Check reports whether runs can be isolated on this host. Run fails with
+// the same error, so callers can refuse to enable code execution up front.
+func (r *Runner) Check(ctx context.Context) error {
+	_, err := r.isolation(ctx)
+	return err
+}
+
+// isolation picks bubblewrap when it works here, falling back to unshare
+// plus setpriv when running as root, and probes the choice once.
+func (r *Runner) isolation(ctx context.Context) (isolation, error) {
+	r.isolationOnce.Do(func() {
+		candidates := []isolation{isolationBwrap}
+		if os.Geteuid() == 0 {
+			candidates = append(candidates, isolationUnshare)
+		}
+		for _, candidate := range candidates {
+			if probeIsolation(ctx, r, candidate) == nil {
+				r.isolationMode = candidate
+				return
+			}
+		}
+		r.isolationErr = ErrNoIsolation
+	})
+	return r.isolationMode, r.isolationErr
+}
+
+func probeIsolation(ctx context.Context, r *Runner, mode isolation) error {
+	dir, err := os.MkdirTemp("", "lc-sandbox-probe-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := grantAccess(mode, dir); err != nil {
+		return err
+	}
+	argv := r.wrap(mode, dir, []string{"/bin/true"})
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Env = sandboxEnv(dir)
+	return cmd.Run()
+}
+
+// unshareMountScript runs inside the new mount namespace before dropping to
+// nobody: /tmp and /dev/shm become private tmpfs mounts, dir is bound back
+// in place from the cwd, and every other mount is made read-only. Any mount
+// that cannot be remounted fails the run rather than leaving it writable.
+const unshareMountScript = `set -e
+dir=$1
+shift
+cd "$dir"
+mount -t tmpfs -o mode=1777,size=64m,nosuid,nodev tmpfs /tmp
+if [ -d /dev/shm ]; then mount -t tmpfs -o mode=1777,size=16m,nosuid,nodev tmpfs /dev/shm; fi
+mkdir -p "$dir"
+mount --no-canonicalize --bind . "$dir"
+cd "$dir"
+while read -r _ target _; do
+	case $target in
+	/proc | /proc/* | /tmp | /dev/shm | "$dir") continue ;;
+	/tmp/*) [ -e "$target" ] || continue ;;
+	esac
+	mount -o remount,bind,ro "$target"
+done </proc/self/mounts
+exec "$@"`
+
+// wrap prefixes argv with the isolation command: fresh network, PID, IPC
+// and mount namespaces (and a user namespace under bubblewrap), uid nobody,
+// a read-only host filesystem with private /tmp and /dev/shm and only dir
+// writable, under CPU, memory, process and file-size rlimits.
+func (r *Runner) wrap(mode isolation, dir string, argv []string) []string {
+	var out []string
+	switch mode {
+	case isolationBwrap:
+		out = []string{
+			"bwrap", "--unshare-all", "--die-with-parent", "--new-session",
+			"--uid", strconv.Itoa(nobodyID), "--gid", strconv.Itoa(nobodyID),
+			"--ro-bind", "/usr", "/usr",
+			"--ro-bind-try", "/bin", "/bin",
+			"--ro-bind-try", "/lib", "/lib",
+			"--ro-bind-try", "/lib64", "/lib64",
+			"--ro-bind-try", "/etc/alternatives", "/etc/alternatives",
+			"--proc", "/proc", "--dev", "/dev", "--tmpfs", "/tmp",
+			"--bind", dir, dir, "--chdir", dir,
+			"--",
+		}
+	case isolationUnshare:
+		out = []string{
+			"unshare", "--mount", "--propagation=private", "--net", "--pid", "--fork", "--mount-proc",
+			"--ipc", "--uts", "--kill-child", "--",
+			"/bin/sh", "-c", unshareMountScript, "sandbox", dir,
+			"setpriv", "--reuid=" + strconv.Itoa(nobodyID), "--regid=" + strconv.Itoa(nobodyID),
+			"--clear-groups", "--no-new-privs", "--inh-caps=-all", "--",
+		}
+	}
+	// RLIMIT_DATA rather than RLIMIT_AS: the Go runtime reserves far more
+	// address space than it ever touches.
+	out = append(out, "prlimit",
+		"--cpu="+strconv.Itoa(r.limits.CPUSeconds),
+		"--data="+strconv.Itoa(r.limits.MemoryMB<<20),
+		"--nproc="+strconv.Itoa(maxProcesses),
+		"--fsize="+strconv.Itoa(maxFileBytes),
+		"--nofile="+strconv.Itoa(maxOpenFiles),
+		"--core=0",
+		"--",
+	)
+	return append(out, argv...)
+}
+
+// grantAccess hands dir to nobody under unshare; bubblewrap maps nobody
+// onto the bot's own user, which already owns it.
+func grantAccess(mode isolation, dir string) error {
+	if mode != isolationUnshare {
+		return nil
+	}
+	err := filepath.WalkDir(dir, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(path, nobodyID, nobodyID)
+	})
+	if err != nil {
+		return fmt.Errorf("hand sandbox dir to nobody: %w", err)
+	}
+	return nil
+}
+
+func sandboxEnv(dir string) []string {
+	return []string{"PATH=" + sandboxPath, "HOME=" + dir, "TMPDIR=" + dir, "LANG=C.UTF-8"}
+}
+
+// lookSandboxPath finds name on sandboxPath, ignoring the bot's own PATH and
+// any version-manager shims on it.
+func lookSandboxPath(name string) (string, error) {
+	for _, dir := range filepath.SplitList(sandboxPath) {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0o111 != 0 {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s not found in %s: %w", name, sandboxPath, exec.ErrNotFound)
+}
@@ -0,0 +1,313 @@
+// Package sandbox runs learner-submitted solutions against sample cases in an
+// isolated subprocess: uid nobody in fresh namespaces with no network, a
+// read-only filesystem except for its work dir and private /tmp, and a
+// scrubbed environment, capped on CPU time, memory,
+// processes, file size, wall time and output. Runs are refused on hosts that
+// cannot provide that isolation.
+package sandbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Language string
+
+const (
+	Python Language = "python"
+	Go     Language = "go"
+)
+
+// ErrUnsupported is returned for languages or signatures the harnesses cannot
+// drive, such as linked-list and tree parameters.
+var ErrUnsupported = errors.New("sandbox: unsupported language or signature")
+
+// resultPrefix returns the marker for harness output of the run nonce, so
+// prints in the solution, including forged markers, are ignored. Harnesses
+// receive the nonce as their last argument.
+func resultPrefix(nonce string) string {
+	return "__SANDBOX_RESULT_" + nonce + "__ "
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate sandbox nonce: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type Limits struct {
+	// Timeout bounds the wall time of one run over all cases.
+	Timeout time.Duration
+	// CompileTimeout bounds building compiled languages.
+	CompileTimeout time.Duration
+	CPUSeconds     int
+	MemoryMB       int
+	MaxOutputBytes int
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		Timeout:        5 * time.Second,
+		CompileTimeout: 60 * time.Second,
+		CPUSeconds:     5,
+		MemoryMB:       256,
+		MaxOutputBytes: 64 << 10,
+	}
+}
+
+// Function describes the entry point a solution must define.
+type Function struct {
+	Name string
+	// Params are LeetCode parameter types ("integer[]", "string").
+	Params []string
+	// Return is the LeetCode return type; "void" means the solution mutates
+	// its first argument, which is reported instead.
+	Return string
+	// GoStub is LeetCode's golang starter snippet; its signature supplies the
+	// Go parameter types.
+	GoStub string
+}
+
+// Case is one sample input and its expected output, as JSON literals.
+type Case struct {
+	Args     []string
+	Expected string
+}
+
+type Outcome struct {
+	Case
+	Got    string
+	Passed bool
+	Err    string
+}
+
+type Runner struct {
+	limits Limits
+
+	pythonOnce sync.Once
+	python     string
+	pythonErr  error
+
+	isolationOnce sync.Once
+	isolationMode isolation
+	isolationErr  error
+}
+
+func NewRunner(limits Limits) *Runner {
+	defaults := DefaultLimits()
+	if limits.Timeout <= 0 {
+		limits.Timeout = defaults.Timeout
+	}
+	if limits.CompileTimeout <= 0 {
+		limits.CompileTimeout = defaults.CompileTimeout
+	}
+	if limits.CPUSeconds <= 0 {
+		limits.CPUSeconds = defaults.CPUSeconds
+	}
+	if limits.MemoryMB <= 0 {
+		limits.MemoryMB = defaults.MemoryMB
+	}
+	if limits.MaxOutputBytes <= 0 {
+		limits.MaxOutputBytes = defaults.MaxOutputBytes
+	}
+	return &Runner{limits: limits}
+}
+
+// Run executes code against every case and reports one outcome per case. An
+// error means the program could not be built or started at all.
+func (r *Runner) Run(ctx context.Context, lang Language, code string, fn Function, cases []Case) ([]Outcome, error) {
+	if len(cases) == 0 {
+		return nil, nil
+	}
+	for _, t := range fn.Params {
+		if unsupportedType(t) {
+			return nil, ErrUnsupported
+		}
+	}
+	if unsupportedType(fn.Return) {
+		return nil, ErrUnsupported
+	}
+	mode, err := r.isolation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "lc-sandbox-*")
+	if err != nil {
+		return nil, fmt.Errorf("create sandbox dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var argv []string
+	switch lang {
+	case Python:
+		argv, err = r.preparePython(ctx, dir, code, fn)
+	case Go:
+		argv, err = r.prepareGo(ctx, dir, code, fn)
+	default:
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := grantAccess(mode, dir); err != nil {
+		return nil, err
+	}
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	argv = append(argv, nonce)
+
+	var stdin bytes.Buffer
+	for _, c := range cases {
+		stdin.WriteString("[" + strings.Join(c.Args, ",") + "]\n")
+	}
+	stdout, runErr := r.execute(ctx, mode, dir, argv, &stdin)
+
+	results := parseResults(stdout, resultPrefix(nonce))
+	out := make([]Outcome, 0, len(cases))
+	for i, c := range cases {
+		outcome := Outcome{Case: c}
+		switch {
+		case len(results) > len(cases):
+			outcome.Err = "the run reported more results than there are cases"
+		case i < len(results) && results[i].Error != "":
+			outcome.Err = results[i].Error
+		case i < len(results):
+			outcome.Got = string(results[i].OK)
+			outcome.Passed = OutputsMatch(c.Expected, outcome.Got)
+		case runErr != nil:
+			outcome.Err = runErr.Error()
+		default:
+			outcome.Err = "no result"
+		}
+		out = append(out, outcome)
+	}
+	return out, nil
+}
+
+func unsupportedType(t string) bool {
+	return strings.Contains(t, "Node") || strings.Contains(t, "Interval")
+}
+
+type caseResult struct {
+	OK    json.RawMessage `json:"ok"`
+	Error string          `json:"error"`
+}
+
+func parseResults(stdout []byte, prefix string) []caseResult {
+	out := make([]caseResult, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	scanner.Buffer(make([]byte, 64<<10), len(stdout)+1)
+	for scanner.Scan() {
+		line, ok := strings.CutPrefix(scanner.Text(), prefix)
+		if !ok {
+			continue
+		}
+		var result caseResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			result.Error = "unreadable result"
+		}
+		out = append(out, result)
+	}
+	return out
+}
+
+// execute runs argv isolated with a scrubbed environment. A failing run is
+// reported with the tail of stderr.
+func (r *Runner) execute(ctx context.Context, mode isolation, dir string, argv []string, stdin *bytes.Buffer) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.limits.Timeout)
+	defer cancel()
+
+	argv = r.wrap(mode, dir, argv)
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+	cmd.Env = sandboxEnv(dir)
+	cmd.Stdin = stdin
+	stdout := &limitedBuffer{max: r.limits.MaxOutputBytes}
+	stderr := &limitedBuffer{max: 4 << 10}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return stdout.Bytes(), fmt.Errorf("time limit exceeded (%s)", r.limits.Timeout)
+	case err != nil:
+		return stdout.Bytes(), fmt.Errorf("%v: %s", err, lastLine(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// build runs a trusted toolchain step, such as the Go compiler, without the
+// run-time caps.
+func (r *Runner) build(ctx context.Context, dir string, name string, args ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.limits.CompileTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0", "GOTOOLCHAIN=local", "GOFLAGS=")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("compile failed: %s", compileError(string(output), dir))
+	}
+	return nil
+}
+
+func compileError(output, dir string) string {
+	output = strings.ReplaceAll(output, dir+string(filepath.Separator), "")
+	lines := make([]string, 0, 3)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) == cap(lines) {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return "unknown error"
+	}
+	return strings.Join(lines, "; ")
+}
+
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// limitedBuffer keeps the first max bytes written and discards the rest so a
+// runaway print loop cannot exhaust memory.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
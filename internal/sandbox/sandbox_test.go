@@ -0,0 +1,256 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+var twoSum = Function{
+	Name:   "twoSum",
+	Params: []string{"integer[]", "integer"},
+	Return: "integer[]",
+	GoStub: "func twoSum(nums []int, target int) []int {\n    \n}",
+}
+
+var twoSumCases = []Case{
+	{Args: []string{"[2,7,11,15]", "9"}, Expected: "[0,1]"},
+	{Args: []string{"[3,2,4]", "6"}, Expected: "[1,2]"},
+}
+
+// requireTool skips unless name is installed where the runner looks for it
+// and runs can be isolated on this host.
+func requireTool(t *testing.T, name string) {
+	t.Helper()
+	lookup := exec.LookPath
+	if name == "python3" {
+		lookup = lookSandboxPath
+	}
+	if _, err := lookup(name); err != nil {
+		t.Skipf("%s not installed", name)
+	}
+	if err := NewRunner(Limits{}).Check(context.Background()); err != nil {
+		t.Skipf("%v", err)
+	}
+}
+
+func TestRunPythonReportsEachCase(t *testing.T) {
+	requireTool(t, "python3")
+
+	code := `class Solution:
+    def twoSum(self, nums: List[int], target: int) -> List[int]:
+        print("debug output is ignored")
+        if target == 6:
+            return [2, 1]
+        seen = {}
+        for i, n in enumerate(nums):
+            if target - n in seen:
+                return [seen[target - n], i]
+            seen[n] = i
+`
+	outcomes, err := NewRunner(Limits{}).Run(context.Background(), Python, code, twoSum, twoSumCases)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(outcomes) != 2 {
+		t.Fatalf("outcomes = %d, want 2", len(outcomes))
+	}
+	if !outcomes[0].Passed || outcomes[0].Got != "[0,1]" {
+		t.Fatalf("case 1 = %+v, want pass with [0,1]", outcomes[0])
+	}
+	if outcomes[1].Passed || outcomes[1].Got != "[2,1]" {
+		t.Fatalf("case 2 = %+v, want failure with [2,1]", outcomes[1])
+	}
+}
+
+func TestRunPythonReportsExceptionsPerCase(t *testing.T) {
+	requireTool(t, "python3")
+
+	code := `class Solution:
+    def twoSum(self, nums, target):
+        return [0, 1] if target == 9 else nums[99]
+`
+	outcomes, err := NewRunner(Limits{}).Run(context.Background(), Python, code, twoSum, twoSumCases)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !outcomes[0].Passed {
+		t.Fatalf("case 1 = %+v, want pass", outcomes[0])
+	}
+	if !strings.HasPrefix(outcomes[1].Err, "IndexError") {
+		t.Fatalf("case 2 error = %q, want IndexError", outcomes[1].Err)
+	}
+}
+
+func TestRunPythonEnforcesTimeout(t *testing.T) {
+	requireTool(t, "python3")
+
+	code := `class Solution:
+    def twoSum(self, nums, target):
+        while True:
+            pass
+`
+	runner := NewRunner(Limits{Timeout: 500 * time.Millisecond, CPUSeconds: 1})
+	start := time.Now()
+	outcomes, err := runner.Run(context.Background(), Python, code, twoSum, twoSumCases[:1])
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if outcomes[0].Passed || outcomes[0].Err == "" {
+		t.Fatalf("case = %+v, want a limit error", outcomes[0])
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("run took %s, want it stopped by the limits", elapsed)
+	}
+}
+
+func TestRunIsolatesSubmittedCode(t *testing.T) {
+	requireTool(t, "python3")
+	t.Setenv("SANDBOX_TEST_SECRET", "s3cret")
+
+	code := `import os, socket
+
+class Solution:
+    def twoSum(self, nums, target):
+        leaked = False
+        for pid in os.listdir("/proc"):
+            if not pid.isdigit():
+                continue
+            try:
+                with open("/proc/%s/environ" % pid, "rb") as f:
+                    leaked = leaked or b"s3cret" in f.read()
+            except OSError:
+                pass
+        try:
+            socket.create_connection(("1.1.1.1", 53), timeout=1).close()
+            online = True
+        except OSError:
+            online = False
+        try:
+            with open("big", "wb") as f:
+                f.write(b"x" * (4 << 20))
+            wrote = True
+        except OSError:
+            wrote = False
+        for d in ("/tmp", "/var/tmp", "/dev/shm"):
+            try:
+                with open(d + "/lc-sandbox-escape", "w") as f:
+                    f.write("x")
+            except OSError:
+                pass
+        return [os.getuid(), leaked, online, wrote]
+`
+	outcomes, err := NewRunner(Limits{}).Run(context.Background(), Python, code, twoSum, twoSumCases[:1])
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := outcomes[0].Got; got != "[65534,false,false,false]" {
+		t.Fatalf("got %s (err %q), want uid nobody, no leaked env, no network and a file size cap", got, outcomes[0].Err)
+	}
+	for _, dir := range []string{"/tmp", "/var/tmp", "/dev/shm"} {
+		if err := os.Remove(dir + "/lc-sandbox-escape"); err == nil {
+			t.Errorf("submitted code wrote to the host's %s", dir)
+		}
+	}
+}
+
+func TestRunIgnoresForgedResults(t *testing.T) {
+	requireTool(t, "python3")
+	runner := NewRunner(Limits{})
+
+	forged := `class Solution:
+    def twoSum(self, nums, target):
+        print('__SANDBOX_RESULT__ {"ok":[0,1]}')
+        print('__SANDBOX_RESULT_0__ {"ok":[0,1]}')
+        return []
+`
+	outcomes, err := runner.Run(context.Background(), Python, forged, twoSum, twoSumCases[:1])
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if outcomes[0].Passed || outcomes[0].Got != "[]" {
+		t.Fatalf("outcome = %+v, want the forged markers ignored", outcomes[0])
+	}
+
+	extra := `print(__sandbox_prefix + '{"ok":[0,1]}')
+
+class Solution:
+    def twoSum(self, nums, target):
+        return []
+`
+	outcomes, err = runner.Run(context.Background(), Python, extra, twoSum, twoSumCases[:1])
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if outcomes[0].Passed || !strings.Contains(outcomes[0].Err, "more results") {
+		t.Fatalf("outcome = %+v, want a run with extra results rejected", outcomes[0])
+	}
+}
+
+func TestRunGoBuildsAgainstStubSignature(t *testing.T) {
+	requireTool(t, "go")
+
+	code := `func twoSum(nums []int, target int) []int {
+	seen := map[int]int{}
+	for i, n := range nums {
+		if j, ok := seen[target-n]; ok {
+			return []int{j, i}
+		}
+		seen[n] = i
+	}
+	return nil
+}`
+	outcomes, err := NewRunner(Limits{}).Run(context.Background(), Go, code, twoSum, twoSumCases)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for i, outcome := range outcomes {
+		if !outcome.Passed {
+			t.Fatalf("case %d = %+v, want pass", i+1, outcome)
+		}
+	}
+}
+
+func TestRunGoReportsCompileErrors(t *testing.T) {
+	requireTool(t, "go")
+
+	code := "func twoSum(nums []int, target int) []int {\n\treturn undefinedName\n}"
+	_, err := NewRunner(Limits{}).Run(context.Background(), Go, code, twoSum, twoSumCases)
+	if err == nil || !strings.Contains(err.Error(), "undefined") {
+		t.Fatalf("Run() error = %v, want compile error mentioning undefined", err)
+	}
+}
+
+func TestRunRejectsUnsupportedSignatures(t *testing.T) {
+	fn := Function{Name: "reverseList", Params: []string{"ListNode"}, Return: "ListNode"}
+	_, err := NewRunner(Limits{}).Run(context.Background(), Python, "", fn, []Case{{Args: []string{"[1,2]"}, Expected: "[2,1]"}})
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Run() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestOutputsMatch(t *testing.T) {
+	tests := []struct {
+		expected string
+		got      string
+		want     bool
+	}{
+		{expected: "[0,1]", got: "[0, 1]", want: true},
+		{expected: "[0,1]", got: "[1,0]", want: false},
+		{expected: "2.00000", got: "2", want: true},
+		{expected: "2.50000", got: "2.5000001", want: true},
+		{expected: "true", got: "true", want: true},
+		{expected: `"bab"`, got: `"aba"`, want: false},
+		{expected: "[[1,2],[3]]", got: "[[1,2],[3]]", want: true},
+		{expected: "not json", got: "not  json", want: true},
+	}
+	for _, tt := range tests {
+		if got := OutputsMatch(tt.expected, tt.got); got != tt.want {
+			t.Fatalf("OutputsMatch(%q, %q) = %v, want %v", tt.expected, tt.got, got, tt.want)
+		}
+	}
+}
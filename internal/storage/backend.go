@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// Backend is the persistence contract shared by every storage implementation.
+// The adapters package maps it onto bot.StateStore.
+type Backend interface {
+	GetChatSettings(ctx context.Context, key StateKey) (ChatSettings, error)
+	UpsertDailySettings(ctx context.Context, key StateKey, enabled bool, hhmm, tz string) error
+	SetCurrentQuestion(ctx context.Context, key StateKey, q QuestionRef) error
+	ClearCurrentQuestion(ctx context.Context, key StateKey) error
+	SetHintLevel(ctx context.Context, key StateKey, slug string, level int) error
+	SetTutorThread(ctx context.Context, key StateKey, thread TutorThread) error
+	SetMockSession(ctx context.Context, key StateKey, session MockSession) error
+	ClearMockSession(ctx context.Context, key StateKey) error
+	ListMockSessions(ctx context.Context) ([]ChatSettings, error)
+	MarkDailySent(ctx context.Context, key StateKey, day string) error
+	MarkWeeklySummarySent(ctx context.Context, key StateKey, week string) error
+	SetDifficultyPreference(ctx context.Context, key StateKey, difficulty string) error
+	SetDailyMode(ctx context.Context, key StateKey, mode string) error
+	SetStudyPlan(ctx context.Context, key StateKey, name string) error
+	DismissQuestion(ctx context.Context, key StateKey, slug string) error
+	MarkQuestionAnswered(ctx context.Context, key StateKey, q QuestionRef) error
+	ListAnsweredQuestions(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error)
+	ListAllAnsweredQuestions(ctx context.Context, key StateKey) ([]AnsweredQuestion, error)
+	GetAnsweredQuestion(ctx context.Context, key StateKey, slug string) (QuestionRef, error)
+	ListReviewQueue(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error)
+	GetReviewState(ctx context.Context, key StateKey, slug string) (ReviewState, error)
+	UpdateReviewState(ctx context.Context, key StateKey, slug string, state ReviewState) error
+	DeleteAnsweredQuestion(ctx context.Context, key StateKey, slug string) error
+	RecordAnswerAttempt(ctx context.Context, key StateKey, attempt AnswerAttempt) error
+	ListAnswerAttempts(ctx context.Context, key StateKey, slug string, limit int) ([]AnswerAttempt, error)
+	RecordGroupSolve(ctx context.Context, chatID int64, solve GroupSolve) error
+	ListGroupSolves(ctx context.Context, chatID int64, since time.Time) ([]GroupSolve, error)
+	ListActiveGroups(ctx context.Context, since time.Time) ([]ChatSettings, error)
+	SaveInterviewSession(ctx context.Context, key StateKey, session InterviewSession) error
+	LatestInterviewSession(ctx context.Context, key StateKey) (InterviewSession, error)
+	SaveQuestionList(ctx context.Context, chatID int64, list QuestionList) error
+	GetQuestionList(ctx context.Context, chatID int64, name string) (QuestionList, error)
+	ListQuestionLists(ctx context.Context, chatID int64) ([]QuestionList, error)
+	SaveFormattedPrompt(ctx context.Context, prompt FormattedPrompt) error
+	GetFormattedPrompt(ctx context.Context, key string) (FormattedPrompt, error)
+	AddServedQuestion(ctx context.Context, key StateKey, q QuestionRef) error
+	RemoveServedQuestion(ctx context.Context, key StateKey, slug string) error
+	SeenQuestionSet(ctx context.Context, key StateKey) (map[string]struct{}, error)
+	ResetServedQuestions(ctx context.Context, key StateKey) error
+	ListDailyEnabledChats(ctx context.Context) ([]ChatSettings, error)
+}
+
+var (
+	_ Backend = (*Store)(nil)
+	_ Backend = (*MemoryStore)(nil)
+	_ Backend = (*BoltStore)(nil)
+)
@@ -0,0 +1,993 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const boltOpenTimeout = 5 * time.Second
+
+var (
+	boltMetaBucket     = []byte("meta")
+	boltChatsBucket    = []byte("chats")
+	boltServedBucket   = []byte("served_questions")
+	boltAnsweredBucket = []byte("answered_questions")
+	boltAttemptsBucket = []byte("answer_attempts")
+	boltSolvesBucket   = []byte("group_solves")
+	boltSessionsBucket = []byte("interview_sessions")
+	boltListsBucket    = []byte("question_lists")
+	boltPromptsBucket  = []byte("formatted_prompts")
+
+	boltSchemaVersionKey = []byte("schema_version")
+)
+
+// boltMigration upgrades the on-disk layout by one schema version.
+type boltMigration struct {
+	version int
+	name    string
+	apply   func(tx *bolt.Tx) error
+}
+
+// boltMigrations must stay append-only; each entry runs exactly once per
+// database file, in order, inside a single write transaction.
+var boltMigrations = []boltMigration{
+	{
+		version: 1,
+		name:    "create base buckets",
+		apply: func(tx *bolt.Tx) error {
+			for _, name := range [][]byte{boltChatsBucket, boltServedBucket, boltAnsweredBucket} {
+				if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		version: 2,
+		name:    "create answer attempts bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(boltAttemptsBucket)
+			return err
+		},
+	},
+	{
+		version: 3,
+		name:    "create group solves bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(boltSolvesBucket)
+			return err
+		},
+	},
+	{
+		version: 4,
+		name:    "create interview sessions bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(boltSessionsBucket)
+			return err
+		},
+	},
+	{
+		version: 5,
+		name:    "create question lists bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(boltListsBucket)
+			return err
+		},
+	},
+	{
+		version: 6,
+		name:    "create formatted prompts bucket",
+		apply: func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(boltPromptsBucket)
+			return err
+		},
+	},
+}
+
+// BoltStore persists chat state in a single bbolt database file. Per-chat
+// subcollections are modelled as nested buckets keyed by chat ID.
+type BoltStore struct {
+	db               *bolt.DB
+	defaultDailyTime string
+	defaultDailyTZ   string
+	nowFn            func() time.Time
+}
+
+func OpenBoltStore(path, defaultDailyTime, defaultDailyTZ string) (*BoltStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create bolt data dir: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("open bolt db: %w", err)
+	}
+
+	store := &BoltStore{
+		db:               db,
+		defaultDailyTime: defaultDailyTime,
+		defaultDailyTZ:   defaultDailyTZ,
+		nowFn:            time.Now,
+	}
+	if err := store.migrate(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// SchemaVersion reports the migration version currently applied to the file.
+func (s *BoltStore) SchemaVersion() (int, error) {
+	version := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		version, err = readSchemaVersion(tx)
+		return err
+	})
+	return version, err
+}
+
+func (s *BoltStore) migrate() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(boltMetaBucket); err != nil {
+			return fmt.Errorf("create meta bucket: %w", err)
+		}
+
+		current, err := readSchemaVersion(tx)
+		if err != nil {
+			return err
+		}
+		for _, m := range boltMigrations {
+			if m.version <= current {
+				continue
+			}
+			if err := m.apply(tx); err != nil {
+				return fmt.Errorf("apply bolt migration %d (%s): %w", m.version, m.name, err)
+			}
+			current = m.version
+		}
+
+		return tx.Bucket(boltMetaBucket).Put(boltSchemaVersionKey, []byte(strconv.Itoa(current)))
+	})
+}
+
+func readSchemaVersion(tx *bolt.Tx) (int, error) {
+	meta := tx.Bucket(boltMetaBucket)
+	if meta == nil {
+		return 0, nil
+	}
+	raw := meta.Get(boltSchemaVersionKey)
+	if raw == nil {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(string(raw))
+	if err != nil {
+		return 0, fmt.Errorf("decode schema version %q: %w", raw, err)
+	}
+	return version, nil
+}
+
+func (s *BoltStore) GetChatSettings(_ context.Context, key StateKey) (ChatSettings, error) {
+	var (
+		settings ChatSettings
+		found    bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = getJSON(tx.Bucket(boltChatsBucket), chatKey(key), &settings)
+		return err
+	})
+	if err != nil {
+		return ChatSettings{}, fmt.Errorf("get chat settings: %w", err)
+	}
+	if !found {
+		return s.defaultSettings(key), nil
+	}
+
+	if settings.ChatID == 0 {
+		settings.ChatID, settings.UserID = key.ChatID, key.UserID
+	}
+	return s.withDefaults(settings), nil
+}
+
+func (s *BoltStore) UpsertDailySettings(_ context.Context, key StateKey, enabled bool, hhmm, tz string) error {
+	if hhmm == "" {
+		hhmm = s.defaultDailyTime
+	}
+	if tz == "" {
+		tz = s.defaultDailyTZ
+	}
+
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.DailyEnabled = enabled
+		item.DailyTime = hhmm
+		item.Timezone = tz
+	})
+	if err != nil {
+		return fmt.Errorf("upsert daily settings: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) SetCurrentQuestion(_ context.Context, key StateKey, q QuestionRef) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		qCopy := q
+		item.CurrentQuestion = &qCopy
+		item.HintLevel = 0
+		item.HintSlug = ""
+		item.Tutor = nil
+	})
+	if err != nil {
+		return fmt.Errorf("set current question: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) ClearCurrentQuestion(_ context.Context, key StateKey) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.CurrentQuestion = nil
+		item.HintLevel = 0
+		item.HintSlug = ""
+		item.Tutor = nil
+	})
+	if err != nil {
+		return fmt.Errorf("clear current question: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) SetHintLevel(_ context.Context, key StateKey, slug string, level int) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.HintLevel = level
+		item.HintSlug = slug
+	})
+	if err != nil {
+		return fmt.Errorf("set hint level: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) SetTutorThread(_ context.Context, key StateKey, thread TutorThread) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.Tutor = &thread
+	})
+	if err != nil {
+		return fmt.Errorf("set tutor thread: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) SetMockSession(_ context.Context, key StateKey, session MockSession) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.Mock = &session
+	})
+	if err != nil {
+		return fmt.Errorf("set mock session: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) ClearMockSession(_ context.Context, key StateKey) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.Mock = nil
+	})
+	if err != nil {
+		return fmt.Errorf("clear mock session: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) MarkDailySent(_ context.Context, key StateKey, day string) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.LastDailySentOn = day
+	})
+	if err != nil {
+		return fmt.Errorf("mark daily sent: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) MarkWeeklySummarySent(_ context.Context, key StateKey, week string) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.LastWeeklySummaryOn = week
+	})
+	if err != nil {
+		return fmt.Errorf("mark weekly summary sent: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) SetDifficultyPreference(_ context.Context, key StateKey, difficulty string) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.Difficulty = difficulty
+	})
+	if err != nil {
+		return fmt.Errorf("set difficulty preference: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) SetDailyMode(_ context.Context, key StateKey, mode string) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.DailyMode = mode
+	})
+	if err != nil {
+		return fmt.Errorf("set daily mode: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) SetStudyPlan(_ context.Context, key StateKey, name string) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.StudyPlan = name
+	})
+	if err != nil {
+		return fmt.Errorf("set study plan: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) DismissQuestion(_ context.Context, key StateKey, slug string) error {
+	err := s.updateChat(key, func(item *ChatSettings) {
+		item.DismissedSlug = slug
+	})
+	if err != nil {
+		return fmt.Errorf("dismiss question: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) MarkQuestionAnswered(_ context.Context, key StateKey, q QuestionRef) error {
+	if q.Slug == "" {
+		return fmt.Errorf("mark question answered: slug is empty")
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := chatSubBucket(tx, boltAnsweredBucket, key)
+		if err != nil {
+			return err
+		}
+
+		now := s.nowFn().UTC()
+		var item AnsweredQuestion
+		found, err := getJSON(bucket, []byte(q.Slug), &item)
+		if err != nil {
+			return err
+		}
+		if !found {
+			item = AnsweredQuestion{FirstAnsweredAt: now}
+		}
+		item.Slug = q.Slug
+		item.Title = q.Title
+		item.Difficulty = q.Difficulty
+		item.URL = q.URL
+		item.Attempts++
+		item.LastAnsweredAt = now
+		return putJSON(bucket, []byte(q.Slug), item)
+	})
+	if err != nil {
+		return fmt.Errorf("mark question answered: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) ListAnsweredQuestions(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error) {
+	out, err := s.ListAllAnsweredQuestions(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if limit = clampAnsweredLimit(limit); len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (s *BoltStore) ListReviewQueue(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error) {
+	out, err := s.ListAllAnsweredQuestions(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return sortReviewQueue(out, limit), nil
+}
+
+// ListAllAnsweredQuestions returns the whole answered history, newest first.
+func (s *BoltStore) ListAllAnsweredQuestions(_ context.Context, key StateKey) ([]AnsweredQuestion, error) {
+	out := make([]AnsweredQuestion, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltAnsweredBucket, key)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var item AnsweredQuestion
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("decode answered question: %w", err)
+			}
+			if item.Slug == "" {
+				item.Slug = string(k)
+			}
+			if item.Attempts == 0 {
+				item.Attempts = 1
+			}
+			out = append(out, withReviewDefaults(item))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list answered questions: %w", err)
+	}
+
+	sortAnsweredByRecency(out)
+	return out, nil
+}
+
+func (s *BoltStore) GetReviewState(_ context.Context, key StateKey, slug string) (ReviewState, error) {
+	var (
+		item  AnsweredQuestion
+		found bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = getJSON(existingChatSubBucket(tx, boltAnsweredBucket, key), []byte(slug), &item)
+		return err
+	})
+	if err != nil {
+		return ReviewState{}, fmt.Errorf("get review state: %w", err)
+	}
+	if !found {
+		return ReviewState{}, ErrAnsweredQuestionNotFound
+	}
+	return withReviewDefaults(item).reviewState(), nil
+}
+
+func (s *BoltStore) UpdateReviewState(_ context.Context, key StateKey, slug string, state ReviewState) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltAnsweredBucket, key)
+		var item AnsweredQuestion
+		found, err := getJSON(bucket, []byte(slug), &item)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrAnsweredQuestionNotFound
+		}
+		item.setReviewState(state)
+		return putJSON(bucket, []byte(slug), item)
+	})
+	if errors.Is(err, ErrAnsweredQuestionNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("update review state: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) GetAnsweredQuestion(_ context.Context, key StateKey, slug string) (QuestionRef, error) {
+	var (
+		q     QuestionRef
+		found bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = getJSON(existingChatSubBucket(tx, boltAnsweredBucket, key), []byte(slug), &q)
+		return err
+	})
+	if err != nil {
+		return QuestionRef{}, fmt.Errorf("get answered question: %w", err)
+	}
+	if !found {
+		return QuestionRef{}, ErrAnsweredQuestionNotFound
+	}
+	if q.Slug == "" {
+		q.Slug = slug
+	}
+	return q, nil
+}
+
+func (s *BoltStore) DeleteAnsweredQuestion(_ context.Context, key StateKey, slug string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltAnsweredBucket, key)
+		if bucket == nil || bucket.Get([]byte(slug)) == nil {
+			return ErrAnsweredQuestionNotFound
+		}
+		return bucket.Delete([]byte(slug))
+	})
+	if errors.Is(err, ErrAnsweredQuestionNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("delete answered question: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) RecordAnswerAttempt(_ context.Context, key StateKey, attempt AnswerAttempt) error {
+	if attempt.Slug == "" {
+		return fmt.Errorf("record answer attempt: slug is empty")
+	}
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = s.nowFn()
+	}
+	attempt.CreatedAt = attempt.CreatedAt.UTC()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := chatSubBucket(tx, boltAttemptsBucket, key)
+		if err != nil {
+			return err
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, sequenceKey(seq), attempt)
+	})
+	if err != nil {
+		return fmt.Errorf("record answer attempt: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) ListAnswerAttempts(_ context.Context, key StateKey, slug string, limit int) ([]AnswerAttempt, error) {
+	out := make([]AnswerAttempt, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltAttemptsBucket, key)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var item AnswerAttempt
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("decode answer attempt: %w", err)
+			}
+			if slug == "" || item.Slug == slug {
+				out = append(out, item)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list answer attempts: %w", err)
+	}
+	return sortAnswerAttempts(out, limit), nil
+}
+
+func (s *BoltStore) RecordGroupSolve(_ context.Context, chatID int64, solve GroupSolve) error {
+	if solve.Slug == "" {
+		return fmt.Errorf("record group solve: slug is empty")
+	}
+	if solve.SolvedAt.IsZero() {
+		solve.SolvedAt = s.nowFn()
+	}
+	solve.SolvedAt = solve.SolvedAt.UTC()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := chatSubBucket(tx, boltSolvesBucket, ChatKey(chatID))
+		if err != nil {
+			return err
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, sequenceKey(seq), solve)
+	})
+	if err != nil {
+		return fmt.Errorf("record group solve: %w", err)
+	}
+	err = s.updateChat(ChatKey(chatID), func(item *ChatSettings) {
+		if solve.SolvedAt.After(item.LastSolveAt) {
+			item.LastSolveAt = solve.SolvedAt
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("record group solve time: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) ListGroupSolves(_ context.Context, chatID int64, since time.Time) ([]GroupSolve, error) {
+	out := make([]GroupSolve, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltSolvesBucket, ChatKey(chatID))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var item GroupSolve
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("decode group solve: %w", err)
+			}
+			if !item.SolvedAt.Before(since) {
+				out = append(out, item)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list group solves: %w", err)
+	}
+	return sortGroupSolves(out), nil
+}
+
+func (s *BoltStore) SaveInterviewSession(_ context.Context, key StateKey, session InterviewSession) error {
+	if session.ID == "" {
+		return fmt.Errorf("save interview session: id is empty")
+	}
+	session.StartedAt = session.StartedAt.UTC()
+	session.FinishedAt = session.FinishedAt.UTC()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := chatSubBucket(tx, boltSessionsBucket, key)
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, []byte(session.ID), session)
+	})
+	if err != nil {
+		return fmt.Errorf("save interview session: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) LatestInterviewSession(_ context.Context, key StateKey) (InterviewSession, error) {
+	var (
+		latest InterviewSession
+		found  bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltSessionsBucket, key)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var item InterviewSession
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("decode interview session: %w", err)
+			}
+			if !found || item.StartedAt.After(latest.StartedAt) {
+				latest, found = item, true
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return InterviewSession{}, fmt.Errorf("latest interview session: %w", err)
+	}
+	if !found {
+		return InterviewSession{}, ErrInterviewSessionNotFound
+	}
+	return latest, nil
+}
+
+func (s *BoltStore) SaveQuestionList(_ context.Context, chatID int64, list QuestionList) error {
+	if list.Name == "" {
+		return fmt.Errorf("save question list: name is empty")
+	}
+	list.CreatedAt = list.CreatedAt.UTC()
+	list.UpdatedAt = list.UpdatedAt.UTC()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := chatSubBucket(tx, boltListsBucket, ChatKey(chatID))
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, []byte(list.Name), list)
+	})
+	if err != nil {
+		return fmt.Errorf("save question list: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) GetQuestionList(_ context.Context, chatID int64, name string) (QuestionList, error) {
+	var (
+		list  QuestionList
+		found bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltListsBucket, ChatKey(chatID))
+		if bucket == nil {
+			return nil
+		}
+		var err error
+		found, err = getJSON(bucket, []byte(name), &list)
+		return err
+	})
+	if err != nil {
+		return QuestionList{}, fmt.Errorf("get question list: %w", err)
+	}
+	if !found {
+		return QuestionList{}, ErrQuestionListNotFound
+	}
+	return list, nil
+}
+
+func (s *BoltStore) ListQuestionLists(_ context.Context, chatID int64) ([]QuestionList, error) {
+	out := make([]QuestionList, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltListsBucket, ChatKey(chatID))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var item QuestionList
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("decode question list: %w", err)
+			}
+			out = append(out, item)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list question lists: %w", err)
+	}
+	return sortQuestionLists(out), nil
+}
+
+func (s *BoltStore) SaveFormattedPrompt(_ context.Context, prompt FormattedPrompt) error {
+	if prompt.Key == "" {
+		return fmt.Errorf("save formatted prompt: key is empty")
+	}
+	prompt.CreatedAt = prompt.CreatedAt.UTC()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(boltPromptsBucket), []byte(prompt.Key), prompt)
+	})
+	if err != nil {
+		return fmt.Errorf("save formatted prompt: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) GetFormattedPrompt(_ context.Context, key string) (FormattedPrompt, error) {
+	var (
+		prompt FormattedPrompt
+		found  bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = getJSON(tx.Bucket(boltPromptsBucket), []byte(key), &prompt)
+		return err
+	})
+	if err != nil {
+		return FormattedPrompt{}, fmt.Errorf("get formatted prompt: %w", err)
+	}
+	if !found {
+		return FormattedPrompt{}, ErrFormattedPromptNotFound
+	}
+	return prompt, nil
+}
+
+func (s *BoltStore) AddServedQuestion(_ context.Context, key StateKey, q QuestionRef) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := chatSubBucket(tx, boltServedBucket, key)
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, []byte(q.Slug), q)
+	})
+	if err != nil {
+		return fmt.Errorf("add served question: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) RemoveServedQuestion(_ context.Context, key StateKey, slug string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltServedBucket, key)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.Delete([]byte(slug))
+	})
+	if err != nil {
+		return fmt.Errorf("remove served question: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) SeenQuestionSet(_ context.Context, key StateKey) (map[string]struct{}, error) {
+	seen := make(map[string]struct{})
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := existingChatSubBucket(tx, boltServedBucket, key)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, _ []byte) error {
+			seen[string(k)] = struct{}{}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load served questions: %w", err)
+	}
+	return seen, nil
+}
+
+func (s *BoltStore) ResetServedQuestions(_ context.Context, key StateKey) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		parent := tx.Bucket(boltServedBucket)
+		if parent.Bucket(chatKey(key)) == nil {
+			return nil
+		}
+		return parent.DeleteBucket(chatKey(key))
+	})
+	if err != nil {
+		return fmt.Errorf("reset served questions: %w", err)
+	}
+	return nil
+}
+
+func (s *BoltStore) ListDailyEnabledChats(_ context.Context) ([]ChatSettings, error) {
+	out := make([]ChatSettings, 0, 32)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltChatsBucket).ForEach(func(k, v []byte) error {
+			var item ChatSettings
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("decode daily chat: %w", err)
+			}
+			if !item.DailyEnabled {
+				return nil
+			}
+			if item.ChatID == 0 {
+				parsed, parseErr := strconv.ParseInt(string(k), 10, 64)
+				if parseErr == nil {
+					item.ChatID = parsed
+				}
+			}
+			if item.ChatID == 0 || item.UserID != 0 {
+				return nil
+			}
+			out = append(out, s.withDefaults(item))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query daily chats: %w", err)
+	}
+	sortChatSettings(out)
+	return out, nil
+}
+
+func (s *BoltStore) ListActiveGroups(_ context.Context, since time.Time) ([]ChatSettings, error) {
+	out := make([]ChatSettings, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltChatsBucket).ForEach(func(_, v []byte) error {
+			var item ChatSettings
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("decode active group: %w", err)
+			}
+			if item.ChatID == 0 || item.UserID != 0 || item.LastSolveAt.IsZero() || item.LastSolveAt.Before(since) {
+				return nil
+			}
+			out = append(out, s.withDefaults(item))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query active groups: %w", err)
+	}
+	sortChatSettings(out)
+	return out, nil
+}
+
+func (s *BoltStore) ListMockSessions(_ context.Context) ([]ChatSettings, error) {
+	out := make([]ChatSettings, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltChatsBucket).ForEach(func(_, v []byte) error {
+			var item ChatSettings
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("decode mock session chat: %w", err)
+			}
+			if item.ChatID == 0 || item.Mock == nil {
+				return nil
+			}
+			out = append(out, s.withDefaults(item))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query mock sessions: %w", err)
+	}
+	sortChatSettings(out)
+	return out, nil
+}
+
+func (s *BoltStore) updateChat(key StateKey, mutate func(item *ChatSettings)) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltChatsBucket)
+		var item ChatSettings
+		if _, err := getJSON(bucket, chatKey(key), &item); err != nil {
+			return err
+		}
+		item.ChatID, item.UserID = key.ChatID, key.UserID
+		mutate(&item)
+		item.UpdatedAt = s.nowFn().UTC()
+		return putJSON(bucket, chatKey(key), item)
+	})
+}
+
+func (s *BoltStore) withDefaults(item ChatSettings) ChatSettings {
+	if item.DailyTime == "" {
+		item.DailyTime = s.defaultDailyTime
+	}
+	if item.Timezone == "" {
+		item.Timezone = s.defaultDailyTZ
+	}
+	return item
+}
+
+func (s *BoltStore) defaultSettings(key StateKey) ChatSettings {
+	return ChatSettings{
+		ChatID:       key.ChatID,
+		UserID:       key.UserID,
+		DailyEnabled: false,
+		DailyTime:    s.defaultDailyTime,
+		Timezone:     s.defaultDailyTZ,
+	}
+}
+
+func chatKey(key StateKey) []byte {
+	return []byte(key.String())
+}
+
+// sequenceKey encodes a bucket sequence big-endian so keys sort in insertion
+// order.
+func sequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+// chatSubBucket returns the per-chat nested bucket under parent, creating it
+// on first write.
+func chatSubBucket(tx *bolt.Tx, parent []byte, key StateKey) (*bolt.Bucket, error) {
+	bucket, err := tx.Bucket(parent).CreateBucketIfNotExists(chatKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("create %s bucket for chat %s: %w", parent, key, err)
+	}
+	return bucket, nil
+}
+
+func existingChatSubBucket(tx *bolt.Tx, parent []byte, key StateKey) *bolt.Bucket {
+	return tx.Bucket(parent).Bucket(chatKey(key))
+}
+
+func getJSON(bucket *bolt.Bucket, key []byte, dst any) (bool, error) {
+	if bucket == nil {
+		return false, nil
+	}
+	raw := bucket.Get(key)
+	if raw == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return false, fmt.Errorf("decode %s: %w", key, err)
+	}
+	return true, nil
+}
+
+func putJSON(bucket *bolt.Bucket, key []byte, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", key, err)
+	}
+	return bucket.Put(key, raw)
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBoltStorePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "bot.db")
+	ctx := context.Background()
+
+	store, err := OpenBoltStore(path, "20:00", "Asia/Singapore")
+	if err != nil {
+		t.Fatalf("open bolt store: %v", err)
+	}
+	if err := store.UpsertDailySettings(ctx, ChatKey(42), true, "21:30", ""); err != nil {
+		t.Fatalf("upsert daily settings: %v", err)
+	}
+	if err := store.MarkQuestionAnswered(ctx, ChatKey(42), QuestionRef{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy"}); err != nil {
+		t.Fatalf("mark answered: %v", err)
+	}
+	if err := store.AddServedQuestion(ctx, ChatKey(42), QuestionRef{Slug: "two-sum"}); err != nil {
+		t.Fatalf("add served: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	store, err = OpenBoltStore(path, "20:00", "Asia/Singapore")
+	if err != nil {
+		t.Fatalf("reopen bolt store: %v", err)
+	}
+	defer store.Close()
+
+	chats, err := store.ListDailyEnabledChats(ctx)
+	if err != nil {
+		t.Fatalf("list daily chats: %v", err)
+	}
+	if len(chats) != 1 || chats[0].ChatID != 42 || chats[0].DailyTime != "21:30" || chats[0].Timezone != "Asia/Singapore" {
+		t.Fatalf("unexpected daily chats after reopen: %+v", chats)
+	}
+	if _, err := store.GetAnsweredQuestion(ctx, ChatKey(42), "two-sum"); err != nil {
+		t.Fatalf("expected answered question to persist: %v", err)
+	}
+	seen, _ := store.SeenQuestionSet(ctx, ChatKey(42))
+	if _, ok := seen["two-sum"]; !ok {
+		t.Fatalf("expected served question to persist, got %v", seen)
+	}
+}
+
+func TestBoltStoreMigratesLegacyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+
+	// A file created before schema tracking has no meta bucket.
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatalf("create legacy db: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("close legacy db: %v", err)
+	}
+
+	store, err := OpenBoltStore(path, "20:00", "Asia/Singapore")
+	if err != nil {
+		t.Fatalf("open bolt store: %v", err)
+	}
+	defer store.Close()
+
+	version, err := store.SchemaVersion()
+	if err != nil {
+		t.Fatalf("schema version: %v", err)
+	}
+	if want := boltMigrations[len(boltMigrations)-1].version; version != want {
+		t.Fatalf("expected schema version %d, got %d", want, version)
+	}
+}
@@ -14,14 +14,22 @@ import (
 )
 
 const (
-	chatsCollectionName    = "chats"
-	servedSubcollName      = "served_questions"
-	answeredSubcollName    = "answered_questions"
-	resetBatchCommitSize   = 450
-	maxAnsweredListResults = 50
+	chatsCollectionName      = "chats"
+	servedSubcollName        = "served_questions"
+	answeredSubcollName      = "answered_questions"
+	attemptsSubcollName      = "answer_attempts"
+	groupSolvesSubcollName   = "group_solves"
+	sessionsSubcollName      = "interview_sessions"
+	questionListsSubcollName = "question_lists"
+	formattedPromptsCollName = "formatted_prompts"
+	resetBatchCommitSize     = 450
+	maxAnsweredListResults   = 50
 )
 
 var ErrAnsweredQuestionNotFound = errors.New("answered question not found")
+var ErrInterviewSessionNotFound = errors.New("interview session not found")
+var ErrQuestionListNotFound = errors.New("question list not found")
+var ErrFormattedPromptNotFound = errors.New("formatted prompt not found")
 
 type Store struct {
 	client           *firestore.Client
@@ -38,37 +46,141 @@ func NewStore(client *firestore.Client, defaultDailyTime, defaultDailyTZ string)
 }
 
 type QuestionRef struct {
-	Slug       string `firestore:"slug"`
-	Title      string `firestore:"title"`
-	Difficulty string `firestore:"difficulty"`
-	URL        string `firestore:"url"`
+	Slug       string `firestore:"slug" json:"slug"`
+	Title      string `firestore:"title" json:"title"`
+	Difficulty string `firestore:"difficulty" json:"difficulty"`
+	URL        string `firestore:"url" json:"url"`
 }
 
 type AnsweredQuestion struct {
-	Slug            string    `firestore:"slug"`
-	Title           string    `firestore:"title"`
-	Difficulty      string    `firestore:"difficulty"`
-	URL             string    `firestore:"url"`
-	FirstAnsweredAt time.Time `firestore:"first_answered_at"`
-	LastAnsweredAt  time.Time `firestore:"last_answered_at"`
-	Attempts        int       `firestore:"attempts"`
+	Slug            string    `firestore:"slug" json:"slug"`
+	Title           string    `firestore:"title" json:"title"`
+	Difficulty      string    `firestore:"difficulty" json:"difficulty"`
+	URL             string    `firestore:"url" json:"url"`
+	FirstAnsweredAt time.Time `firestore:"first_answered_at" json:"first_answered_at"`
+	LastAnsweredAt  time.Time `firestore:"last_answered_at" json:"last_answered_at"`
+	Attempts        int       `firestore:"attempts" json:"attempts"`
+	EaseFactor      float64   `firestore:"ease_factor" json:"ease_factor"`
+	IntervalDays    int       `firestore:"interval_days" json:"interval_days"`
+	Repetitions     int       `firestore:"repetitions" json:"repetitions"`
+	DueAt           time.Time `firestore:"due_at" json:"due_at"`
+}
+
+// AnswerAttempt is one graded submission, kept whether or not it passed.
+type AnswerAttempt struct {
+	Slug      string    `firestore:"slug" json:"slug"`
+	Answer    string    `firestore:"answer" json:"answer"`
+	Score     int       `firestore:"score" json:"score"`
+	Source    string    `firestore:"source" json:"source"`
+	Feedback  string    `firestore:"feedback" json:"feedback"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// GroupSolve records a group member completing a question. Score is zero
+// when the question was closed with /done instead of being graded.
+type GroupSolve struct {
+	UserID     int64     `firestore:"user_id" json:"user_id"`
+	Name       string    `firestore:"name" json:"name"`
+	Slug       string    `firestore:"slug" json:"slug"`
+	Difficulty string    `firestore:"difficulty" json:"difficulty"`
+	Score      int       `firestore:"score" json:"score"`
+	SolvedAt   time.Time `firestore:"solved_at" json:"solved_at"`
 }
 
 type ChatSettings struct {
-	ChatID          int64        `firestore:"chat_id"`
-	DailyEnabled    bool         `firestore:"daily_enabled"`
-	DailyTime       string       `firestore:"daily_time"`
-	Timezone        string       `firestore:"timezone"`
-	CurrentQuestion *QuestionRef `firestore:"current_question,omitempty"`
-	LastDailySentOn string       `firestore:"last_daily_sent_on"`
-	UpdatedAt       time.Time    `firestore:"updated_at"`
+	ChatID          int64        `firestore:"chat_id" json:"chat_id"`
+	UserID          int64        `firestore:"user_id,omitempty" json:"user_id,omitempty"`
+	DailyEnabled    bool         `firestore:"daily_enabled" json:"daily_enabled"`
+	DailyTime       string       `firestore:"daily_time" json:"daily_time"`
+	Timezone        string       `firestore:"timezone" json:"timezone"`
+	CurrentQuestion *QuestionRef `firestore:"current_question,omitempty" json:"current_question,omitempty"`
+	LastDailySentOn string       `firestore:"last_daily_sent_on" json:"last_daily_sent_on"`
+	Difficulty      string       `firestore:"difficulty,omitempty" json:"difficulty,omitempty"`
+	DailyMode       string       `firestore:"daily_mode,omitempty" json:"daily_mode,omitempty"`
+	// LastWeeklySummaryOn is the ISO week ("2026-W07") of the last posted
+	// group leaderboard summary.
+	LastWeeklySummaryOn string       `firestore:"last_weekly_summary_on,omitempty" json:"last_weekly_summary_on,omitempty"`
+	Mock                *MockSession `firestore:"mock_session,omitempty" json:"mock_session,omitempty"`
+	// HintLevel counts hints shown for the question HintSlug.
+	HintLevel int    `firestore:"hint_level,omitempty" json:"hint_level,omitempty"`
+	HintSlug  string `firestore:"hint_slug,omitempty" json:"hint_slug,omitempty"`
+	// Tutor is the tutoring conversation about the current question.
+	Tutor *TutorThread `firestore:"tutor_thread,omitempty" json:"tutor_thread,omitempty"`
+	// StudyPlan names the active /plan study list; empty means the whole
+	// catalog.
+	StudyPlan string `firestore:"study_plan,omitempty" json:"study_plan,omitempty"`
+	// DismissedSlug is the group question a member left with /exit.
+	DismissedSlug string `firestore:"dismissed_slug,omitempty" json:"dismissed_slug,omitempty"`
+	// LastSolveAt is when a group member last solved a question.
+	LastSolveAt time.Time `firestore:"last_solve_at,omitempty" json:"last_solve_at,omitempty"`
+	UpdatedAt   time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// InterviewSession is a multi-question interview loop and its scorecard.
+// Current indexes the question being worked on; FinishedAt is zero while the
+// session is running.
+type InterviewSession struct {
+	ID         string            `firestore:"id" json:"id"`
+	Difficulty string            `firestore:"difficulty,omitempty" json:"difficulty,omitempty"`
+	Questions  []SessionQuestion `firestore:"questions" json:"questions"`
+	Current    int               `firestore:"current" json:"current"`
+	StartedAt  time.Time         `firestore:"started_at" json:"started_at"`
+	FinishedAt time.Time         `firestore:"finished_at" json:"finished_at"`
+}
+
+// SessionQuestion is one interview question and how it went.
+type SessionQuestion struct {
+	QuestionRef
+	Outcome   string `firestore:"outcome,omitempty" json:"outcome,omitempty"`
+	BestScore int    `firestore:"best_score" json:"best_score"`
+	Attempts  int    `firestore:"attempts" json:"attempts"`
+}
+
+// QuestionList is a chat's custom question list, served in order.
+type QuestionList struct {
+	Name      string        `firestore:"name" json:"name"`
+	Questions []QuestionRef `firestore:"questions" json:"questions"`
+	CreatedAt time.Time     `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `firestore:"updated_at" json:"updated_at"`
+}
+
+// FormattedPrompt is an AI-formatted question statement. Key is derived
+// from the slug, model and raw statement, so entries are shared by all chats.
+type FormattedPrompt struct {
+	Key       string    `firestore:"key" json:"key"`
+	Slug      string    `firestore:"slug" json:"slug"`
+	Model     string    `firestore:"model" json:"model"`
+	Text      string    `firestore:"text" json:"text"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// TutorThread is the bounded transcript of answers, reviews, hints and
+// follow-ups for the question Slug.
+type TutorThread struct {
+	Slug  string      `firestore:"slug" json:"slug"`
+	Turns []TutorTurn `firestore:"turns" json:"turns"`
+}
+
+type TutorTurn struct {
+	Kind string    `firestore:"kind" json:"kind"`
+	Text string    `firestore:"text" json:"text"`
+	At   time.Time `firestore:"at" json:"at"`
+}
+
+// MockSession is a timed mock interview on the current question.
+type MockSession struct {
+	Slug          string    `firestore:"slug" json:"slug"`
+	StartedAt     time.Time `firestore:"started_at" json:"started_at"`
+	Minutes       int       `firestore:"minutes" json:"minutes"`
+	WarnedHalfway bool      `firestore:"warned_halfway" json:"warned_halfway"`
+	WarnedFinal   bool      `firestore:"warned_final" json:"warned_final"`
 }
 
-func (s *Store) GetChatSettings(ctx context.Context, chatID int64) (ChatSettings, error) {
-	snap, err := s.chatDoc(chatID).Get(ctx)
+func (s *Store) GetChatSettings(ctx context.Context, key StateKey) (ChatSettings, error) {
+	snap, err := s.chatDoc(key).Get(ctx)
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
-			return s.defaultSettings(chatID), nil
+			return s.defaultSettings(key), nil
 		}
 		return ChatSettings{}, fmt.Errorf("get chat settings: %w", err)
 	}
@@ -79,7 +191,7 @@ func (s *Store) GetChatSettings(ctx context.Context, chatID int64) (ChatSettings
 	}
 
 	if settings.ChatID == 0 {
-		settings.ChatID = chatID
+		settings.ChatID, settings.UserID = key.ChatID, key.UserID
 	}
 	if settings.DailyTime == "" {
 		settings.DailyTime = s.defaultDailyTime
@@ -91,7 +203,7 @@ func (s *Store) GetChatSettings(ctx context.Context, chatID int64) (ChatSettings
 	return settings, nil
 }
 
-func (s *Store) UpsertDailySettings(ctx context.Context, chatID int64, enabled bool, hhmm, tz string) error {
+func (s *Store) UpsertDailySettings(ctx context.Context, key StateKey, enabled bool, hhmm, tz string) error {
 	if hhmm == "" {
 		hhmm = s.defaultDailyTime
 	}
@@ -99,8 +211,9 @@ func (s *Store) UpsertDailySettings(ctx context.Context, chatID int64, enabled b
 		tz = s.defaultDailyTZ
 	}
 
-	_, err := s.chatDoc(chatID).Set(ctx, map[string]any{
-		"chat_id":       chatID,
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":       key.ChatID,
+		"user_id":       key.UserID,
 		"daily_enabled": enabled,
 		"daily_time":    hhmm,
 		"timezone":      tz,
@@ -112,10 +225,14 @@ func (s *Store) UpsertDailySettings(ctx context.Context, chatID int64, enabled b
 	return nil
 }
 
-func (s *Store) SetCurrentQuestion(ctx context.Context, chatID int64, q QuestionRef) error {
-	_, err := s.chatDoc(chatID).Set(ctx, map[string]any{
-		"chat_id":          chatID,
+func (s *Store) SetCurrentQuestion(ctx context.Context, key StateKey, q QuestionRef) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":          key.ChatID,
+		"user_id":          key.UserID,
 		"current_question": q,
+		"hint_level":       0,
+		"hint_slug":        firestore.Delete,
+		"tutor_thread":     firestore.Delete,
 		"updated_at":       firestore.ServerTimestamp,
 	}, firestore.MergeAll)
 	if err != nil {
@@ -124,10 +241,14 @@ func (s *Store) SetCurrentQuestion(ctx context.Context, chatID int64, q Question
 	return nil
 }
 
-func (s *Store) ClearCurrentQuestion(ctx context.Context, chatID int64) error {
-	_, err := s.chatDoc(chatID).Set(ctx, map[string]any{
-		"chat_id":          chatID,
+func (s *Store) ClearCurrentQuestion(ctx context.Context, key StateKey) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":          key.ChatID,
+		"user_id":          key.UserID,
 		"current_question": firestore.Delete,
+		"hint_level":       0,
+		"hint_slug":        firestore.Delete,
+		"tutor_thread":     firestore.Delete,
 		"updated_at":       firestore.ServerTimestamp,
 	}, firestore.MergeAll)
 	if err != nil {
@@ -136,9 +257,63 @@ func (s *Store) ClearCurrentQuestion(ctx context.Context, chatID int64) error {
 	return nil
 }
 
-func (s *Store) MarkDailySent(ctx context.Context, chatID int64, day string) error {
-	_, err := s.chatDoc(chatID).Set(ctx, map[string]any{
-		"chat_id":            chatID,
+func (s *Store) SetHintLevel(ctx context.Context, key StateKey, slug string, level int) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":    key.ChatID,
+		"user_id":    key.UserID,
+		"hint_level": level,
+		"hint_slug":  slug,
+		"updated_at": firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("set hint level: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) SetTutorThread(ctx context.Context, key StateKey, thread TutorThread) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":      key.ChatID,
+		"user_id":      key.UserID,
+		"tutor_thread": thread,
+		"updated_at":   firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("set tutor thread: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) SetMockSession(ctx context.Context, key StateKey, session MockSession) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":      key.ChatID,
+		"user_id":      key.UserID,
+		"mock_session": session,
+		"updated_at":   firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("set mock session: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ClearMockSession(ctx context.Context, key StateKey) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":      key.ChatID,
+		"user_id":      key.UserID,
+		"mock_session": firestore.Delete,
+		"updated_at":   firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("clear mock session: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) MarkDailySent(ctx context.Context, key StateKey, day string) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":            key.ChatID,
+		"user_id":            key.UserID,
 		"last_daily_sent_on": day,
 		"updated_at":         firestore.ServerTimestamp,
 	}, firestore.MergeAll)
@@ -148,12 +323,77 @@ func (s *Store) MarkDailySent(ctx context.Context, chatID int64, day string) err
 	return nil
 }
 
-func (s *Store) MarkQuestionAnswered(ctx context.Context, chatID int64, q QuestionRef) error {
+func (s *Store) SetDifficultyPreference(ctx context.Context, key StateKey, difficulty string) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":    key.ChatID,
+		"user_id":    key.UserID,
+		"difficulty": difficulty,
+		"updated_at": firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("set difficulty preference: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) SetDailyMode(ctx context.Context, key StateKey, mode string) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":    key.ChatID,
+		"user_id":    key.UserID,
+		"daily_mode": mode,
+		"updated_at": firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("set daily mode: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) SetStudyPlan(ctx context.Context, key StateKey, name string) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":    key.ChatID,
+		"user_id":    key.UserID,
+		"study_plan": name,
+		"updated_at": firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("set study plan: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) DismissQuestion(ctx context.Context, key StateKey, slug string) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":        key.ChatID,
+		"user_id":        key.UserID,
+		"dismissed_slug": slug,
+		"updated_at":     firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("dismiss question: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) MarkWeeklySummarySent(ctx context.Context, key StateKey, week string) error {
+	_, err := s.chatDoc(key).Set(ctx, map[string]any{
+		"chat_id":                key.ChatID,
+		"user_id":                key.UserID,
+		"last_weekly_summary_on": week,
+		"updated_at":             firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("mark weekly summary sent: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) MarkQuestionAnswered(ctx context.Context, key StateKey, q QuestionRef) error {
 	if q.Slug == "" {
 		return fmt.Errorf("mark question answered: slug is empty")
 	}
 
-	ref := s.chatDoc(chatID).Collection(answeredSubcollName).Doc(q.Slug)
+	ref := s.chatDoc(key).Collection(answeredSubcollName).Doc(q.Slug)
 	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
 		_, err := tx.Get(ref)
 		if status.Code(err) == codes.NotFound {
@@ -186,7 +426,7 @@ func (s *Store) MarkQuestionAnswered(ctx context.Context, chatID int64, q Questi
 	return nil
 }
 
-func (s *Store) ListAnsweredQuestions(ctx context.Context, chatID int64, limit int) ([]AnsweredQuestion, error) {
+func (s *Store) ListAnsweredQuestions(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -194,7 +434,7 @@ func (s *Store) ListAnsweredQuestions(ctx context.Context, chatID int64, limit i
 		limit = maxAnsweredListResults
 	}
 
-	iter := s.chatDoc(chatID).Collection(answeredSubcollName).
+	iter := s.chatDoc(key).Collection(answeredSubcollName).
 		OrderBy("last_answered_at", firestore.Desc).
 		Limit(limit).
 		Documents(ctx)
@@ -220,14 +460,89 @@ func (s *Store) ListAnsweredQuestions(ctx context.Context, chatID int64, limit i
 		if item.Attempts == 0 {
 			item.Attempts = 1
 		}
-		out = append(out, item)
+		out = append(out, withReviewDefaults(item))
+	}
+
+	return out, nil
+}
+
+func (s *Store) ListReviewQueue(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error) {
+	// Documents written before review scheduling have no due_at to order by,
+	// so sort the full history in memory.
+	items, err := s.ListAllAnsweredQuestions(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("list review queue: %w", err)
+	}
+	return sortReviewQueue(items, limit), nil
+}
+
+// ListAllAnsweredQuestions returns the whole answered history, newest first.
+func (s *Store) ListAllAnsweredQuestions(ctx context.Context, key StateKey) ([]AnsweredQuestion, error) {
+	iter := s.chatDoc(key).Collection(answeredSubcollName).
+		OrderBy("last_answered_at", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	out := make([]AnsweredQuestion, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list all answered questions: %w", err)
+		}
+
+		var item AnsweredQuestion
+		if err := doc.DataTo(&item); err != nil {
+			return nil, fmt.Errorf("decode answered question: %w", err)
+		}
+		if item.Slug == "" {
+			item.Slug = doc.Ref.ID
+		}
+		if item.Attempts == 0 {
+			item.Attempts = 1
+		}
+		out = append(out, withReviewDefaults(item))
 	}
 
 	return out, nil
 }
 
-func (s *Store) GetAnsweredQuestion(ctx context.Context, chatID int64, slug string) (QuestionRef, error) {
-	snap, err := s.chatDoc(chatID).Collection(answeredSubcollName).Doc(slug).Get(ctx)
+func (s *Store) GetReviewState(ctx context.Context, key StateKey, slug string) (ReviewState, error) {
+	snap, err := s.chatDoc(key).Collection(answeredSubcollName).Doc(slug).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return ReviewState{}, ErrAnsweredQuestionNotFound
+		}
+		return ReviewState{}, fmt.Errorf("get review state: %w", err)
+	}
+
+	var item AnsweredQuestion
+	if err := snap.DataTo(&item); err != nil {
+		return ReviewState{}, fmt.Errorf("decode answered question: %w", err)
+	}
+	return withReviewDefaults(item).reviewState(), nil
+}
+
+func (s *Store) UpdateReviewState(ctx context.Context, key StateKey, slug string, state ReviewState) error {
+	_, err := s.chatDoc(key).Collection(answeredSubcollName).Doc(slug).Update(ctx, []firestore.Update{
+		{Path: "ease_factor", Value: state.EaseFactor},
+		{Path: "interval_days", Value: state.IntervalDays},
+		{Path: "repetitions", Value: state.Repetitions},
+		{Path: "due_at", Value: state.DueAt.UTC()},
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return ErrAnsweredQuestionNotFound
+		}
+		return fmt.Errorf("update review state: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetAnsweredQuestion(ctx context.Context, key StateKey, slug string) (QuestionRef, error) {
+	snap, err := s.chatDoc(key).Collection(answeredSubcollName).Doc(slug).Get(ctx)
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
 			return QuestionRef{}, ErrAnsweredQuestionNotFound
@@ -245,8 +560,8 @@ func (s *Store) GetAnsweredQuestion(ctx context.Context, chatID int64, slug stri
 	return q, nil
 }
 
-func (s *Store) DeleteAnsweredQuestion(ctx context.Context, chatID int64, slug string) error {
-	ref := s.chatDoc(chatID).Collection(answeredSubcollName).Doc(slug)
+func (s *Store) DeleteAnsweredQuestion(ctx context.Context, key StateKey, slug string) error {
+	ref := s.chatDoc(key).Collection(answeredSubcollName).Doc(slug)
 	if _, err := ref.Get(ctx); err != nil {
 		if status.Code(err) == codes.NotFound {
 			return ErrAnsweredQuestionNotFound
@@ -260,8 +575,218 @@ func (s *Store) DeleteAnsweredQuestion(ctx context.Context, chatID int64, slug s
 	return nil
 }
 
-func (s *Store) AddServedQuestion(ctx context.Context, chatID int64, q QuestionRef) error {
-	_, err := s.chatDoc(chatID).Collection(servedSubcollName).Doc(q.Slug).Set(ctx, map[string]any{
+func (s *Store) RecordAnswerAttempt(ctx context.Context, key StateKey, attempt AnswerAttempt) error {
+	if attempt.Slug == "" {
+		return fmt.Errorf("record answer attempt: slug is empty")
+	}
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = time.Now()
+	}
+	attempt.CreatedAt = attempt.CreatedAt.UTC()
+
+	if _, _, err := s.chatDoc(key).Collection(attemptsSubcollName).Add(ctx, attempt); err != nil {
+		return fmt.Errorf("record answer attempt: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListAnswerAttempts(ctx context.Context, key StateKey, slug string, limit int) ([]AnswerAttempt, error) {
+	// Filtering by slug and ordering by time would need a composite index, so
+	// slug-filtered results are ordered in memory instead.
+	query := s.chatDoc(key).Collection(attemptsSubcollName).Query
+	if slug != "" {
+		query = query.Where("slug", "==", slug)
+	} else {
+		query = query.OrderBy("created_at", firestore.Desc)
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	out := make([]AnswerAttempt, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list answer attempts: %w", err)
+		}
+
+		var item AnswerAttempt
+		if err := doc.DataTo(&item); err != nil {
+			return nil, fmt.Errorf("decode answer attempt: %w", err)
+		}
+		out = append(out, item)
+	}
+
+	return sortAnswerAttempts(out, limit), nil
+}
+
+func (s *Store) RecordGroupSolve(ctx context.Context, chatID int64, solve GroupSolve) error {
+	if solve.Slug == "" {
+		return fmt.Errorf("record group solve: slug is empty")
+	}
+	if solve.SolvedAt.IsZero() {
+		solve.SolvedAt = time.Now()
+	}
+	solve.SolvedAt = solve.SolvedAt.UTC()
+
+	chat := s.chatDoc(ChatKey(chatID))
+	if _, _, err := chat.Collection(groupSolvesSubcollName).Add(ctx, solve); err != nil {
+		return fmt.Errorf("record group solve: %w", err)
+	}
+	_, err := chat.Set(ctx, map[string]any{
+		"chat_id":       chatID,
+		"user_id":       int64(0),
+		"last_solve_at": solve.SolvedAt,
+		"updated_at":    firestore.ServerTimestamp,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("record group solve time: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ListGroupSolves(ctx context.Context, chatID int64, since time.Time) ([]GroupSolve, error) {
+	iter := s.chatDoc(ChatKey(chatID)).Collection(groupSolvesSubcollName).
+		Where("solved_at", ">=", since.UTC()).
+		OrderBy("solved_at", firestore.Asc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	out := make([]GroupSolve, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list group solves: %w", err)
+		}
+
+		var item GroupSolve
+		if err := doc.DataTo(&item); err != nil {
+			return nil, fmt.Errorf("decode group solve: %w", err)
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func (s *Store) SaveInterviewSession(ctx context.Context, key StateKey, session InterviewSession) error {
+	if session.ID == "" {
+		return fmt.Errorf("save interview session: id is empty")
+	}
+	session.StartedAt = session.StartedAt.UTC()
+	session.FinishedAt = session.FinishedAt.UTC()
+	if _, err := s.chatDoc(key).Collection(sessionsSubcollName).Doc(session.ID).Set(ctx, session); err != nil {
+		return fmt.Errorf("save interview session: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) LatestInterviewSession(ctx context.Context, key StateKey) (InterviewSession, error) {
+	iter := s.chatDoc(key).Collection(sessionsSubcollName).
+		OrderBy("started_at", firestore.Desc).
+		Limit(1).
+		Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return InterviewSession{}, ErrInterviewSessionNotFound
+	}
+	if err != nil {
+		return InterviewSession{}, fmt.Errorf("latest interview session: %w", err)
+	}
+	var session InterviewSession
+	if err := doc.DataTo(&session); err != nil {
+		return InterviewSession{}, fmt.Errorf("decode interview session: %w", err)
+	}
+	return session, nil
+}
+
+func (s *Store) SaveQuestionList(ctx context.Context, chatID int64, list QuestionList) error {
+	if list.Name == "" {
+		return fmt.Errorf("save question list: name is empty")
+	}
+	list.CreatedAt = list.CreatedAt.UTC()
+	list.UpdatedAt = list.UpdatedAt.UTC()
+	if _, err := s.chatDoc(ChatKey(chatID)).Collection(questionListsSubcollName).Doc(list.Name).Set(ctx, list); err != nil {
+		return fmt.Errorf("save question list: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetQuestionList(ctx context.Context, chatID int64, name string) (QuestionList, error) {
+	doc, err := s.chatDoc(ChatKey(chatID)).Collection(questionListsSubcollName).Doc(name).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return QuestionList{}, ErrQuestionListNotFound
+	}
+	if err != nil {
+		return QuestionList{}, fmt.Errorf("get question list: %w", err)
+	}
+	var list QuestionList
+	if err := doc.DataTo(&list); err != nil {
+		return QuestionList{}, fmt.Errorf("decode question list: %w", err)
+	}
+	return list, nil
+}
+
+func (s *Store) ListQuestionLists(ctx context.Context, chatID int64) ([]QuestionList, error) {
+	iter := s.chatDoc(ChatKey(chatID)).Collection(questionListsSubcollName).OrderBy("name", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+
+	out := make([]QuestionList, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list question lists: %w", err)
+		}
+		var item QuestionList
+		if err := doc.DataTo(&item); err != nil {
+			return nil, fmt.Errorf("decode question list: %w", err)
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func (s *Store) SaveFormattedPrompt(ctx context.Context, prompt FormattedPrompt) error {
+	if prompt.Key == "" {
+		return fmt.Errorf("save formatted prompt: key is empty")
+	}
+	prompt.CreatedAt = prompt.CreatedAt.UTC()
+	if _, err := s.client.Collection(formattedPromptsCollName).Doc(prompt.Key).Set(ctx, prompt); err != nil {
+		return fmt.Errorf("save formatted prompt: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetFormattedPrompt(ctx context.Context, key string) (FormattedPrompt, error) {
+	doc, err := s.client.Collection(formattedPromptsCollName).Doc(key).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return FormattedPrompt{}, ErrFormattedPromptNotFound
+	}
+	if err != nil {
+		return FormattedPrompt{}, fmt.Errorf("get formatted prompt: %w", err)
+	}
+	var prompt FormattedPrompt
+	if err := doc.DataTo(&prompt); err != nil {
+		return FormattedPrompt{}, fmt.Errorf("decode formatted prompt: %w", err)
+	}
+	return prompt, nil
+}
+
+func (s *Store) AddServedQuestion(ctx context.Context, key StateKey, q QuestionRef) error {
+	_, err := s.chatDoc(key).Collection(servedSubcollName).Doc(q.Slug).Set(ctx, map[string]any{
 		"slug":       q.Slug,
 		"title":      q.Title,
 		"difficulty": q.Difficulty,
@@ -274,16 +799,16 @@ func (s *Store) AddServedQuestion(ctx context.Context, chatID int64, q QuestionR
 	return nil
 }
 
-func (s *Store) RemoveServedQuestion(ctx context.Context, chatID int64, slug string) error {
-	_, err := s.chatDoc(chatID).Collection(servedSubcollName).Doc(slug).Delete(ctx)
+func (s *Store) RemoveServedQuestion(ctx context.Context, key StateKey, slug string) error {
+	_, err := s.chatDoc(key).Collection(servedSubcollName).Doc(slug).Delete(ctx)
 	if err != nil && status.Code(err) != codes.NotFound {
 		return fmt.Errorf("remove served question: %w", err)
 	}
 	return nil
 }
 
-func (s *Store) SeenQuestionSet(ctx context.Context, chatID int64) (map[string]struct{}, error) {
-	iter := s.chatDoc(chatID).Collection(servedSubcollName).Documents(ctx)
+func (s *Store) SeenQuestionSet(ctx context.Context, key StateKey) (map[string]struct{}, error) {
+	iter := s.chatDoc(key).Collection(servedSubcollName).Documents(ctx)
 	defer iter.Stop()
 
 	seen := make(map[string]struct{})
@@ -301,8 +826,8 @@ func (s *Store) SeenQuestionSet(ctx context.Context, chatID int64) (map[string]s
 	return seen, nil
 }
 
-func (s *Store) ResetServedQuestions(ctx context.Context, chatID int64) error {
-	iter := s.chatDoc(chatID).Collection(servedSubcollName).Documents(ctx)
+func (s *Store) ResetServedQuestions(ctx context.Context, key StateKey) error {
+	iter := s.chatDoc(key).Collection(servedSubcollName).Documents(ctx)
 	defer iter.Stop()
 
 	batch := s.client.Batch()
@@ -362,7 +887,7 @@ func (s *Store) ListDailyEnabledChats(ctx context.Context) ([]ChatSettings, erro
 				item.ChatID = parsed
 			}
 		}
-		if item.ChatID == 0 {
+		if item.ChatID == 0 || item.UserID != 0 {
 			continue
 		}
 		if item.DailyTime == "" {
@@ -374,16 +899,78 @@ func (s *Store) ListDailyEnabledChats(ctx context.Context) ([]ChatSettings, erro
 		out = append(out, item)
 	}
 
+	sortChatSettings(out)
+	return out, nil
+}
+
+func (s *Store) ListActiveGroups(ctx context.Context, since time.Time) ([]ChatSettings, error) {
+	iter := s.client.Collection(chatsCollectionName).Where("last_solve_at", ">=", since.UTC()).Documents(ctx)
+	defer iter.Stop()
+
+	out := make([]ChatSettings, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("query active groups: %w", err)
+		}
+
+		var item ChatSettings
+		if err := doc.DataTo(&item); err != nil {
+			return nil, fmt.Errorf("decode active group: %w", err)
+		}
+		if item.ChatID == 0 || item.UserID != 0 {
+			continue
+		}
+		if item.DailyTime == "" {
+			item.DailyTime = s.defaultDailyTime
+		}
+		if item.Timezone == "" {
+			item.Timezone = s.defaultDailyTZ
+		}
+		out = append(out, item)
+	}
+	sortChatSettings(out)
+	return out, nil
+}
+
+func (s *Store) ListMockSessions(ctx context.Context) ([]ChatSettings, error) {
+	iter := s.client.Collection(chatsCollectionName).Where("mock_session.minutes", ">", 0).Documents(ctx)
+	defer iter.Stop()
+
+	out := make([]ChatSettings, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("query mock sessions: %w", err)
+		}
+
+		var item ChatSettings
+		if err := doc.DataTo(&item); err != nil {
+			return nil, fmt.Errorf("decode mock session chat: %w", err)
+		}
+		if item.ChatID == 0 || item.Mock == nil {
+			continue
+		}
+		out = append(out, item)
+	}
+	sortChatSettings(out)
 	return out, nil
 }
 
-func (s *Store) chatDoc(chatID int64) *firestore.DocumentRef {
-	return s.client.Collection(chatsCollectionName).Doc(strconv.FormatInt(chatID, 10))
+func (s *Store) chatDoc(key StateKey) *firestore.DocumentRef {
+	return s.client.Collection(chatsCollectionName).Doc(key.String())
 }
 
-func (s *Store) defaultSettings(chatID int64) ChatSettings {
+func (s *Store) defaultSettings(key StateKey) ChatSettings {
 	return ChatSettings{
-		ChatID:       chatID,
+		ChatID:       key.ChatID,
+		UserID:       key.UserID,
 		DailyEnabled: false,
 		DailyTime:    s.defaultDailyTime,
 		Timezone:     s.defaultDailyTZ,
@@ -0,0 +1,26 @@
+package storage
+
+import "strconv"
+
+// StateKey addresses one learner's state. UserID is zero for private chats
+// and for a group as a whole; each member of a group gets their own key.
+type StateKey struct {
+	ChatID int64
+	UserID int64
+}
+
+// ChatKey returns the chat-wide key for chatID.
+func ChatKey(chatID int64) StateKey {
+	return StateKey{ChatID: chatID}
+}
+
+// String is the document ID every backend stores the key under. Chat-wide
+// keys keep the bare chat ID so data written before group support still
+// resolves.
+func (k StateKey) String() string {
+	id := strconv.FormatInt(k.ChatID, 10)
+	if k.UserID == 0 {
+		return id
+	}
+	return id + ":" + strconv.FormatInt(k.UserID, 10)
+}
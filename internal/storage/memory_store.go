@@ -0,0 +1,576 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps all chat state in process memory. It mirrors the
+// Firestore Store semantics and is intended for local runs and tests.
+type MemoryStore struct {
+	defaultDailyTime string
+	defaultDailyTZ   string
+	nowFn            func() time.Time
+
+	mu       sync.RWMutex
+	chats    map[StateKey]ChatSettings
+	served   map[StateKey]map[string]QuestionRef
+	answered map[StateKey]map[string]AnsweredQuestion
+	attempts map[StateKey][]AnswerAttempt
+	solves   map[int64][]GroupSolve
+	sessions map[StateKey]map[string]InterviewSession
+	lists    map[int64]map[string]QuestionList
+	prompts  map[string]FormattedPrompt
+}
+
+func NewMemoryStore(defaultDailyTime, defaultDailyTZ string) *MemoryStore {
+	return &MemoryStore{
+		defaultDailyTime: defaultDailyTime,
+		defaultDailyTZ:   defaultDailyTZ,
+		nowFn:            time.Now,
+		chats:            make(map[StateKey]ChatSettings),
+		served:           make(map[StateKey]map[string]QuestionRef),
+		answered:         make(map[StateKey]map[string]AnsweredQuestion),
+		attempts:         make(map[StateKey][]AnswerAttempt),
+		solves:           make(map[int64][]GroupSolve),
+		sessions:         make(map[StateKey]map[string]InterviewSession),
+		lists:            make(map[int64]map[string]QuestionList),
+		prompts:          make(map[string]FormattedPrompt),
+	}
+}
+
+func (s *MemoryStore) GetChatSettings(_ context.Context, key StateKey) (ChatSettings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, ok := s.chats[key]
+	if !ok {
+		return s.defaultSettings(key), nil
+	}
+	return s.withDefaults(cloneChatSettings(item)), nil
+}
+
+func (s *MemoryStore) UpsertDailySettings(_ context.Context, key StateKey, enabled bool, hhmm, tz string) error {
+	if hhmm == "" {
+		hhmm = s.defaultDailyTime
+	}
+	if tz == "" {
+		tz = s.defaultDailyTZ
+	}
+
+	s.updateChat(key, func(item *ChatSettings) {
+		item.DailyEnabled = enabled
+		item.DailyTime = hhmm
+		item.Timezone = tz
+	})
+	return nil
+}
+
+func (s *MemoryStore) SetCurrentQuestion(_ context.Context, key StateKey, q QuestionRef) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		qCopy := q
+		item.CurrentQuestion = &qCopy
+		item.HintLevel = 0
+		item.HintSlug = ""
+		item.Tutor = nil
+	})
+	return nil
+}
+
+func (s *MemoryStore) ClearCurrentQuestion(_ context.Context, key StateKey) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.CurrentQuestion = nil
+		item.HintLevel = 0
+		item.HintSlug = ""
+		item.Tutor = nil
+	})
+	return nil
+}
+
+func (s *MemoryStore) SetHintLevel(_ context.Context, key StateKey, slug string, level int) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.HintLevel = level
+		item.HintSlug = slug
+	})
+	return nil
+}
+
+func (s *MemoryStore) SetTutorThread(_ context.Context, key StateKey, thread TutorThread) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.Tutor = &TutorThread{Slug: thread.Slug, Turns: append([]TutorTurn(nil), thread.Turns...)}
+	})
+	return nil
+}
+
+func (s *MemoryStore) SetMockSession(_ context.Context, key StateKey, session MockSession) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.Mock = &session
+	})
+	return nil
+}
+
+func (s *MemoryStore) ClearMockSession(_ context.Context, key StateKey) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.Mock = nil
+	})
+	return nil
+}
+
+func (s *MemoryStore) MarkDailySent(_ context.Context, key StateKey, day string) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.LastDailySentOn = day
+	})
+	return nil
+}
+
+func (s *MemoryStore) MarkWeeklySummarySent(_ context.Context, key StateKey, week string) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.LastWeeklySummaryOn = week
+	})
+	return nil
+}
+
+func (s *MemoryStore) SetDifficultyPreference(_ context.Context, key StateKey, difficulty string) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.Difficulty = difficulty
+	})
+	return nil
+}
+
+func (s *MemoryStore) SetDailyMode(_ context.Context, key StateKey, mode string) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.DailyMode = mode
+	})
+	return nil
+}
+
+func (s *MemoryStore) SetStudyPlan(_ context.Context, key StateKey, name string) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.StudyPlan = name
+	})
+	return nil
+}
+
+func (s *MemoryStore) DismissQuestion(_ context.Context, key StateKey, slug string) error {
+	s.updateChat(key, func(item *ChatSettings) {
+		item.DismissedSlug = slug
+	})
+	return nil
+}
+
+func (s *MemoryStore) MarkQuestionAnswered(_ context.Context, key StateKey, q QuestionRef) error {
+	if q.Slug == "" {
+		return fmt.Errorf("mark question answered: slug is empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, ok := s.answered[key]
+	if !ok {
+		items = make(map[string]AnsweredQuestion)
+		s.answered[key] = items
+	}
+
+	now := s.nowFn().UTC()
+	item, exists := items[q.Slug]
+	if !exists {
+		item = AnsweredQuestion{FirstAnsweredAt: now}
+	}
+	item.Slug = q.Slug
+	item.Title = q.Title
+	item.Difficulty = q.Difficulty
+	item.URL = q.URL
+	item.Attempts++
+	item.LastAnsweredAt = now
+	items[q.Slug] = item
+	return nil
+}
+
+func (s *MemoryStore) ListAnsweredQuestions(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error) {
+	out, err := s.ListAllAnsweredQuestions(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if limit = clampAnsweredLimit(limit); len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// ListAllAnsweredQuestions returns the whole answered history, newest first.
+func (s *MemoryStore) ListAllAnsweredQuestions(_ context.Context, key StateKey) ([]AnsweredQuestion, error) {
+	s.mu.RLock()
+	out := make([]AnsweredQuestion, 0, len(s.answered[key]))
+	for _, item := range s.answered[key] {
+		out = append(out, withReviewDefaults(item))
+	}
+	s.mu.RUnlock()
+
+	sortAnsweredByRecency(out)
+	return out, nil
+}
+
+func (s *MemoryStore) ListReviewQueue(ctx context.Context, key StateKey, limit int) ([]AnsweredQuestion, error) {
+	out, err := s.ListAllAnsweredQuestions(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return sortReviewQueue(out, limit), nil
+}
+
+func (s *MemoryStore) GetReviewState(_ context.Context, key StateKey, slug string) (ReviewState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, ok := s.answered[key][slug]
+	if !ok {
+		return ReviewState{}, ErrAnsweredQuestionNotFound
+	}
+	return withReviewDefaults(item).reviewState(), nil
+}
+
+func (s *MemoryStore) UpdateReviewState(_ context.Context, key StateKey, slug string, state ReviewState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.answered[key][slug]
+	if !ok {
+		return ErrAnsweredQuestionNotFound
+	}
+	item.setReviewState(state)
+	s.answered[key][slug] = item
+	return nil
+}
+
+func (s *MemoryStore) GetAnsweredQuestion(_ context.Context, key StateKey, slug string) (QuestionRef, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, ok := s.answered[key][slug]
+	if !ok {
+		return QuestionRef{}, ErrAnsweredQuestionNotFound
+	}
+	return QuestionRef{
+		Slug:       item.Slug,
+		Title:      item.Title,
+		Difficulty: item.Difficulty,
+		URL:        item.URL,
+	}, nil
+}
+
+func (s *MemoryStore) DeleteAnsweredQuestion(_ context.Context, key StateKey, slug string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.answered[key][slug]; !ok {
+		return ErrAnsweredQuestionNotFound
+	}
+	delete(s.answered[key], slug)
+	return nil
+}
+
+func (s *MemoryStore) RecordAnswerAttempt(_ context.Context, key StateKey, attempt AnswerAttempt) error {
+	if attempt.Slug == "" {
+		return fmt.Errorf("record answer attempt: slug is empty")
+	}
+	if attempt.CreatedAt.IsZero() {
+		attempt.CreatedAt = s.nowFn()
+	}
+	attempt.CreatedAt = attempt.CreatedAt.UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts[key] = append(s.attempts[key], attempt)
+	return nil
+}
+
+func (s *MemoryStore) ListAnswerAttempts(_ context.Context, key StateKey, slug string, limit int) ([]AnswerAttempt, error) {
+	s.mu.RLock()
+	out := make([]AnswerAttempt, 0, len(s.attempts[key]))
+	for _, item := range s.attempts[key] {
+		if slug == "" || item.Slug == slug {
+			out = append(out, item)
+		}
+	}
+	s.mu.RUnlock()
+
+	return sortAnswerAttempts(out, limit), nil
+}
+
+func (s *MemoryStore) RecordGroupSolve(_ context.Context, chatID int64, solve GroupSolve) error {
+	if solve.Slug == "" {
+		return fmt.Errorf("record group solve: slug is empty")
+	}
+	if solve.SolvedAt.IsZero() {
+		solve.SolvedAt = s.nowFn()
+	}
+	solve.SolvedAt = solve.SolvedAt.UTC()
+
+	s.mu.Lock()
+	s.solves[chatID] = append(s.solves[chatID], solve)
+	s.mu.Unlock()
+
+	s.updateChat(ChatKey(chatID), func(item *ChatSettings) {
+		if solve.SolvedAt.After(item.LastSolveAt) {
+			item.LastSolveAt = solve.SolvedAt
+		}
+	})
+	return nil
+}
+
+func (s *MemoryStore) ListGroupSolves(_ context.Context, chatID int64, since time.Time) ([]GroupSolve, error) {
+	s.mu.RLock()
+	out := make([]GroupSolve, 0, len(s.solves[chatID]))
+	for _, item := range s.solves[chatID] {
+		if !item.SolvedAt.Before(since) {
+			out = append(out, item)
+		}
+	}
+	s.mu.RUnlock()
+
+	return sortGroupSolves(out), nil
+}
+
+func (s *MemoryStore) SaveInterviewSession(_ context.Context, key StateKey, session InterviewSession) error {
+	if session.ID == "" {
+		return fmt.Errorf("save interview session: id is empty")
+	}
+	session.StartedAt = session.StartedAt.UTC()
+	session.FinishedAt = session.FinishedAt.UTC()
+	session.Questions = append([]SessionQuestion(nil), session.Questions...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, ok := s.sessions[key]
+	if !ok {
+		items = make(map[string]InterviewSession)
+		s.sessions[key] = items
+	}
+	items[session.ID] = session
+	return nil
+}
+
+func (s *MemoryStore) LatestInterviewSession(_ context.Context, key StateKey) (InterviewSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var (
+		latest InterviewSession
+		found  bool
+	)
+	for _, item := range s.sessions[key] {
+		if !found || item.StartedAt.After(latest.StartedAt) {
+			latest, found = item, true
+		}
+	}
+	if !found {
+		return InterviewSession{}, ErrInterviewSessionNotFound
+	}
+	latest.Questions = append([]SessionQuestion(nil), latest.Questions...)
+	return latest, nil
+}
+
+func (s *MemoryStore) SaveQuestionList(_ context.Context, chatID int64, list QuestionList) error {
+	if list.Name == "" {
+		return fmt.Errorf("save question list: name is empty")
+	}
+	list.CreatedAt = list.CreatedAt.UTC()
+	list.UpdatedAt = list.UpdatedAt.UTC()
+	list.Questions = append([]QuestionRef(nil), list.Questions...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, ok := s.lists[chatID]
+	if !ok {
+		items = make(map[string]QuestionList)
+		s.lists[chatID] = items
+	}
+	items[list.Name] = list
+	return nil
+}
+
+func (s *MemoryStore) GetQuestionList(_ context.Context, chatID int64, name string) (QuestionList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list, ok := s.lists[chatID][name]
+	if !ok {
+		return QuestionList{}, ErrQuestionListNotFound
+	}
+	list.Questions = append([]QuestionRef(nil), list.Questions...)
+	return list, nil
+}
+
+func (s *MemoryStore) ListQuestionLists(_ context.Context, chatID int64) ([]QuestionList, error) {
+	s.mu.RLock()
+	out := make([]QuestionList, 0, len(s.lists[chatID]))
+	for _, list := range s.lists[chatID] {
+		list.Questions = append([]QuestionRef(nil), list.Questions...)
+		out = append(out, list)
+	}
+	s.mu.RUnlock()
+
+	return sortQuestionLists(out), nil
+}
+
+func (s *MemoryStore) SaveFormattedPrompt(_ context.Context, prompt FormattedPrompt) error {
+	if prompt.Key == "" {
+		return fmt.Errorf("save formatted prompt: key is empty")
+	}
+	prompt.CreatedAt = prompt.CreatedAt.UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prompts[prompt.Key] = prompt
+	return nil
+}
+
+func (s *MemoryStore) GetFormattedPrompt(_ context.Context, key string) (FormattedPrompt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prompt, ok := s.prompts[key]
+	if !ok {
+		return FormattedPrompt{}, ErrFormattedPromptNotFound
+	}
+	return prompt, nil
+}
+
+func (s *MemoryStore) AddServedQuestion(_ context.Context, key StateKey, q QuestionRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, ok := s.served[key]
+	if !ok {
+		items = make(map[string]QuestionRef)
+		s.served[key] = items
+	}
+	items[q.Slug] = q
+	return nil
+}
+
+func (s *MemoryStore) RemoveServedQuestion(_ context.Context, key StateKey, slug string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.served[key], slug)
+	return nil
+}
+
+func (s *MemoryStore) SeenQuestionSet(_ context.Context, key StateKey) (map[string]struct{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]struct{}, len(s.served[key]))
+	for slug := range s.served[key] {
+		seen[slug] = struct{}{}
+	}
+	return seen, nil
+}
+
+func (s *MemoryStore) ResetServedQuestions(_ context.Context, key StateKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.served, key)
+	return nil
+}
+
+func (s *MemoryStore) ListDailyEnabledChats(_ context.Context) ([]ChatSettings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]ChatSettings, 0, len(s.chats))
+	for _, item := range s.chats {
+		if !item.DailyEnabled || item.ChatID == 0 || item.UserID != 0 {
+			continue
+		}
+		out = append(out, s.withDefaults(cloneChatSettings(item)))
+	}
+	sortChatSettings(out)
+	return out, nil
+}
+
+func (s *MemoryStore) ListActiveGroups(_ context.Context, since time.Time) ([]ChatSettings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]ChatSettings, 0)
+	for _, item := range s.chats {
+		if item.ChatID == 0 || item.UserID != 0 || item.LastSolveAt.IsZero() || item.LastSolveAt.Before(since) {
+			continue
+		}
+		out = append(out, s.withDefaults(cloneChatSettings(item)))
+	}
+	sortChatSettings(out)
+	return out, nil
+}
+
+func (s *MemoryStore) ListMockSessions(_ context.Context) ([]ChatSettings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]ChatSettings, 0)
+	for _, item := range s.chats {
+		if item.Mock != nil {
+			out = append(out, s.withDefaults(cloneChatSettings(item)))
+		}
+	}
+	sortChatSettings(out)
+	return out, nil
+}
+
+func (s *MemoryStore) updateChat(key StateKey, mutate func(item *ChatSettings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.chats[key]
+	if !ok {
+		item = ChatSettings{ChatID: key.ChatID, UserID: key.UserID}
+	}
+	mutate(&item)
+	item.UpdatedAt = s.nowFn().UTC()
+	s.chats[key] = item
+}
+
+func (s *MemoryStore) withDefaults(item ChatSettings) ChatSettings {
+	if item.DailyTime == "" {
+		item.DailyTime = s.defaultDailyTime
+	}
+	if item.Timezone == "" {
+		item.Timezone = s.defaultDailyTZ
+	}
+	return item
+}
+
+func (s *MemoryStore) defaultSettings(key StateKey) ChatSettings {
+	return ChatSettings{
+		ChatID:       key.ChatID,
+		UserID:       key.UserID,
+		DailyEnabled: false,
+		DailyTime:    s.defaultDailyTime,
+		Timezone:     s.defaultDailyTZ,
+	}
+}
+
+func cloneChatSettings(in ChatSettings) ChatSettings {
+	out := in
+	if in.CurrentQuestion != nil {
+		q := *in.CurrentQuestion
+		out.CurrentQuestion = &q
+	}
+	if in.Mock != nil {
+		mock := *in.Mock
+		out.Mock = &mock
+	}
+	if in.Tutor != nil {
+		out.Tutor = &TutorThread{Slug: in.Tutor.Slug, Turns: append([]TutorTurn(nil), in.Tutor.Turns...)}
+	}
+	return out
+}
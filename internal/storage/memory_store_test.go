@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreAppliesDefaultsToPartialChats(t *testing.T) {
+	store := NewMemoryStore("20:00", "Asia/Singapore")
+	ctx := context.Background()
+
+	if err := store.SetCurrentQuestion(ctx, ChatKey(7), QuestionRef{Slug: "two-sum", Title: "Two Sum"}); err != nil {
+		t.Fatalf("set current question: %v", err)
+	}
+
+	settings, err := store.GetChatSettings(ctx, ChatKey(7))
+	if err != nil {
+		t.Fatalf("get chat settings: %v", err)
+	}
+	if settings.DailyTime != "20:00" || settings.Timezone != "Asia/Singapore" {
+		t.Fatalf("expected defaults on partial chat, got time=%q tz=%q", settings.DailyTime, settings.Timezone)
+	}
+	if settings.CurrentQuestion == nil || settings.CurrentQuestion.Slug != "two-sum" {
+		t.Fatalf("expected current question to round-trip, got %+v", settings.CurrentQuestion)
+	}
+
+	settings.CurrentQuestion.Slug = "mutated"
+	again, _ := store.GetChatSettings(ctx, ChatKey(7))
+	if again.CurrentQuestion.Slug != "two-sum" {
+		t.Fatalf("expected returned settings to be a copy, got %q", again.CurrentQuestion.Slug)
+	}
+}
+
+func TestMemoryStoreMarkQuestionAnsweredKeepsFirstAnsweredAt(t *testing.T) {
+	store := NewMemoryStore("20:00", "Asia/Singapore")
+	first := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+	store.nowFn = func() time.Time { return first }
+
+	ctx := context.Background()
+	q := QuestionRef{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy"}
+	if err := store.MarkQuestionAnswered(ctx, ChatKey(1), q); err != nil {
+		t.Fatalf("mark answered: %v", err)
+	}
+
+	second := first.Add(48 * time.Hour)
+	store.nowFn = func() time.Time { return second }
+	if err := store.MarkQuestionAnswered(ctx, ChatKey(1), q); err != nil {
+		t.Fatalf("mark answered again: %v", err)
+	}
+
+	items, err := store.ListAnsweredQuestions(ctx, ChatKey(1), 10)
+	if err != nil {
+		t.Fatalf("list answered: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 answered question, got %d", len(items))
+	}
+	if !items[0].FirstAnsweredAt.Equal(first) || !items[0].LastAnsweredAt.Equal(second) {
+		t.Fatalf("unexpected timestamps: first=%v last=%v", items[0].FirstAnsweredAt, items[0].LastAnsweredAt)
+	}
+	if items[0].Attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", items[0].Attempts)
+	}
+}
+
+func TestMemoryStoreConcurrentWrites(t *testing.T) {
+	store := NewMemoryStore("20:00", "Asia/Singapore")
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = store.MarkQuestionAnswered(ctx, ChatKey(1), QuestionRef{Slug: "two-sum"})
+			_ = store.AddServedQuestion(ctx, ChatKey(1), QuestionRef{Slug: "two-sum"})
+			_, _ = store.SeenQuestionSet(ctx, ChatKey(1))
+			_ = store.UpsertDailySettings(ctx, ChatKey(int64(i)), i%2 == 0, "", "")
+			_, _ = store.ListDailyEnabledChats(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	items, _ := store.ListAnsweredQuestions(ctx, ChatKey(1), 10)
+	if len(items) != 1 || items[0].Attempts != 32 {
+		t.Fatalf("expected 32 attempts on a single answered question, got %+v", items)
+	}
+	chats, _ := store.ListDailyEnabledChats(ctx)
+	if len(chats) != 15 {
+		t.Fatalf("expected 15 daily-enabled chats (even ids except 0), got %d", len(chats))
+	}
+}
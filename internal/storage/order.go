@@ -0,0 +1,64 @@
+package storage
+
+import "sort"
+
+// sortAnsweredByRecency orders answered questions by LastAnsweredAt, newest
+// first, with slug as a stable tie-break.
+func sortAnsweredByRecency(items []AnsweredQuestion) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].LastAnsweredAt.Equal(items[j].LastAnsweredAt) {
+			return items[i].Slug < items[j].Slug
+		}
+		return items[i].LastAnsweredAt.After(items[j].LastAnsweredAt)
+	})
+}
+
+// clampAnsweredLimit applies the default and cap shared by answered listings.
+func clampAnsweredLimit(limit int) int {
+	if limit <= 0 {
+		return 10
+	}
+	if limit > maxAnsweredListResults {
+		return maxAnsweredListResults
+	}
+	return limit
+}
+
+// sortAnswerAttempts orders attempts newest first and trims to limit. A
+// non-positive limit keeps every attempt.
+func sortAnswerAttempts(items []AnswerAttempt, limit int) []AnswerAttempt {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// sortGroupSolves orders solves oldest first, matching the Firestore query.
+func sortGroupSolves(items []GroupSolve) []GroupSolve {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].SolvedAt.Before(items[j].SolvedAt)
+	})
+	return items
+}
+
+// sortQuestionLists orders lists by name, matching the Firestore query.
+func sortQuestionLists(items []QuestionList) []QuestionList {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+// sortChatSettings orders settings by chat, with the chat-wide entry before
+// its members.
+func sortChatSettings(items []ChatSettings) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ChatID != items[j].ChatID {
+			return items[i].ChatID < items[j].ChatID
+		}
+		return items[i].UserID < items[j].UserID
+	})
+}
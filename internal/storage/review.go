@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"sort"
+	"time"
+)
+
+// ReviewState is the spaced-repetition schedule kept with an answered
+// question.
+type ReviewState struct {
+	EaseFactor   float64
+	IntervalDays int
+	Repetitions  int
+	DueAt        time.Time
+}
+
+// unscheduledReviewDelay is when a question answered without a graded review
+// (via /done, or before scheduling existed) first comes due.
+const unscheduledReviewDelay = 24 * time.Hour
+
+func (q AnsweredQuestion) reviewState() ReviewState {
+	return ReviewState{
+		EaseFactor:   q.EaseFactor,
+		IntervalDays: q.IntervalDays,
+		Repetitions:  q.Repetitions,
+		DueAt:        q.DueAt,
+	}
+}
+
+func (q *AnsweredQuestion) setReviewState(state ReviewState) {
+	q.EaseFactor = state.EaseFactor
+	q.IntervalDays = state.IntervalDays
+	q.Repetitions = state.Repetitions
+	q.DueAt = state.DueAt.UTC()
+}
+
+func withReviewDefaults(item AnsweredQuestion) AnsweredQuestion {
+	if item.DueAt.IsZero() && !item.LastAnsweredAt.IsZero() {
+		item.DueAt = item.LastAnsweredAt.Add(unscheduledReviewDelay)
+	}
+	return item
+}
+
+// sortReviewQueue orders items by due date (earliest first) and trims to
+// limit.
+func sortReviewQueue(items []AnsweredQuestion, limit int) []AnsweredQuestion {
+	limit = clampAnsweredLimit(limit)
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].DueAt.Equal(items[j].DueAt) {
+			return items[i].Slug < items[j].Slug
+		}
+		return items[i].DueAt.Before(items[j].DueAt)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,912 @@
+// Package storagetest provides a conformance suite for bot.StateStore
+// implementations. Every backend should pass Run so that behavior stays
+// identical regardless of which store the bot is deployed with.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"telegram-leetcode-bot/internal/bot"
+)
+
+// Defaults that factories must configure on the store under test.
+const (
+	DefaultDailyTime = "20:00"
+	DefaultTimezone  = "Asia/Singapore"
+)
+
+// writeGap separates writes whose relative ordering is asserted, so that
+// backends relying on wall-clock or server timestamps produce distinct values.
+const writeGap = 15 * time.Millisecond
+
+// Factory returns an empty store configured with DefaultDailyTime and
+// DefaultTimezone. It is called once per subtest.
+type Factory func(t *testing.T) bot.StateStore
+
+// Run executes the full conformance suite against stores built by newStore.
+func Run(t *testing.T, newStore Factory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, store bot.StateStore)
+	}{
+		{"ChatSettingsDefaults", testChatSettingsDefaults},
+		{"UpsertDailySettings", testUpsertDailySettings},
+		{"CurrentQuestionLifecycle", testCurrentQuestionLifecycle},
+		{"MarkDailySent", testMarkDailySent},
+		{"MockSessionLifecycle", testMockSessionLifecycle},
+		{"HintLevel", testHintLevel},
+		{"TutorThread", testTutorThread},
+		{"MarkWeeklySummarySent", testMarkWeeklySummarySent},
+		{"DifficultyPreference", testDifficultyPreference},
+		{"DailyMode", testDailyMode},
+		{"StudyPlan", testStudyPlan},
+		{"MarkQuestionAnsweredIncrementsAttempts", testMarkQuestionAnsweredIncrementsAttempts},
+		{"MarkQuestionAnsweredRejectsEmptySlug", testMarkQuestionAnsweredRejectsEmptySlug},
+		{"ListAnsweredQuestionsOrderAndLimit", testListAnsweredQuestionsOrderAndLimit},
+		{"ListAllAnsweredQuestions", testListAllAnsweredQuestions},
+		{"AnsweredQuestionNotFound", testAnsweredQuestionNotFound},
+		{"DeleteAnsweredQuestion", testDeleteAnsweredQuestion},
+		{"ReviewStateRoundTrip", testReviewStateRoundTrip},
+		{"ReviewQueueOrder", testReviewQueueOrder},
+		{"AnswerAttempts", testAnswerAttempts},
+		{"GroupSolves", testGroupSolves},
+		{"InterviewSessions", testInterviewSessions},
+		{"QuestionLists", testQuestionLists},
+		{"FormattedPrompts", testFormattedPrompts},
+		{"ServedQuestions", testServedQuestions},
+		{"ListDailyEnabledChats", testListDailyEnabledChats},
+		{"ChatIsolation", testChatIsolation},
+		{"GroupMemberIsolation", testGroupMemberIsolation},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, newStore(t))
+		})
+	}
+}
+
+func testChatSettingsDefaults(t *testing.T, store bot.StateStore) {
+	settings, err := store.GetChatSettings(context.Background(), bot.ChatKey(1001))
+	if err != nil {
+		t.Fatalf("GetChatSettings: %v", err)
+	}
+	if settings.ChatID != 1001 {
+		t.Fatalf("ChatID = %d, want 1001", settings.ChatID)
+	}
+	if settings.DailyEnabled {
+		t.Fatalf("DailyEnabled = true for unknown chat")
+	}
+	if settings.DailyTime != DefaultDailyTime || settings.Timezone != DefaultTimezone {
+		t.Fatalf("defaults = (%q, %q), want (%q, %q)", settings.DailyTime, settings.Timezone, DefaultDailyTime, DefaultTimezone)
+	}
+	if settings.CurrentQuestion != nil {
+		t.Fatalf("CurrentQuestion = %+v for unknown chat", settings.CurrentQuestion)
+	}
+}
+
+func testUpsertDailySettings(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(1002), true, "07:45", "America/New_York"))
+
+	settings := mustSettings(t, store, bot.ChatKey(1002))
+	if !settings.DailyEnabled || settings.DailyTime != "07:45" || settings.Timezone != "America/New_York" {
+		t.Fatalf("unexpected settings after upsert: %+v", settings)
+	}
+
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(1002), false, "", ""))
+	settings = mustSettings(t, store, bot.ChatKey(1002))
+	if settings.DailyEnabled {
+		t.Fatalf("DailyEnabled = true after disabling")
+	}
+	if settings.DailyTime != DefaultDailyTime || settings.Timezone != DefaultTimezone {
+		t.Fatalf("empty upsert values should fall back to defaults, got (%q, %q)", settings.DailyTime, settings.Timezone)
+	}
+}
+
+func testCurrentQuestionLifecycle(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	q := twoSum()
+
+	mustNoErr(t, store.SetCurrentQuestion(ctx, bot.ChatKey(1003), q))
+	settings := mustSettings(t, store, bot.ChatKey(1003))
+	if settings.CurrentQuestion == nil || !sameQuestion(*settings.CurrentQuestion, q) {
+		t.Fatalf("CurrentQuestion = %+v, want %+v", settings.CurrentQuestion, q)
+	}
+	if settings.DailyTime != DefaultDailyTime || settings.Timezone != DefaultTimezone {
+		t.Fatalf("chat created by SetCurrentQuestion should report defaults, got (%q, %q)", settings.DailyTime, settings.Timezone)
+	}
+
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(1003), true, "09:00", DefaultTimezone))
+	settings = mustSettings(t, store, bot.ChatKey(1003))
+	if settings.CurrentQuestion == nil {
+		t.Fatalf("UpsertDailySettings must not clear the current question")
+	}
+
+	mustNoErr(t, store.ClearCurrentQuestion(ctx, bot.ChatKey(1003)))
+	settings = mustSettings(t, store, bot.ChatKey(1003))
+	if settings.CurrentQuestion != nil {
+		t.Fatalf("CurrentQuestion = %+v after clear", settings.CurrentQuestion)
+	}
+	if !settings.DailyEnabled || settings.DailyTime != "09:00" {
+		t.Fatalf("ClearCurrentQuestion must not touch daily settings, got %+v", settings)
+	}
+
+	mustNoErr(t, store.ClearCurrentQuestion(ctx, bot.ChatKey(1099)))
+}
+
+func testMarkDailySent(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(1004), true, "20:00", DefaultTimezone))
+	mustNoErr(t, store.MarkDailySent(ctx, bot.ChatKey(1004), "2026-02-14"))
+
+	settings := mustSettings(t, store, bot.ChatKey(1004))
+	if settings.LastDailySentOn != "2026-02-14" {
+		t.Fatalf("LastDailySentOn = %q, want 2026-02-14", settings.LastDailySentOn)
+	}
+	if !settings.DailyEnabled {
+		t.Fatalf("MarkDailySent must not disable daily delivery")
+	}
+}
+
+func testMockSessionLifecycle(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	solo := bot.ChatKey(1028)
+	member := bot.StateKey{ChatID: -1028, UserID: 7}
+	started := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+
+	mustNoErr(t, store.SetCurrentQuestion(ctx, solo, twoSum()))
+	mustNoErr(t, store.SetMockSession(ctx, solo, bot.MockSession{Slug: "two-sum", StartedAt: started, Minutes: 30}))
+	mustNoErr(t, store.SetMockSession(ctx, member, bot.MockSession{Slug: "merge-intervals", StartedAt: started, Minutes: 45, WarnedHalfway: true}))
+
+	got := mustSettings(t, store, solo)
+	if got.Mock == nil || *got.Mock != (bot.MockSession{Slug: "two-sum", StartedAt: started, Minutes: 30}) {
+		t.Fatalf("mock session = %+v, want two-sum for 30 minutes", got.Mock)
+	}
+	if got.CurrentQuestion == nil || got.CurrentQuestion.Slug != "two-sum" {
+		t.Fatalf("SetMockSession must keep the current question, got %+v", got.CurrentQuestion)
+	}
+
+	sessions, err := store.ListMockSessions(ctx)
+	mustNoErr(t, err)
+	if len(sessions) != 2 || sessions[0].Key() != member || sessions[1].Key() != solo {
+		t.Fatalf("ListMockSessions = %+v, want the member then the private chat", sessions)
+	}
+	if !sessions[0].Mock.WarnedHalfway || sessions[0].Mock.WarnedFinal {
+		t.Fatalf("warning flags not kept: %+v", sessions[0].Mock)
+	}
+
+	mustNoErr(t, store.ClearMockSession(ctx, solo))
+	if got := mustSettings(t, store, solo); got.Mock != nil || got.CurrentQuestion == nil {
+		t.Fatalf("ClearMockSession must only drop the session, got %+v", got)
+	}
+	sessions, err = store.ListMockSessions(ctx)
+	mustNoErr(t, err)
+	if len(sessions) != 1 || sessions[0].Key() != member {
+		t.Fatalf("ListMockSessions after clear = %+v", sessions)
+	}
+}
+
+func testHintLevel(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	key := bot.ChatKey(1031)
+
+	mustNoErr(t, store.SetCurrentQuestion(ctx, key, twoSum()))
+	mustNoErr(t, store.SetHintLevel(ctx, key, "two-sum", 2))
+	got := mustSettings(t, store, key)
+	if got.HintLevel != 2 || got.HintSlug != "two-sum" || got.CurrentQuestion == nil {
+		t.Fatalf("after SetHintLevel got %+v, want level 2 on the current question", got)
+	}
+
+	mustNoErr(t, store.SetCurrentQuestion(ctx, key, mergeIntervals()))
+	if got := mustSettings(t, store, key); got.HintLevel != 0 || got.HintSlug != "" {
+		t.Fatalf("hint level %d for %q after a new question, want 0", got.HintLevel, got.HintSlug)
+	}
+
+	mustNoErr(t, store.SetHintLevel(ctx, key, "merge-intervals", 1))
+	mustNoErr(t, store.ClearCurrentQuestion(ctx, key))
+	if got := mustSettings(t, store, key).HintLevel; got != 0 {
+		t.Fatalf("HintLevel = %d after clearing the question, want 0", got)
+	}
+}
+
+func testTutorThread(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	key := bot.StateKey{ChatID: -1035, UserID: 7}
+	at := time.Date(2026, 2, 16, 9, 30, 0, 0, time.UTC)
+
+	mustNoErr(t, store.SetCurrentQuestion(ctx, key, twoSum()))
+	thread := bot.TutorThread{Slug: "two-sum", Turns: []bot.TutorTurn{
+		{Kind: bot.TutorTurnAnswer, Text: "hash map of complements", At: at},
+		{Kind: bot.TutorTurnReview, Text: "Score 8/10: handles duplicates?", At: at.Add(time.Minute)},
+	}}
+	mustNoErr(t, store.SetTutorThread(ctx, key, thread))
+	got := mustSettings(t, store, key).Tutor
+	if got == nil || got.Slug != "two-sum" || len(got.Turns) != 2 {
+		t.Fatalf("Tutor = %+v, want the saved two-turn thread", got)
+	}
+	if got.Turns[1].Kind != bot.TutorTurnReview || got.Turns[1].Text != thread.Turns[1].Text || !got.Turns[1].At.Equal(thread.Turns[1].At) {
+		t.Fatalf("second turn = %+v, want %+v", got.Turns[1], thread.Turns[1])
+	}
+	if mustSettings(t, store, bot.ChatKey(-1035)).Tutor != nil {
+		t.Fatal("a member's thread leaked into the group settings")
+	}
+
+	mustNoErr(t, store.SetCurrentQuestion(ctx, key, mergeIntervals()))
+	if got := mustSettings(t, store, key).Tutor; got != nil {
+		t.Fatalf("Tutor = %+v after a new question, want nil", got)
+	}
+
+	mustNoErr(t, store.SetTutorThread(ctx, key, bot.TutorThread{Slug: "merge-intervals", Turns: thread.Turns[:1]}))
+	mustNoErr(t, store.ClearCurrentQuestion(ctx, key))
+	if got := mustSettings(t, store, key).Tutor; got != nil {
+		t.Fatalf("Tutor = %+v after clearing the question, want nil", got)
+	}
+}
+
+func testMarkWeeklySummarySent(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(-1025), true, "20:00", DefaultTimezone))
+	mustNoErr(t, store.MarkDailySent(ctx, bot.ChatKey(-1025), "2026-02-16"))
+	mustNoErr(t, store.MarkWeeklySummarySent(ctx, bot.ChatKey(-1025), "2026-W07"))
+
+	settings := mustSettings(t, store, bot.ChatKey(-1025))
+	if settings.LastWeeklySummaryOn != "2026-W07" {
+		t.Fatalf("LastWeeklySummaryOn = %q, want 2026-W07", settings.LastWeeklySummaryOn)
+	}
+	if settings.LastDailySentOn != "2026-02-16" || !settings.DailyEnabled {
+		t.Fatalf("MarkWeeklySummarySent must not touch the daily schedule: %+v", settings)
+	}
+}
+
+func testDifficultyPreference(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	if got := mustSettings(t, store, bot.ChatKey(1017)).Difficulty; got != "" {
+		t.Fatalf("Difficulty = %q for unknown chat, want empty", got)
+	}
+
+	mustNoErr(t, store.SetDifficultyPreference(ctx, bot.ChatKey(1017), "Medium"))
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(1017), true, "08:00", DefaultTimezone))
+	if got := mustSettings(t, store, bot.ChatKey(1017)).Difficulty; got != "Medium" {
+		t.Fatalf("Difficulty = %q after upsert, want Medium", got)
+	}
+
+	chats, err := store.ListDailyEnabledChats(ctx)
+	mustNoErr(t, err)
+	if len(chats) != 1 || chats[0].Difficulty != "Medium" {
+		t.Fatalf("ListDailyEnabledChats should carry the difficulty preference, got %+v", chats)
+	}
+
+	mustNoErr(t, store.SetDifficultyPreference(ctx, bot.ChatKey(1017), ""))
+	if got := mustSettings(t, store, bot.ChatKey(1017)).Difficulty; got != "" {
+		t.Fatalf("Difficulty = %q after reset, want empty", got)
+	}
+}
+
+func testDailyMode(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	if got := mustSettings(t, store, bot.ChatKey(1020)).DailyMode; got != "" {
+		t.Fatalf("DailyMode = %q for unknown chat, want empty", got)
+	}
+
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(1020), true, "08:00", DefaultTimezone))
+	mustNoErr(t, store.SetDailyMode(ctx, bot.ChatKey(1020), bot.DailyModeMixed))
+	mustNoErr(t, store.MarkDailySent(ctx, bot.ChatKey(1020), "2026-03-01"))
+
+	chats, err := store.ListDailyEnabledChats(ctx)
+	mustNoErr(t, err)
+	if len(chats) != 1 || chats[0].DailyMode != bot.DailyModeMixed {
+		t.Fatalf("ListDailyEnabledChats should carry the daily mode, got %+v", chats)
+	}
+	if settings := mustSettings(t, store, bot.ChatKey(1020)); !settings.DailyEnabled || settings.DailyTime != "08:00" {
+		t.Fatalf("SetDailyMode must not touch the schedule, got %+v", settings)
+	}
+}
+
+func testStudyPlan(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	key := bot.ChatKey(1032)
+	if got := mustSettings(t, store, key).StudyPlan; got != "" {
+		t.Fatalf("StudyPlan = %q for unknown chat, want empty", got)
+	}
+
+	mustNoErr(t, store.SetStudyPlan(ctx, key, "blind75"))
+	mustNoErr(t, store.SetCurrentQuestion(ctx, key, twoSum()))
+	mustNoErr(t, store.ClearCurrentQuestion(ctx, key))
+	if got := mustSettings(t, store, key).StudyPlan; got != "blind75" {
+		t.Fatalf("StudyPlan = %q after a question round trip, want blind75", got)
+	}
+
+	mustNoErr(t, store.SetStudyPlan(ctx, key, ""))
+	if got := mustSettings(t, store, key).StudyPlan; got != "" {
+		t.Fatalf("StudyPlan = %q after reset, want empty", got)
+	}
+}
+
+func testMarkQuestionAnsweredIncrementsAttempts(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	q := twoSum()
+
+	mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1005), q))
+	first := mustAnswered(t, store, bot.ChatKey(1005), 10)
+	if len(first) != 1 || first[0].Attempts != 1 {
+		t.Fatalf("after first answer got %+v, want one item with 1 attempt", first)
+	}
+	if first[0].FirstAnsweredAt.IsZero() || first[0].LastAnsweredAt.IsZero() {
+		t.Fatalf("answer timestamps must be set, got %+v", first[0])
+	}
+
+	time.Sleep(writeGap)
+	q.Title = "Two Sum (renamed)"
+	mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1005), q))
+
+	second := mustAnswered(t, store, bot.ChatKey(1005), 10)
+	if len(second) != 1 {
+		t.Fatalf("repeated answers must not duplicate entries, got %d", len(second))
+	}
+	if second[0].Attempts != 2 {
+		t.Fatalf("Attempts = %d, want 2", second[0].Attempts)
+	}
+	if !second[0].FirstAnsweredAt.Equal(first[0].FirstAnsweredAt) {
+		t.Fatalf("FirstAnsweredAt changed from %v to %v", first[0].FirstAnsweredAt, second[0].FirstAnsweredAt)
+	}
+	if !second[0].LastAnsweredAt.After(first[0].LastAnsweredAt) {
+		t.Fatalf("LastAnsweredAt did not advance: %v -> %v", first[0].LastAnsweredAt, second[0].LastAnsweredAt)
+	}
+	if second[0].Title != "Two Sum (renamed)" {
+		t.Fatalf("question metadata should be refreshed, got title %q", second[0].Title)
+	}
+}
+
+func testMarkQuestionAnsweredRejectsEmptySlug(t *testing.T, store bot.StateStore) {
+	if err := store.MarkQuestionAnswered(context.Background(), bot.ChatKey(1006), bot.Question{Title: "No slug"}); err == nil {
+		t.Fatalf("expected error for empty slug")
+	}
+}
+
+func testListAnsweredQuestionsOrderAndLimit(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	slugs := make([]string, 0, 12)
+	for i := 0; i < 12; i++ {
+		slug := fmt.Sprintf("question-%02d", i)
+		slugs = append(slugs, slug)
+		mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1007), bot.Question{Slug: slug, Title: slug, Difficulty: "Easy"}))
+		time.Sleep(writeGap)
+	}
+
+	// Re-answering the oldest question moves it to the front.
+	mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1007), bot.Question{Slug: slugs[0], Title: slugs[0], Difficulty: "Easy"}))
+
+	items := mustAnswered(t, store, bot.ChatKey(1007), 3)
+	if len(items) != 3 {
+		t.Fatalf("limit 3 returned %d items", len(items))
+	}
+	want := []string{slugs[0], slugs[11], slugs[10]}
+	for i, item := range items {
+		if item.Slug != want[i] {
+			t.Fatalf("ListAnsweredQuestions[%d] = %q, want order %v", i, item.Slug, want)
+		}
+	}
+
+	if items := mustAnswered(t, store, bot.ChatKey(1007), 0); len(items) != 10 {
+		t.Fatalf("non-positive limit should default to 10, got %d", len(items))
+	}
+	if items := mustAnswered(t, store, bot.ChatKey(1007), 500); len(items) != 12 {
+		t.Fatalf("limit above the cap should return everything available, got %d", len(items))
+	}
+}
+
+func testListAllAnsweredQuestions(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	const total = 55 // above the ListAnsweredQuestions cap of 50
+	for i := 0; i < total; i++ {
+		slug := fmt.Sprintf("question-%02d", i)
+		mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1023), bot.Question{Slug: slug, Title: slug, Difficulty: "Medium"}))
+	}
+
+	items, err := store.ListAllAnsweredQuestions(ctx, bot.ChatKey(1023))
+	mustNoErr(t, err)
+	if len(items) != total {
+		t.Fatalf("ListAllAnsweredQuestions returned %d items, want %d", len(items), total)
+	}
+	for i := 1; i < len(items); i++ {
+		if items[i].LastAnsweredAt.After(items[i-1].LastAnsweredAt) {
+			t.Fatalf("ListAllAnsweredQuestions must be newest first, got %v before %v", items[i-1].LastAnsweredAt, items[i].LastAnsweredAt)
+		}
+	}
+}
+
+func testAnsweredQuestionNotFound(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	if _, err := store.GetAnsweredQuestion(ctx, bot.ChatKey(1008), "missing"); !errors.Is(err, bot.ErrAnsweredQuestionNotFound) {
+		t.Fatalf("GetAnsweredQuestion(missing) error = %v, want ErrAnsweredQuestionNotFound", err)
+	}
+
+	q := twoSum()
+	mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1008), q))
+	got, err := store.GetAnsweredQuestion(ctx, bot.ChatKey(1008), q.Slug)
+	if err != nil {
+		t.Fatalf("GetAnsweredQuestion: %v", err)
+	}
+	if !sameQuestion(got, q) {
+		t.Fatalf("GetAnsweredQuestion = %+v, want %+v", got, q)
+	}
+}
+
+func testDeleteAnsweredQuestion(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	if err := store.DeleteAnsweredQuestion(ctx, bot.ChatKey(1009), "missing"); !errors.Is(err, bot.ErrAnsweredQuestionNotFound) {
+		t.Fatalf("DeleteAnsweredQuestion(missing) error = %v, want ErrAnsweredQuestionNotFound", err)
+	}
+
+	q := twoSum()
+	mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1009), q))
+	mustNoErr(t, store.DeleteAnsweredQuestion(ctx, bot.ChatKey(1009), q.Slug))
+	if items := mustAnswered(t, store, bot.ChatKey(1009), 10); len(items) != 0 {
+		t.Fatalf("expected empty history after delete, got %+v", items)
+	}
+	if err := store.DeleteAnsweredQuestion(ctx, bot.ChatKey(1009), q.Slug); !errors.Is(err, bot.ErrAnsweredQuestionNotFound) {
+		t.Fatalf("second delete error = %v, want ErrAnsweredQuestionNotFound", err)
+	}
+}
+
+func testReviewStateRoundTrip(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	if _, err := store.GetReviewState(ctx, bot.ChatKey(1018), "missing"); !errors.Is(err, bot.ErrAnsweredQuestionNotFound) {
+		t.Fatalf("GetReviewState(missing) error = %v, want ErrAnsweredQuestionNotFound", err)
+	}
+	if err := store.UpdateReviewState(ctx, bot.ChatKey(1018), "missing", bot.ReviewState{IntervalDays: 1}); !errors.Is(err, bot.ErrAnsweredQuestionNotFound) {
+		t.Fatalf("UpdateReviewState(missing) error = %v, want ErrAnsweredQuestionNotFound", err)
+	}
+
+	q := twoSum()
+	mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1018), q))
+	initial, err := store.GetReviewState(ctx, bot.ChatKey(1018), q.Slug)
+	mustNoErr(t, err)
+	answered := mustAnswered(t, store, bot.ChatKey(1018), 1)
+	if !initial.DueAt.Equal(answered[0].LastAnsweredAt.Add(24 * time.Hour)) {
+		t.Fatalf("ungraded question should be due a day after its last answer, got %v (last answered %v)", initial.DueAt, answered[0].LastAnsweredAt)
+	}
+
+	want := bot.ReviewState{
+		EaseFactor:   2.36,
+		IntervalDays: 6,
+		Repetitions:  2,
+		DueAt:        time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC),
+	}
+	mustNoErr(t, store.UpdateReviewState(ctx, bot.ChatKey(1018), q.Slug, want))
+
+	// Answering again must not reset the schedule.
+	mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1018), q))
+	got, err := store.GetReviewState(ctx, bot.ChatKey(1018), q.Slug)
+	mustNoErr(t, err)
+	if got.EaseFactor != want.EaseFactor || got.IntervalDays != want.IntervalDays || got.Repetitions != want.Repetitions || !got.DueAt.Equal(want.DueAt) {
+		t.Fatalf("GetReviewState = %+v, want %+v", got, want)
+	}
+	if review := mustAnswered(t, store, bot.ChatKey(1018), 1)[0].Review; review.IntervalDays != want.IntervalDays || !review.DueAt.Equal(want.DueAt) {
+		t.Fatalf("ListAnsweredQuestions should carry the review state, got %+v", review)
+	}
+}
+
+func testReviewQueueOrder(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, slug := range []string{"later", "soonest", "middle"} {
+		mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1019), bot.Question{Slug: slug, Title: slug, Difficulty: "Easy"}))
+		offsets := []time.Duration{72 * time.Hour, 0, 24 * time.Hour}
+		mustNoErr(t, store.UpdateReviewState(ctx, bot.ChatKey(1019), slug, bot.ReviewState{EaseFactor: 2.5, IntervalDays: i + 1, Repetitions: 1, DueAt: base.Add(offsets[i])}))
+	}
+
+	queue, err := store.ListReviewQueue(ctx, bot.ChatKey(1019), 2)
+	mustNoErr(t, err)
+	if len(queue) != 2 || queue[0].Slug != "soonest" || queue[1].Slug != "middle" {
+		t.Fatalf("ListReviewQueue(limit 2) = %+v, want soonest then middle", queue)
+	}
+	if !queue[0].Review.DueAt.Equal(base) {
+		t.Fatalf("queue head DueAt = %v, want %v", queue[0].Review.DueAt, base)
+	}
+
+	empty, err := store.ListReviewQueue(ctx, bot.ChatKey(1099), 10)
+	mustNoErr(t, err)
+	if len(empty) != 0 {
+		t.Fatalf("ListReviewQueue for unknown chat = %+v, want empty", empty)
+	}
+}
+
+func testAnswerAttempts(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	if err := store.RecordAnswerAttempt(ctx, bot.ChatKey(1021), bot.AnswerAttempt{Answer: "no slug"}); err == nil {
+		t.Fatalf("expected error for empty slug")
+	}
+
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	attempts := []bot.AnswerAttempt{
+		{Slug: "two-sum", Answer: "nested loops", Score: 4, Source: "Heuristic", Feedback: "Too slow.", CreatedAt: base},
+		{Slug: "merge-intervals", Answer: "sort then merge", Score: 9, Source: "AI", Feedback: "Good.", CreatedAt: base.Add(time.Minute)},
+		{Slug: "two-sum", Answer: "hash map of complements", Score: 9, Source: "AI", Feedback: "Optimal.", CreatedAt: base.Add(2 * time.Minute)},
+	}
+	for _, attempt := range attempts {
+		mustNoErr(t, store.RecordAnswerAttempt(ctx, bot.ChatKey(1021), attempt))
+	}
+
+	all, err := store.ListAnswerAttempts(ctx, bot.ChatKey(1021), "", 0)
+	mustNoErr(t, err)
+	if len(all) != 3 {
+		t.Fatalf("ListAnswerAttempts(all) returned %d items, want 3", len(all))
+	}
+	if all[0] != attempts[2] || all[2] != attempts[0] {
+		t.Fatalf("attempts should be newest first with all fields kept, got %+v", all)
+	}
+
+	limited, err := store.ListAnswerAttempts(ctx, bot.ChatKey(1021), "", 2)
+	mustNoErr(t, err)
+	if len(limited) != 2 || limited[1] != attempts[1] {
+		t.Fatalf("ListAnswerAttempts(limit 2) = %+v", limited)
+	}
+
+	twoSum, err := store.ListAnswerAttempts(ctx, bot.ChatKey(1021), "two-sum", 0)
+	mustNoErr(t, err)
+	if len(twoSum) != 2 || twoSum[0].Answer != "hash map of complements" || twoSum[1].Answer != "nested loops" {
+		t.Fatalf("ListAnswerAttempts(two-sum) = %+v", twoSum)
+	}
+
+	other, err := store.ListAnswerAttempts(ctx, bot.ChatKey(1022), "", 0)
+	mustNoErr(t, err)
+	if len(other) != 0 {
+		t.Fatalf("attempts leaked across chats: %+v", other)
+	}
+}
+
+func testGroupSolves(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	if err := store.RecordGroupSolve(ctx, -1026, bot.GroupSolve{UserID: 1}); err == nil {
+		t.Fatalf("expected error for empty slug")
+	}
+
+	base := time.Date(2026, 2, 9, 9, 0, 0, 0, time.UTC)
+	solves := []bot.GroupSolve{
+		{UserID: 2, Name: "Bob", Slug: "merge-intervals", Difficulty: "Medium", Score: 8, SolvedAt: base.Add(48 * time.Hour)},
+		{UserID: 1, Name: "Alice", Slug: "two-sum", Difficulty: "Easy", Score: 9, SolvedAt: base},
+		{UserID: 1, Name: "Alice", Slug: "trapping-rain-water", Difficulty: "Hard", Score: 7, SolvedAt: base.Add(7 * 24 * time.Hour)},
+	}
+	for _, solve := range solves {
+		mustNoErr(t, store.RecordGroupSolve(ctx, -1026, solve))
+	}
+
+	all, err := store.ListGroupSolves(ctx, -1026, time.Time{})
+	mustNoErr(t, err)
+	if len(all) != 3 || all[0] != solves[1] || all[1] != solves[0] || all[2] != solves[2] {
+		t.Fatalf("ListGroupSolves(all) = %+v, want oldest first with all fields kept", all)
+	}
+
+	recent, err := store.ListGroupSolves(ctx, -1026, base.Add(48*time.Hour))
+	mustNoErr(t, err)
+	if len(recent) != 2 || recent[0].Slug != "merge-intervals" || recent[1].Slug != "trapping-rain-water" {
+		t.Fatalf("ListGroupSolves(since) = %+v", recent)
+	}
+
+	other, err := store.ListGroupSolves(ctx, -1027, time.Time{})
+	mustNoErr(t, err)
+	if len(other) != 0 {
+		t.Fatalf("solves leaked across groups: %+v", other)
+	}
+
+	mustNoErr(t, store.RecordGroupSolve(ctx, -1036, bot.GroupSolve{UserID: 3, Slug: "two-sum", SolvedAt: base}))
+	active, err := store.ListActiveGroups(ctx, base.Add(24*time.Hour))
+	mustNoErr(t, err)
+	if len(active) != 1 || active[0].ChatID != -1026 || active[0].Timezone != DefaultTimezone || active[0].DailyEnabled {
+		t.Fatalf("ListActiveGroups = %+v, want only the group with a recent solve", active)
+	}
+	active, err = store.ListActiveGroups(ctx, base.Add(8*24*time.Hour))
+	mustNoErr(t, err)
+	if len(active) != 0 {
+		t.Fatalf("ListActiveGroups after the last solve = %+v, want none", active)
+	}
+}
+
+func testInterviewSessions(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	key := bot.ChatKey(1029)
+	if _, err := store.LatestInterviewSession(ctx, key); !errors.Is(err, bot.ErrInterviewSessionNotFound) {
+		t.Fatalf("expected ErrInterviewSessionNotFound, got %v", err)
+	}
+	if err := store.SaveInterviewSession(ctx, key, bot.InterviewSession{}); err == nil {
+		t.Fatalf("expected error for empty session id")
+	}
+
+	started := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+	first := bot.InterviewSession{
+		ID:         "first",
+		Difficulty: "Medium",
+		Questions:  []bot.SessionQuestion{{Question: twoSum(), Outcome: bot.SessionOutcomeSolved, BestScore: 9, Attempts: 2}},
+		Current:    1,
+		StartedAt:  started,
+		FinishedAt: started.Add(20 * time.Minute),
+	}
+	second := bot.InterviewSession{
+		ID:        "second",
+		Questions: []bot.SessionQuestion{{Question: mergeIntervals()}, {Question: twoSum()}},
+		StartedAt: started.Add(time.Hour),
+	}
+	mustNoErr(t, store.SaveInterviewSession(ctx, key, second))
+	mustNoErr(t, store.SaveInterviewSession(ctx, key, first))
+
+	latest, err := store.LatestInterviewSession(ctx, key)
+	mustNoErr(t, err)
+	if latest.ID != "second" || len(latest.Questions) != 2 || !latest.FinishedAt.IsZero() {
+		t.Fatalf("latest session = %+v, want the most recently started", latest)
+	}
+
+	second.Questions[0].Outcome, second.Questions[0].BestScore, second.Questions[0].Attempts = bot.SessionOutcomeSkipped, 4, 1
+	second.Current = 1
+	mustNoErr(t, store.SaveInterviewSession(ctx, key, second))
+	latest, err = store.LatestInterviewSession(ctx, key)
+	mustNoErr(t, err)
+	got := latest.Questions[0]
+	if latest.Current != 1 || !sameQuestion(got.Question, mergeIntervals()) || got.Outcome != bot.SessionOutcomeSkipped || got.BestScore != 4 || got.Attempts != 1 {
+		t.Fatalf("saving an existing id must replace it, got %+v", latest)
+	}
+
+	if _, err := store.LatestInterviewSession(ctx, bot.ChatKey(1030)); !errors.Is(err, bot.ErrInterviewSessionNotFound) {
+		t.Fatalf("sessions leaked across chats: %v", err)
+	}
+}
+
+func testQuestionLists(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	chatID := int64(1033)
+	if _, err := store.GetQuestionList(ctx, chatID, "week-1"); !errors.Is(err, bot.ErrQuestionListNotFound) {
+		t.Fatalf("expected ErrQuestionListNotFound, got %v", err)
+	}
+	if err := store.SaveQuestionList(ctx, chatID, bot.QuestionList{}); err == nil {
+		t.Fatalf("expected error for empty list name")
+	}
+
+	created := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	mustNoErr(t, store.SaveQuestionList(ctx, chatID, bot.QuestionList{Name: "week-2", CreatedAt: created, UpdatedAt: created}))
+	mustNoErr(t, store.SaveQuestionList(ctx, chatID, bot.QuestionList{
+		Name:      "week-1",
+		Questions: []bot.Question{mergeIntervals(), twoSum()},
+		CreatedAt: created,
+		UpdatedAt: created,
+	}))
+
+	got, err := store.GetQuestionList(ctx, chatID, "week-1")
+	mustNoErr(t, err)
+	if len(got.Questions) != 2 || !sameQuestion(got.Questions[0], mergeIntervals()) || !sameQuestion(got.Questions[1], twoSum()) {
+		t.Fatalf("questions = %+v, want merge-intervals then two-sum", got.Questions)
+	}
+	if !got.CreatedAt.Equal(created) {
+		t.Fatalf("CreatedAt = %v, want %v", got.CreatedAt, created)
+	}
+
+	lists, err := store.ListQuestionLists(ctx, chatID)
+	mustNoErr(t, err)
+	if len(lists) != 2 || lists[0].Name != "week-1" || lists[1].Name != "week-2" {
+		t.Fatalf("lists = %+v, want week-1 and week-2 by name", lists)
+	}
+
+	got.Questions = got.Questions[1:]
+	got.UpdatedAt = created.Add(time.Hour)
+	mustNoErr(t, store.SaveQuestionList(ctx, chatID, got))
+	got, err = store.GetQuestionList(ctx, chatID, "week-1")
+	mustNoErr(t, err)
+	if len(got.Questions) != 1 || !sameQuestion(got.Questions[0], twoSum()) || !got.UpdatedAt.Equal(created.Add(time.Hour)) {
+		t.Fatalf("saving an existing name must replace it, got %+v", got)
+	}
+
+	if lists, err := store.ListQuestionLists(ctx, chatID+1); err != nil || len(lists) != 0 {
+		t.Fatalf("lists leaked across chats: %+v, %v", lists, err)
+	}
+}
+
+func testFormattedPrompts(t *testing.T, store bot.StateStore) {
+	prompts, ok := store.(bot.FormattedPromptStore)
+	if !ok {
+		t.Fatalf("store does not implement bot.FormattedPromptStore")
+	}
+	ctx := context.Background()
+	if _, err := prompts.GetFormattedPrompt(ctx, "two-sum-abc"); !errors.Is(err, bot.ErrFormattedPromptNotFound) {
+		t.Fatalf("expected ErrFormattedPromptNotFound, got %v", err)
+	}
+	if err := prompts.SaveFormattedPrompt(ctx, bot.FormattedPrompt{Text: "x"}); err == nil {
+		t.Fatalf("expected error for empty key")
+	}
+
+	created := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	want := bot.FormattedPrompt{Key: "two-sum-abc", Slug: "two-sum", Model: "openai/gpt-4o-mini", Text: "## Two Sum", CreatedAt: created}
+	mustNoErr(t, prompts.SaveFormattedPrompt(ctx, want))
+	got, err := prompts.GetFormattedPrompt(ctx, "two-sum-abc")
+	mustNoErr(t, err)
+	if got.Slug != want.Slug || got.Model != want.Model || got.Text != want.Text || !got.CreatedAt.Equal(created) {
+		t.Fatalf("prompt = %+v, want %+v", got, want)
+	}
+
+	want.Text = "## Two Sum (v2)"
+	mustNoErr(t, prompts.SaveFormattedPrompt(ctx, want))
+	if got, err := prompts.GetFormattedPrompt(ctx, "two-sum-abc"); err != nil || got.Text != want.Text {
+		t.Fatalf("saving an existing key must replace it, got %+v, %v", got, err)
+	}
+}
+
+func testServedQuestions(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	mustNoErr(t, store.AddServedQuestion(ctx, bot.ChatKey(1010), twoSum()))
+	mustNoErr(t, store.AddServedQuestion(ctx, bot.ChatKey(1010), mergeIntervals()))
+	mustNoErr(t, store.AddServedQuestion(ctx, bot.ChatKey(1010), twoSum()))
+
+	seen := mustSeen(t, store, bot.ChatKey(1010))
+	if len(seen) != 2 {
+		t.Fatalf("seen = %v, want two-sum and merge-intervals", seen)
+	}
+
+	mustNoErr(t, store.RemoveServedQuestion(ctx, bot.ChatKey(1010), "two-sum"))
+	mustNoErr(t, store.RemoveServedQuestion(ctx, bot.ChatKey(1010), "never-served"))
+	seen = mustSeen(t, store, bot.ChatKey(1010))
+	if _, ok := seen["two-sum"]; ok || len(seen) != 1 {
+		t.Fatalf("seen after remove = %v, want only merge-intervals", seen)
+	}
+
+	mustNoErr(t, store.ResetServedQuestions(ctx, bot.ChatKey(1010)))
+	if seen := mustSeen(t, store, bot.ChatKey(1010)); len(seen) != 0 {
+		t.Fatalf("seen after reset = %v, want empty", seen)
+	}
+	mustNoErr(t, store.ResetServedQuestions(ctx, bot.ChatKey(1010)))
+
+	// Served questions can be added again after a reset.
+	mustNoErr(t, store.AddServedQuestion(ctx, bot.ChatKey(1010), twoSum()))
+	if seen := mustSeen(t, store, bot.ChatKey(1010)); len(seen) != 1 {
+		t.Fatalf("seen after re-add = %v, want two-sum", seen)
+	}
+}
+
+func testListDailyEnabledChats(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(1011), true, "08:00", "Europe/London"))
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(1012), false, "09:00", DefaultTimezone))
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(1013), true, "", ""))
+	mustNoErr(t, store.SetCurrentQuestion(ctx, bot.ChatKey(1014), twoSum()))
+	// 10110 sorts before 1013 as a string, so key order alone is not enough.
+	mustNoErr(t, store.UpsertDailySettings(ctx, bot.ChatKey(10110), true, "", ""))
+
+	chats, err := store.ListDailyEnabledChats(ctx)
+	if err != nil {
+		t.Fatalf("ListDailyEnabledChats: %v", err)
+	}
+
+	ids := make([]int64, 0, len(chats))
+	byID := make(map[int64]bot.ChatSettings, len(chats))
+	for _, chat := range chats {
+		ids = append(ids, chat.ChatID)
+		byID[chat.ChatID] = chat
+	}
+	if !slices.Equal(ids, []int64{1011, 1013, 10110}) {
+		t.Fatalf("daily chat IDs = %v, want [1011 1013 10110] in order", ids)
+	}
+	if got := byID[1011]; got.DailyTime != "08:00" || got.Timezone != "Europe/London" {
+		t.Fatalf("chat 1011 = %+v", got)
+	}
+	if got := byID[1013]; got.DailyTime != DefaultDailyTime || got.Timezone != DefaultTimezone {
+		t.Fatalf("chat 1013 should carry defaults, got %+v", got)
+	}
+}
+
+func testChatIsolation(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	mustNoErr(t, store.SetCurrentQuestion(ctx, bot.ChatKey(1015), twoSum()))
+	mustNoErr(t, store.MarkQuestionAnswered(ctx, bot.ChatKey(1015), twoSum()))
+	mustNoErr(t, store.AddServedQuestion(ctx, bot.ChatKey(1015), twoSum()))
+	mustNoErr(t, store.AddServedQuestion(ctx, bot.ChatKey(1016), mergeIntervals()))
+
+	mustNoErr(t, store.ResetServedQuestions(ctx, bot.ChatKey(1016)))
+
+	if settings := mustSettings(t, store, bot.ChatKey(1016)); settings.CurrentQuestion != nil {
+		t.Fatalf("current question leaked across chats: %+v", settings.CurrentQuestion)
+	}
+	if items := mustAnswered(t, store, bot.ChatKey(1016), 10); len(items) != 0 {
+		t.Fatalf("answered history leaked across chats: %+v", items)
+	}
+	if seen := mustSeen(t, store, bot.ChatKey(1015)); len(seen) != 1 {
+		t.Fatalf("reset of one chat must not affect another, got %v", seen)
+	}
+}
+
+func testGroupMemberIsolation(t *testing.T, store bot.StateStore) {
+	ctx := context.Background()
+	group := bot.ChatKey(1024)
+	alice := bot.StateKey{ChatID: 1024, UserID: 1}
+	bob := bot.StateKey{ChatID: 1024, UserID: 2}
+
+	mustNoErr(t, store.UpsertDailySettings(ctx, group, true, "08:00", DefaultTimezone))
+	mustNoErr(t, store.SetCurrentQuestion(ctx, group, mergeIntervals()))
+	mustNoErr(t, store.SetCurrentQuestion(ctx, alice, twoSum()))
+	mustNoErr(t, store.MarkQuestionAnswered(ctx, alice, twoSum()))
+	mustNoErr(t, store.AddServedQuestion(ctx, alice, twoSum()))
+	mustNoErr(t, store.UpsertDailySettings(ctx, bob, true, "09:00", DefaultTimezone))
+
+	if got := mustSettings(t, store, alice); got.ChatID != 1024 || got.CurrentQuestion == nil || got.CurrentQuestion.Slug != "two-sum" {
+		t.Fatalf("member settings = %+v, want chat 1024 with two-sum", got)
+	}
+	if got := mustSettings(t, store, group).CurrentQuestion; got == nil || got.Slug != "merge-intervals" {
+		t.Fatalf("group current question = %+v, want merge-intervals", got)
+	}
+	if got := mustSettings(t, store, bob).CurrentQuestion; got != nil {
+		t.Fatalf("current question leaked between members: %+v", got)
+	}
+	if items := mustAnswered(t, store, group, 10); len(items) != 0 {
+		t.Fatalf("member history leaked into the group: %+v", items)
+	}
+	if seen := mustSeen(t, store, bob); len(seen) != 0 {
+		t.Fatalf("seen set leaked between members: %v", seen)
+	}
+
+	mustNoErr(t, store.DismissQuestion(ctx, bob, "merge-intervals"))
+	if got := mustSettings(t, store, bob); got.DismissedSlug != "merge-intervals" || got.DailyTime != "09:00" {
+		t.Fatalf("member settings after DismissQuestion = %+v", got)
+	}
+	if got := mustSettings(t, store, group).DismissedSlug; got != "" {
+		t.Fatalf("dismissal leaked into the group: %q", got)
+	}
+
+	chats, err := store.ListDailyEnabledChats(ctx)
+	mustNoErr(t, err)
+	if len(chats) != 1 || chats[0].ChatID != 1024 {
+		t.Fatalf("daily chats = %+v, want only the group itself", chats)
+	}
+}
+
+func twoSum() bot.Question {
+	return bot.Question{Slug: "two-sum", Title: "Two Sum", Difficulty: "Easy", URL: "https://leetcode.com/problems/two-sum/"}
+}
+
+func mergeIntervals() bot.Question {
+	return bot.Question{Slug: "merge-intervals", Title: "Merge Intervals", Difficulty: "Medium", URL: "https://leetcode.com/problems/merge-intervals/"}
+}
+
+// sameQuestion compares the persisted question fields; provider-only fields
+// such as Tags are not stored.
+func sameQuestion(a, b bot.Question) bool {
+	return a.Slug == b.Slug && a.Title == b.Title && a.Difficulty == b.Difficulty && a.URL == b.URL
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func mustSettings(t *testing.T, store bot.StateStore, key bot.StateKey) bot.ChatSettings {
+	t.Helper()
+	settings, err := store.GetChatSettings(context.Background(), key)
+	if err != nil {
+		t.Fatalf("GetChatSettings(%s): %v", key, err)
+	}
+	return settings
+}
+
+func mustAnswered(t *testing.T, store bot.StateStore, key bot.StateKey, limit int) []bot.AnsweredQuestion {
+	t.Helper()
+	items, err := store.ListAnsweredQuestions(context.Background(), key, limit)
+	if err != nil {
+		t.Fatalf("ListAnsweredQuestions(%s): %v", key, err)
+	}
+	return items
+}
+
+func mustSeen(t *testing.T, store bot.StateStore, key bot.StateKey) map[string]struct{} {
+	t.Helper()
+	seen, err := store.SeenQuestionSet(context.Background(), key)
+	if err != nil {
+		t.Fatalf("SeenQuestionSet(%s): %v", key, err)
+	}
+	return seen
+}
@@ -11,21 +11,28 @@ import (
 	"time"
 )
 
+// pollRequestSlack is added on top of the long-poll timeout so that the HTTP
+// request outlives Telegram holding the connection open.
+const pollRequestSlack = 10 * time.Second
+
 type Client struct {
 	httpClient *http.Client
+	pollClient *http.Client
 	baseURL    string
 }
 
 func NewClient(token string) *Client {
 	return &Client{
 		httpClient: &http.Client{Timeout: 15 * time.Second},
+		pollClient: &http.Client{},
 		baseURL:    fmt.Sprintf("https://api.telegram.org/bot%s", token),
 	}
 }
 
 type apiResponse struct {
-	OK          bool   `json:"ok"`
-	Description string `json:"description"`
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
 }
 
 func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
@@ -46,6 +53,41 @@ func (c *Client) SendRichMessage(ctx context.Context, chatID int64, text string)
 	return c.postJSON(ctx, "/sendMessage", payload)
 }
 
+func (c *Client) SendRichMessageWithKeyboard(ctx context.Context, chatID int64, text string, keyboard InlineKeyboardMarkup) error {
+	payload := map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "MarkdownV2",
+		"disable_web_page_preview": true,
+	}
+	if len(keyboard.InlineKeyboard) > 0 {
+		payload["reply_markup"] = keyboard
+	}
+	return c.postJSON(ctx, "/sendMessage", payload)
+}
+
+// AnswerCallbackQuery acknowledges an inline button press so the client stops
+// showing a loading indicator. text is shown as a short toast when non-empty.
+func (c *Client) AnswerCallbackQuery(ctx context.Context, callbackQueryID, text string) error {
+	payload := map[string]any{
+		"callback_query_id": callbackQueryID,
+	}
+	if text != "" {
+		payload["text"] = text
+	}
+	return c.postJSON(ctx, "/answerCallbackQuery", payload)
+}
+
+// GetMe returns the bot's own account, used to recognise commands and
+// mentions addressed to it in group chats.
+func (c *Client) GetMe(ctx context.Context) (User, error) {
+	var me User
+	if err := c.call(ctx, c.httpClient, "/getMe", map[string]any{}, &me); err != nil {
+		return User{}, err
+	}
+	return me, nil
+}
+
 func (c *Client) SetWebhook(ctx context.Context, webhookURL string) error {
 	payload := map[string]any{
 		"url": webhookURL,
@@ -53,7 +95,41 @@ func (c *Client) SetWebhook(ctx context.Context, webhookURL string) error {
 	return c.postJSON(ctx, "/setWebhook", payload)
 }
 
+func (c *Client) DeleteWebhook(ctx context.Context) error {
+	payload := map[string]any{
+		"drop_pending_updates": false,
+	}
+	return c.postJSON(ctx, "/deleteWebhook", payload)
+}
+
+// GetUpdates long-polls Telegram for updates with update_id >= offset,
+// holding the request open for up to timeout when none are pending.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	seconds := int(timeout / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+	payload := map[string]any{
+		"offset":          offset,
+		"timeout":         seconds,
+		"allowed_updates": []string{"message", "callback_query"},
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, timeout+pollRequestSlack)
+	defer cancel()
+
+	var updates []Update
+	if err := c.call(reqCtx, c.pollClient, "/getUpdates", payload, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
 func (c *Client) postJSON(ctx context.Context, path string, payload any) error {
+	return c.call(ctx, c.httpClient, path, payload, nil)
+}
+
+func (c *Client) call(ctx context.Context, httpClient *http.Client, path string, payload any, result any) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
@@ -65,7 +141,7 @@ func (c *Client) postJSON(ctx context.Context, path string, payload any) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("telegram request failed: %w", err)
 	}
@@ -87,6 +163,11 @@ func (c *Client) postJSON(ctx context.Context, path string, payload any) error {
 	if !out.OK {
 		return fmt.Errorf("telegram api error: %s", out.Description)
 	}
+	if result != nil && len(out.Result) > 0 {
+		if err := json.Unmarshal(out.Result, result); err != nil {
+			return fmt.Errorf("unmarshal telegram result: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -105,8 +186,25 @@ func BuildWebhookURL(baseURL, secret string) (string, error) {
 
 // Telegram update models.
 type Update struct {
-	UpdateID int64    `json:"update_id"`
-	Message  *Message `json:"message"`
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message"`
+	CallbackQuery *CallbackQuery `json:"callback_query"`
+}
+
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message"`
+	Data    string   `json:"data"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
 }
 
 type Message struct {
@@ -114,10 +212,13 @@ type Message struct {
 	Text      string `json:"text"`
 	From      User   `json:"from"`
 	Chat      Chat   `json:"chat"`
+	// ReplyToMessage is set when the message replies to another one.
+	ReplyToMessage *Message `json:"reply_to_message"`
 }
 
 type User struct {
 	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
 	FirstName string `json:"first_name"`
 	Username  string `json:"username"`
 }
@@ -0,0 +1,109 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	pollRetryMinDelay = time.Second
+	pollRetryMaxDelay = 30 * time.Second
+	pollHandleTimeout = 60 * time.Second
+)
+
+// UpdateHandler processes a single Telegram update.
+type UpdateHandler func(ctx context.Context, update Update)
+
+// UpdateSource is the subset of Client used by Poller.
+type UpdateSource interface {
+	GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error)
+}
+
+// Poller receives updates through getUpdates long polling instead of a
+// webhook. It tracks the offset itself so each update is handled once.
+type Poller struct {
+	source  UpdateSource
+	handler UpdateHandler
+	timeout time.Duration
+	logger  *log.Logger
+	offset  int64
+
+	minRetryDelay time.Duration
+}
+
+func NewPoller(source UpdateSource, timeout time.Duration, handler UpdateHandler, logger *log.Logger) *Poller {
+	if timeout < 0 {
+		timeout = 0
+	}
+	return &Poller{
+		source:  source,
+		handler: handler,
+		timeout: timeout,
+		logger:  logger,
+
+		minRetryDelay: pollRetryMinDelay,
+	}
+}
+
+// Run polls until ctx is cancelled. Updates already fetched when ctx is
+// cancelled are still handled, each with its own bounded context, so a
+// shutdown does not drop acknowledged updates.
+func (p *Poller) Run(ctx context.Context) error {
+	delay := p.minRetryDelay
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		updates, err := p.source.GetUpdates(ctx, p.offset, p.timeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			p.logger.Printf("get updates failed, retrying in %s: %v", delay, err)
+			if !sleepContext(ctx, delay) {
+				return ctx.Err()
+			}
+			delay = min(delay*2, pollRetryMaxDelay)
+			continue
+		}
+		delay = p.minRetryDelay
+
+		for _, update := range updates {
+			if update.UpdateID >= p.offset {
+				p.offset = update.UpdateID + 1
+			}
+			p.handle(ctx, update)
+		}
+	}
+}
+
+// Offset returns the next update_id the poller will request.
+func (p *Poller) Offset() int64 {
+	return p.offset
+}
+
+func (p *Poller) handle(ctx context.Context, update Update) {
+	handleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pollHandleTimeout)
+	defer cancel()
+	p.handler(handleCtx, update)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// IsPollingStopped reports whether err is the expected result of Run after
+// its context was cancelled.
+func IsPollingStopped(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,131 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeUpdateSource struct {
+	mu      sync.Mutex
+	batches [][]Update
+	offsets []int64
+	cancel  context.CancelFunc
+	errOnce error
+}
+
+func (f *fakeUpdateSource) GetUpdates(ctx context.Context, offset int64, _ time.Duration) ([]Update, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.offsets = append(f.offsets, offset)
+	if f.errOnce != nil {
+		err := f.errOnce
+		f.errOnce = nil
+		return nil, err
+	}
+	if len(f.batches) == 0 {
+		f.cancel()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	batch := f.batches[0]
+	f.batches = f.batches[1:]
+	return batch, nil
+}
+
+func TestPollerTracksOffsetAndStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := &fakeUpdateSource{
+		cancel: cancel,
+		batches: [][]Update{
+			{{UpdateID: 10, Message: &Message{Text: "/lc"}}, {UpdateID: 11, Message: &Message{Text: "/hint"}}},
+			{{UpdateID: 12, Message: &Message{Text: "/done"}}},
+		},
+	}
+
+	var handled []string
+	poller := NewPoller(source, time.Second, func(ctx context.Context, update Update) {
+		if ctx.Err() != nil {
+			t.Errorf("handler context already done for update %d", update.UpdateID)
+		}
+		handled = append(handled, update.Message.Text)
+	}, log.New(bytes.NewBuffer(nil), "", 0))
+
+	err := poller.Run(ctx)
+	if !IsPollingStopped(err) {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
+
+	if len(handled) != 3 || handled[0] != "/lc" || handled[2] != "/done" {
+		t.Fatalf("unexpected handled updates: %v", handled)
+	}
+	wantOffsets := []int64{0, 12, 13}
+	if len(source.offsets) != len(wantOffsets) {
+		t.Fatalf("offsets = %v, want %v", source.offsets, wantOffsets)
+	}
+	for i, want := range wantOffsets {
+		if source.offsets[i] != want {
+			t.Fatalf("offsets = %v, want %v", source.offsets, wantOffsets)
+		}
+	}
+	if poller.Offset() != 13 {
+		t.Fatalf("Offset() = %d, want 13", poller.Offset())
+	}
+}
+
+func TestPollerRetriesAfterError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := &fakeUpdateSource{
+		cancel:  cancel,
+		errOnce: errors.New("temporary network failure"),
+		batches: [][]Update{{{UpdateID: 5, Message: &Message{Text: "hello"}}}},
+	}
+
+	handled := 0
+	poller := NewPoller(source, time.Second, func(context.Context, Update) { handled++ }, log.New(bytes.NewBuffer(nil), "", 0))
+	poller.minRetryDelay = time.Millisecond
+	if err := poller.Run(ctx); !IsPollingStopped(err) {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
+	if handled != 1 {
+		t.Fatalf("expected update to be handled after retry, got %d", handled)
+	}
+}
+
+func TestClientGetUpdatesDecodesResult(t *testing.T) {
+	var gotPayload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/getUpdates" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&gotPayload)
+		_, _ = w.Write([]byte(`{"ok":true,"result":[{"update_id":7,"message":{"message_id":1,"text":"/lc","chat":{"id":42,"type":"private"}}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.baseURL = server.URL
+
+	updates, err := client.GetUpdates(context.Background(), 7, 25*time.Second)
+	if err != nil {
+		t.Fatalf("GetUpdates: %v", err)
+	}
+	if len(updates) != 1 || updates[0].UpdateID != 7 || updates[0].Message.Chat.ID != 42 {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+	if gotPayload["offset"] != float64(7) || gotPayload["timeout"] != float64(25) {
+		t.Fatalf("unexpected request payload: %v", gotPayload)
+	}
+}